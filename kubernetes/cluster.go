@@ -0,0 +1,260 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-provider-kubernetes/util"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// clusterSchema returns the schema of the "cluster" block, which makes a resource or data source
+// target another cluster than the one of the provider configuration.
+// On resources, changing the context or the host of the cluster replaces the resource,
+// while its credentials can change without affecting the resource.
+func clusterSchema(isResource bool) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		MaxItems:    1,
+		Optional:    true,
+		Description: "The cluster to manage the object in, instead of the one of the provider configuration.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"config_context": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					ForceNew:    isResource,
+					Description: "Name of a context of the kube config of the provider configuration, set with `config_path`, `config_paths` or `config_raw`.",
+				},
+				"host": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					ForceNew:    isResource,
+					Description: "The hostname (in form of URI) of the Kubernetes API server of the cluster.",
+				},
+				"insecure": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Description: "Whether server should be accessed without verifying the TLS certificate.",
+				},
+				"tls_server_name": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Server name passed to the server for SNI and is used in the client to check server certificates against.",
+				},
+				"cluster_ca_certificate": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "PEM-encoded root certificates bundle for TLS authentication.",
+				},
+				"client_certificate": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "PEM-encoded client certificate for TLS authentication.",
+				},
+				"client_key": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Sensitive:   true,
+					Description: "PEM-encoded client certificate key for TLS authentication.",
+				},
+				"token": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Sensitive:   true,
+					Description: "Token to authenticate a service account.",
+				},
+				"proxy_url": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "URL to the proxy to be used for all API requests to the cluster.",
+				},
+				"exec": {
+					Type:        schema.TypeList,
+					Optional:    true,
+					MaxItems:    1,
+					Description: "Run a command to get the credentials of the cluster.",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"api_version": {
+								Type:     schema.TypeString,
+								Required: true,
+							},
+							"command": {
+								Type:     schema.TypeString,
+								Required: true,
+							},
+							"env": {
+								Type:     schema.TypeMap,
+								Optional: true,
+								Elem:     &schema.Schema{Type: schema.TypeString},
+							},
+							"args": {
+								Type:     schema.TypeList,
+								Optional: true,
+								Elem:     &schema.Schema{Type: schema.TypeString},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// withClusterBlock adds the "cluster" block to a resource or data source, and wraps its functions
+// so that they are passed the clients of that cluster instead of the ones of the provider configuration.
+// Resources are imported from the cluster of the context prefixed to the import ID as "<context>//<id>",
+// or from the cluster of the provider configuration.
+func withClusterBlock(r *schema.Resource, isResource bool) {
+	r.Schema["cluster"] = clusterSchema(isResource)
+
+	read := r.ReadContext
+	r.ReadContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		m, err := clusterClientsets(d, meta)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		return read(ctx, d, m)
+	}
+
+	if !isResource {
+		return
+	}
+
+	if create := r.CreateContext; create != nil {
+		r.CreateContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			m, err := clusterClientsets(d, meta)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			return create(ctx, d, m)
+		}
+	}
+
+	update := r.UpdateContext
+	r.UpdateContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		m, err := clusterClientsets(d, meta)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		// only the credentials of the cluster changed
+		if update == nil || !d.HasChangesExcept("cluster") {
+			return read(ctx, d, m)
+		}
+		return update(ctx, d, m)
+	}
+
+	if del := r.DeleteContext; del != nil {
+		r.DeleteContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			m, err := clusterClientsets(d, meta)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			return del(ctx, d, m)
+		}
+	}
+
+	if r.Importer != nil && r.Importer.StateContext != nil {
+		importState := r.Importer.StateContext
+		r.Importer = &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				// the context is kept in the state, so that the resource is then read from its cluster
+				if configContext, id := util.ParseClusterImportID(d.Id()); configContext != "" {
+					d.SetId(id)
+					cluster := []interface{}{map[string]interface{}{"config_context": configContext}}
+					if err := d.Set("cluster", cluster); err != nil {
+						return nil, err
+					}
+				}
+				m, err := clusterClientsets(d, meta)
+				if err != nil {
+					return nil, err
+				}
+				return importState(ctx, d, m)
+			},
+		}
+	}
+
+	if customizeDiff := r.CustomizeDiff; customizeDiff != nil {
+		r.CustomizeDiff = func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			if !d.NewValueKnown("cluster") {
+				// the cluster isn't known until apply, and the resource will be replaced anyway
+				return nil
+			}
+			m, err := clusterClientsets(d, meta)
+			if err != nil {
+				return err
+			}
+			return customizeDiff(ctx, d, m)
+		}
+	}
+}
+
+// clusterClientsets returns the clients of the cluster set in the "cluster" block of d,
+// or the ones of the provider configuration when the block is not set.
+func clusterClientsets(d interface{ Get(string) interface{} }, meta interface{}) (interface{}, error) {
+	v, ok := d.Get("cluster").([]interface{})
+	if !ok || len(v) == 0 {
+		return meta, nil
+	}
+	spec, ok := v[0].(map[string]interface{})
+	if !ok {
+		return meta, nil
+	}
+	return meta.(KubeClientsets).ClusterClientsets(spec)
+}
+
+// expandClusterOverrides returns the overrides of the client configuration set in a "cluster" block
+func expandClusterOverrides(cluster map[string]interface{}) (*clientcmd.ConfigOverrides, error) {
+	overrides := &clientcmd.ConfigOverrides{}
+
+	if v, ok := cluster["config_context"].(string); ok && v != "" {
+		overrides.CurrentContext = v
+	}
+	if v, ok := cluster["insecure"].(bool); ok {
+		overrides.ClusterInfo.InsecureSkipTLSVerify = v
+	}
+	if v, ok := cluster["tls_server_name"].(string); ok && v != "" {
+		overrides.ClusterInfo.TLSServerName = v
+	}
+	if v, ok := cluster["cluster_ca_certificate"].(string); ok && v != "" {
+		overrides.ClusterInfo.CertificateAuthorityData = bytes.NewBufferString(v).Bytes()
+	}
+	if v, ok := cluster["client_certificate"].(string); ok && v != "" {
+		overrides.AuthInfo.ClientCertificateData = bytes.NewBufferString(v).Bytes()
+	}
+	if v, ok := cluster["client_key"].(string); ok && v != "" {
+		overrides.AuthInfo.ClientKeyData = bytes.NewBufferString(v).Bytes()
+	}
+	if v, ok := cluster["token"].(string); ok && v != "" {
+		overrides.AuthInfo.Token = v
+	}
+	if v, ok := cluster["proxy_url"].(string); ok && v != "" {
+		overrides.ClusterDefaults.ProxyURL = v
+	}
+	if v, ok := cluster["exec"].([]interface{}); ok && len(v) > 0 {
+		exec, err := expandExecConfig(v)
+		if err != nil {
+			return nil, err
+		}
+		overrides.AuthInfo.Exec = exec
+	}
+	if v, ok := cluster["host"].(string); ok && v != "" {
+		host, err := defaultServerURL(v, overrides)
+		if err != nil {
+			return nil, err
+		}
+		overrides.ClusterInfo.Server = host
+	}
+	if overrides.CurrentContext == "" && overrides.ClusterInfo.Server == "" {
+		return nil, fmt.Errorf("Failed to configure cluster: either config_context or host must be set")
+	}
+	return overrides, nil
+}
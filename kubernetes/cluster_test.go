@@ -0,0 +1,98 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	restclient "k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestWithClusterBlock(t *testing.T) {
+	ctx := context.TODO()
+	var got interface{}
+	record := func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		got = meta
+		return nil
+	}
+	r := &schema.Resource{
+		CreateContext: record,
+		ReadContext:   record,
+		DeleteContext: record,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				got = meta
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+		},
+	}
+	withClusterBlock(r, true)
+	if err := r.InternalValidate(nil, true); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &kubeClientsets{config: &restclient.Config{}, kubeconfig: kubeconfig{raw: &clientcmdapi.Config{
+		Clusters:  map[string]*clientcmdapi.Cluster{"staging": {Server: "https://10.0.0.2:6443"}},
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{"staging": {Token: "test"}},
+		Contexts:  map[string]*clientcmdapi.Context{"staging": {Cluster: "staging", AuthInfo: "staging"}},
+	}}}
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"name": "test"})
+	if diags := r.ReadContext(ctx, d, m); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if got != m {
+		t.Fatal("expected the clients of the provider configuration without a cluster block")
+	}
+
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name": "test",
+		"cluster": []interface{}{map[string]interface{}{
+			"host":  "https://10.0.0.1:6443",
+			"token": "test",
+		}},
+	})
+	if diags := r.CreateContext(ctx, d, m); diags.HasError() {
+		t.Fatal(diags)
+	}
+	c, ok := got.(*kubeClientsets)
+	if !ok || c == m {
+		t.Fatalf("expected the clients of the cluster, got %#v", got)
+	}
+	if c.config.Host != "https://10.0.0.1:6443" {
+		t.Fatalf("unexpected host %q", c.config.Host)
+	}
+	if diags := r.UpdateContext(ctx, d, m); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if got != c {
+		t.Fatal("expected a change of the credentials of the cluster to refresh the resource")
+	}
+
+	d = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{})
+	d.SetId("staging//default/test")
+	imported, err := r.Importer.StateContext(ctx, d, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported[0].Id() != "default/test" {
+		t.Fatalf("expected the context to be removed from the ID, got %q", imported[0].Id())
+	}
+	if v := imported[0].Get("cluster.0.config_context"); v != "staging" {
+		t.Fatalf("expected the context to be kept in the cluster block, got %q", v)
+	}
+	if c, ok := got.(*kubeClientsets); !ok || c.config.Host != "https://10.0.0.2:6443" {
+		t.Fatalf("expected the resource to be imported from the cluster of the context, got %#v", got)
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-provider-kubernetes/util"
+)
+
+// providerServer is the gRPC server of the provider. Unlike the one of the SDK, it supports moving
+// the state of kubernetes_manifest resources to the typed resources of the kind of their object.
+type providerServer struct {
+	tfprotov5.ProviderServer
+	provider *schema.Provider
+}
+
+// NewProviderServer returns a function creating the gRPC server of the provider p, to be muxed with
+// the one of the manifest provider
+func NewProviderServer(p *schema.Provider) func() tfprotov5.ProviderServer {
+	return func() tfprotov5.ProviderServer {
+		return &providerServer{
+			ProviderServer: p.GRPCProvider(),
+			provider:       p,
+		}
+	}
+}
+
+func (s *providerServer) GetMetadata(ctx context.Context, req *tfprotov5.GetMetadataRequest) (*tfprotov5.GetMetadataResponse, error) {
+	resp, err := s.ProviderServer.GetMetadata(ctx, req)
+	if resp != nil && resp.ServerCapabilities != nil {
+		resp.ServerCapabilities.MoveResourceState = true
+	}
+	return resp, err
+}
+
+func (s *providerServer) GetProviderSchema(ctx context.Context, req *tfprotov5.GetProviderSchemaRequest) (*tfprotov5.GetProviderSchemaResponse, error) {
+	resp, err := s.ProviderServer.GetProviderSchema(ctx, req)
+	if resp != nil && resp.ServerCapabilities != nil {
+		resp.ServerCapabilities.MoveResourceState = true
+	}
+	return resp, err
+}
+
+// MoveResourceState moves the state of a kubernetes_manifest resource to a typed resource, such as
+// kubernetes_deployment_v1, by importing and reading the object of the manifest from its cluster.
+// The object itself is left untouched.
+func (s *providerServer) MoveResourceState(ctx context.Context, req *tfprotov5.MoveResourceStateRequest) (*tfprotov5.MoveResourceStateResponse, error) {
+	if req.SourceTypeName != "kubernetes_manifest" || !util.IsKubernetesProviderAddress(req.SourceProviderAddress) {
+		return s.ProviderServer.MoveResourceState(ctx, req)
+	}
+	resp := &tfprotov5.MoveResourceStateResponse{}
+
+	r, ok := s.provider.ResourcesMap[req.TargetTypeName]
+	gvk, supported := util.TypedResourceKind(req.TargetTypeName)
+	if !ok || !supported {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Move Resource State Not Supported",
+			Detail:   fmt.Sprintf("Resources of type kubernetes_manifest cannot be moved to the %q resource type.", req.TargetTypeName),
+		})
+		return resp, nil
+	}
+
+	src, err := parseManifestState(req.SourceState)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to read the state of the kubernetes_manifest resource",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	group := ""
+	if i := strings.LastIndex(src.Object.APIVersion, "/"); i >= 0 {
+		group = src.Object.APIVersion[:i]
+	}
+	if group != gvk.Group || src.Object.Kind != gvk.Kind {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Move Resource State Not Supported",
+			Detail:   fmt.Sprintf("The %q resource type manages objects of kind %s, not %s %s.", req.TargetTypeName, gvk.GroupKind(), src.Object.APIVersion, src.Object.Kind),
+		})
+		return resp, nil
+	}
+
+	// the object is read from the cluster of the "cluster" block of the manifest, which is kept
+	d := r.Data(nil)
+	d.SetId(util.TypedResourceID(src.Object.Metadata.Namespace, src.Object.Metadata.Name))
+	if len(src.Cluster) > 0 {
+		if err := d.Set("cluster", src.Cluster); err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Failed to set the cluster of the moved resource",
+				Detail:   err.Error(),
+			})
+			return resp, nil
+		}
+	}
+	imported, err := r.Importer.StateContext(ctx, d, s.provider.Meta())
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to import the object of the kubernetes_manifest resource",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	state, diags := r.RefreshWithoutUpgrade(ctx, imported[0].State(), s.provider.Meta())
+	if diags.HasError() {
+		for _, d := range diags {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  d.Summary,
+				Detail:   d.Detail,
+			})
+		}
+		return resp, nil
+	}
+	if state == nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Object not found",
+			Detail:   fmt.Sprintf("The %s %q of the kubernetes_manifest resource was not found in the cluster.", src.Object.Kind, d.Id()),
+		})
+		return resp, nil
+	}
+
+	ty := r.CoreConfigSchema().ImpliedType()
+	v, err := state.AttrsAsObjectValue(ty)
+	if err == nil {
+		resp.TargetPrivate, err = json.Marshal(state.Meta)
+	}
+	if err == nil {
+		var mp []byte
+		mp, err = msgpack.Marshal(v, ty)
+		resp.TargetState = &tfprotov5.DynamicValue{MsgPack: mp}
+	}
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to encode the moved state",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	return resp, nil
+}
+
+// manifestState holds the attributes of the state of a kubernetes_manifest resource needed to move it
+type manifestState struct {
+	Object struct {
+		APIVersion string `json:"apiVersion"`
+		Kind       string `json:"kind"`
+		Metadata   struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"metadata"`
+	}
+	Cluster []interface{}
+}
+
+// parseManifestState reads the object and the "cluster" block of the raw state of a kubernetes_manifest resource
+func parseManifestState(state *tfprotov5.RawState) (manifestState, error) {
+	var ms manifestState
+	if state == nil || state.JSON == nil {
+		return ms, fmt.Errorf("the state is empty")
+	}
+	var raw struct {
+		// "object" is a dynamic attribute, stored with its type
+		Object *struct {
+			Value json.RawMessage `json:"value"`
+		} `json:"object"`
+		Cluster []map[string]interface{} `json:"cluster"`
+	}
+	if err := json.Unmarshal(state.JSON, &raw); err != nil {
+		return ms, err
+	}
+	if raw.Object == nil || raw.Object.Value == nil {
+		return ms, fmt.Errorf("the state has no object")
+	}
+	if err := json.Unmarshal(raw.Object.Value, &ms.Object); err != nil {
+		return ms, err
+	}
+	if ms.Object.Kind == "" || ms.Object.Metadata.Name == "" {
+		return ms, fmt.Errorf("the object in the state has no kind or metadata.name")
+	}
+	for _, c := range raw.Cluster {
+		ms.Cluster = append(ms.Cluster, withoutNullValues(c))
+	}
+	return ms, nil
+}
+
+// withoutNullValues returns the attributes of a block decoded from JSON which are not null,
+// including the ones of its nested blocks
+func withoutNullValues(block map[string]interface{}) map[string]interface{} {
+	m := make(map[string]interface{}, len(block))
+	for k, v := range block {
+		switch v := v.(type) {
+		case nil:
+			continue
+		case []interface{}:
+			l := make([]interface{}, 0, len(v))
+			for _, e := range v {
+				if b, ok := e.(map[string]interface{}); ok {
+					l = append(l, withoutNullValues(b))
+				} else if e != nil {
+					l = append(l, e)
+				}
+			}
+			m[k] = l
+		default:
+			m[k] = v
+		}
+	}
+	return m
+}
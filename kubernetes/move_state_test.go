@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubernetes
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+)
+
+func TestParseManifestState(t *testing.T) {
+	state := &tfprotov5.RawState{JSON: []byte(`{
+		"manifest": null,
+		"object": {
+			"value": {"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "test", "namespace": "default", "labels": null}},
+			"type": ["object", {"apiVersion": "string", "kind": "string", "metadata": ["object", {"name": "string", "namespace": "string", "labels": ["map", "string"]}]}]
+		},
+		"cluster": [{"config_context": "other", "host": null, "exec": []}]
+	}`)}
+	ms, err := parseManifestState(state)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ms.Object.APIVersion != "apps/v1" || ms.Object.Kind != "Deployment" {
+		t.Errorf("unexpected kind: %s %s", ms.Object.APIVersion, ms.Object.Kind)
+	}
+	if ms.Object.Metadata.Name != "test" || ms.Object.Metadata.Namespace != "default" {
+		t.Errorf("unexpected object: %s/%s", ms.Object.Metadata.Namespace, ms.Object.Metadata.Name)
+	}
+	expected := []interface{}{map[string]interface{}{"config_context": "other", "exec": []interface{}{}}}
+	if !reflect.DeepEqual(ms.Cluster, expected) {
+		t.Errorf("expected cluster %#v, got %#v", expected, ms.Cluster)
+	}
+
+	for name, s := range map[string]string{
+		"without object": `{"manifest": null, "object": null}`,
+		"without name":   `{"object": {"value": {"apiVersion": "v1", "kind": "ConfigMap", "metadata": {}}, "type": "dynamic"}}`,
+	} {
+		if _, err := parseManifestState(&tfprotov5.RawState{JSON: []byte(s)}); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestMoveResourceStateUnsupported(t *testing.T) {
+	s := NewProviderServer(Provider())()
+	state := &tfprotov5.RawState{JSON: []byte(`{"object": {"value": {"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "test", "namespace": "default"}}, "type": "dynamic"}}`)}
+	for name, target := range map[string]string{
+		"untyped target": "kubernetes_labels",
+		"other kind":     "kubernetes_stateful_set_v1",
+	} {
+		resp, err := s.MoveResourceState(context.Background(), &tfprotov5.MoveResourceStateRequest{
+			SourceProviderAddress: "registry.terraform.io/hashicorp/kubernetes",
+			SourceTypeName:        "kubernetes_manifest",
+			SourceState:           state,
+			TargetTypeName:        target,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Summary != "Move Resource State Not Supported" {
+			t.Errorf("%s: expected the move to be rejected, got %v", name, resp.Diagnostics)
+		}
+	}
+}
@@ -6,12 +6,16 @@ package kubernetes
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/hashicorp/go-cty/cty"
 	gversion "github.com/hashicorp/go-version"
@@ -21,10 +25,14 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/hashicorp/terraform-provider-kubernetes/util"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
 
 	apimachineryschema "k8s.io/apimachinery/pkg/runtime/schema"
@@ -100,6 +108,39 @@ func Provider() *schema.Provider {
 				Description:   "Path to the kube config file. Can be set with KUBE_CONFIG_PATH.",
 				ConflictsWith: []string{"config_paths"},
 			},
+			"config_raw": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				Description:   "Content of a kube config file, e.g. read from a secret manager. Takes precedence over `config_path` and `config_paths`.",
+				ConflictsWith: []string{"config_path", "config_paths"},
+			},
+			"token_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "Path to a file with a token to authenticate a service account, re-read periodically to pick up rotated tokens.",
+				ConflictsWith: []string{"token"},
+			},
+			"client_certificate_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "Path to a PEM-encoded client certificate for TLS authentication, re-read when it changes.",
+				ConflictsWith: []string{"client_certificate"},
+				RequiredWith:  []string{"client_key_file"},
+			},
+			"client_key_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "Path to a PEM-encoded client certificate key for TLS authentication, re-read when it changes.",
+				ConflictsWith: []string{"client_key"},
+				RequiredWith:  []string{"client_certificate_file"},
+			},
+			"cluster_ca_certificate_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Description:   "Path to a PEM-encoded root certificates bundle for TLS authentication.",
+				ConflictsWith: []string{"cluster_ca_certificate"},
+			},
 			"config_context": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -129,6 +170,24 @@ func Provider() *schema.Provider {
 				Description: "URL to the proxy to be used for all API requests",
 				DefaultFunc: schema.EnvDefaultFunc("KUBE_PROXY_URL", ""),
 			},
+			"qps": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				ValidateFunc: validation.FloatAtLeast(0),
+				Description:  "Maximum number of queries per second sent to the Kubernetes API by each client of the provider. Defaults to 5.",
+			},
+			"burst": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum burst of queries sent to the Kubernetes API above the `qps` rate. Defaults to 10.",
+			},
+			"request_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateDuration,
+				Description:  "Maximum duration of a single request to the Kubernetes API, e.g. `30s`. Watch requests used to wait for resources are not limited. No timeout is set by default.",
+			},
 			"exec": {
 				Type:     schema.TypeList,
 				Optional: true,
@@ -168,6 +227,133 @@ func Provider() *schema.Provider {
 				},
 				Description: "",
 			},
+			"impersonate": {
+				Type:        schema.TypeList,
+				MaxItems:    1,
+				Optional:    true,
+				Description: "Impersonate a user, and optionally groups, in all requests to the Kubernetes API, as with the `--as` and `--as-group` flags of `kubectl`.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"user": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Name of the user to impersonate, e.g. `system:serviceaccount:tenant:deployer`.",
+						},
+						"uid": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "UID of the user to impersonate.",
+						},
+						"groups": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Groups to impersonate.",
+						},
+						"extra": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "Extra fields of the user to impersonate.",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"key": {
+										Type:        schema.TypeString,
+										Required:    true,
+										Description: "Name of the extra field, e.g. `scopes`.",
+									},
+									"values": {
+										Type:        schema.TypeList,
+										Required:    true,
+										Elem:        &schema.Schema{Type: schema.TypeString},
+										Description: "Values of the extra field.",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"retry": {
+				Type:        schema.TypeList,
+				MaxItems:    1,
+				Optional:    true,
+				Description: "Retry requests to the Kubernetes API which fail with a transient error, such as throttling or an etcd leader change.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"max_attempts": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(1),
+							Description:  "Maximum number of attempts of a request, including the first one. Defaults to 5.",
+						},
+						"min_backoff": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateDuration,
+							Description:  "Delay before the first retry, doubled on each following one, e.g. `500ms`. Defaults to `500ms`.",
+						},
+						"max_backoff": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateDuration,
+							Description:  "Maximum delay between retries, including delays requested by the server with a `Retry-After` header. Defaults to `30s`.",
+						},
+						"retryable_status_codes": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeInt, ValidateFunc: validation.IntBetween(400, 599)},
+							Description: "HTTP status codes of the responses to retry. Creations and patches are only retried on 429, and on 503 with a `Retry-After` header. Defaults to 429, 500, 502, 503 and 504.",
+						},
+						"retry_on_conflict": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Description: "Retry requests which conflict with a concurrent change of the resource, unless they set a `resourceVersion`. Conflicts with other field managers are never retried.",
+						},
+					},
+				},
+			},
+			"cache": {
+				Type:        schema.TypeList,
+				MaxItems:    1,
+				Optional:    true,
+				Description: "Cache the OpenAPI schema and API discovery data of the cluster on disk, for use by the `kubernetes_manifest` resources.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"directory": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Path to the directory where cached data is stored.",
+						},
+						"ttl": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Description:  "How long cached data is used before being refreshed from the cluster, e.g. `12h`. Defaults to `24h`.",
+							ValidateFunc: validateDuration,
+						},
+					},
+				},
+			},
+			"offline": {
+				Type:        schema.TypeList,
+				MaxItems:    1,
+				Optional:    true,
+				Description: "Plan `kubernetes_manifest` resources against a local OpenAPI spec and CRD files, without connecting to the cluster. Resources cannot be applied, read or imported in offline mode.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"openapi_spec_file": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Path to an OpenAPI v2 spec of the Kubernetes API, in JSON or YAML, as served by the API server at `/openapi/v2`.",
+						},
+						"crd_files": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Paths or glob patterns of YAML or JSON files with the CustomResourceDefinitions to plan custom resources against.",
+						},
+					},
+				},
+			},
 			"experiments": {
 				Type:        schema.TypeList,
 				MaxItems:    1,
@@ -359,6 +545,14 @@ func Provider() *schema.Provider {
 		},
 	}
 
+	// any resource or data source can target another cluster than the one of the provider configuration
+	for _, r := range p.ResourcesMap {
+		withClusterBlock(r, true)
+	}
+	for _, r := range p.DataSourcesMap {
+		withClusterBlock(r, false)
+	}
+
 	p.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 		return providerConfigure(ctx, d, p.TerraformVersion)
 	}
@@ -371,22 +565,41 @@ type KubeClientsets interface {
 	AggregatorClientset() (*aggregator.Clientset, error)
 	DynamicClient() (dynamic.Interface, error)
 	DiscoveryClient() (discovery.DiscoveryInterface, error)
+	RESTMapper() (meta.RESTMapper, error)
+	ClusterClientsets(cluster map[string]interface{}) (KubeClientsets, error)
 }
 
+// kubeClientsets creates the clients of the provider when they are first used, and shares them between
+// all resources and data sources, so that they also share HTTP connections and client-side rate limits.
 type kubeClientsets struct {
 	// TODO: this struct has become overloaded we should
 	// rename this or break it into smaller structs
-	config              *restclient.Config
+	config *restclient.Config
+
+	// mu guards the lazily created clients, as resources are handled concurrently
+	mu                  sync.Mutex
 	mainClientset       *kubernetes.Clientset
 	aggregatorClientset *aggregator.Clientset
 	dynamicClient       dynamic.Interface
-	discoveryClient     discovery.DiscoveryInterface
+	discoveryClient     discovery.CachedDiscoveryInterface
+	restMapper          meta.RESTMapper
+
+	// the clients of the clusters targeted by the cluster block of resources are created
+	// from the kube config and client settings of the provider configuration
+	kubeconfig    kubeconfig
+	userAgent     string
+	clientOptions util.ClientOptions
+	impersonate   clientcmdapi.AuthInfo
+	clusters      map[string]*kubeClientsets
 
 	IgnoreAnnotations []string
 	IgnoreLabels      []string
 }
 
-func (k kubeClientsets) MainClientset() (*kubernetes.Clientset, error) {
+func (k *kubeClientsets) MainClientset() (*kubernetes.Clientset, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
 	if k.mainClientset != nil {
 		return k.mainClientset, nil
 	}
@@ -401,7 +614,10 @@ func (k kubeClientsets) MainClientset() (*kubernetes.Clientset, error) {
 	return k.mainClientset, nil
 }
 
-func (k kubeClientsets) AggregatorClientset() (*aggregator.Clientset, error) {
+func (k *kubeClientsets) AggregatorClientset() (*aggregator.Clientset, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
 	if k.aggregatorClientset != nil {
 		return k.aggregatorClientset, nil
 	}
@@ -415,7 +631,10 @@ func (k kubeClientsets) AggregatorClientset() (*aggregator.Clientset, error) {
 	return k.aggregatorClientset, nil
 }
 
-func (k kubeClientsets) DynamicClient() (dynamic.Interface, error) {
+func (k *kubeClientsets) DynamicClient() (dynamic.Interface, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
 	if k.dynamicClient != nil {
 		return k.dynamicClient, nil
 	}
@@ -430,7 +649,20 @@ func (k kubeClientsets) DynamicClient() (dynamic.Interface, error) {
 	return k.dynamicClient, nil
 }
 
-func (k kubeClientsets) DiscoveryClient() (discovery.DiscoveryInterface, error) {
+// DiscoveryClient returns a discovery client which caches the API groups and resources of the cluster in memory
+func (k *kubeClientsets) DiscoveryClient() (discovery.DiscoveryInterface, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	dc, err := k.discoveryClientLocked()
+	if err != nil || dc == nil {
+		// don't return a typed nil
+		return nil, err
+	}
+	return dc, nil
+}
+
+func (k *kubeClientsets) discoveryClientLocked() (discovery.CachedDiscoveryInterface, error) {
 	if k.discoveryClient != nil {
 		return k.discoveryClient, nil
 	}
@@ -440,14 +672,97 @@ func (k kubeClientsets) DiscoveryClient() (discovery.DiscoveryInterface, error)
 		if err != nil {
 			return nil, fmt.Errorf("Failed to configure discovery client: %s", err)
 		}
-		k.discoveryClient = kc
+		k.discoveryClient = memory.NewMemCacheClient(kc)
 	}
 	return k.discoveryClient, nil
 }
 
+// RESTMapper returns a REST mapper backed by the cached discovery client. The cached data
+// is invalidated when a resource type cannot be found, e.g. because its CustomResourceDefinition
+// was created after the data was cached, and the lookup is retried once.
+func (k *kubeClientsets) RESTMapper() (meta.RESTMapper, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.restMapper != nil {
+		return k.restMapper, nil
+	}
+
+	dc, err := k.discoveryClientLocked()
+	if err != nil {
+		return nil, err
+	}
+	if dc == nil {
+		return nil, fmt.Errorf("Failed to configure REST mapper: no client configuration")
+	}
+	k.restMapper = newResettingRESTMapper(restmapper.NewShortcutExpander(restmapper.NewDeferredDiscoveryRESTMapper(dc), dc))
+	return k.restMapper, nil
+}
+
+// ClusterClientsets returns the clients of the cluster described by the cluster block of a resource.
+// They are created when first requested, and shared by all resources targeting the same cluster.
+func (k *kubeClientsets) ClusterClientsets(cluster map[string]interface{}) (KubeClientsets, error) {
+	overrides, err := expandClusterOverrides(cluster)
+	if err != nil {
+		return nil, err
+	}
+	// impersonation applies to all requests, including the ones to the cluster of a resource
+	setImpersonate(&overrides.AuthInfo, k.impersonate)
+	key, err := json.Marshal(overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if c, ok := k.clusters[string(key)]; ok {
+		return c, nil
+	}
+
+	var cc clientcmd.ClientConfig
+	if overrides.CurrentContext != "" {
+		cc = k.kubeconfig.clientConfig(overrides)
+	} else {
+		// a cluster configured without a context doesn't inherit anything from the kube config
+		cc = clientcmd.NewNonInteractiveClientConfig(clientcmdapi.Config{}, "", overrides, nil)
+	}
+	cfg, err := cc.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to configure client for cluster: %s", err)
+	}
+	completeClientConfig(cfg, k.userAgent, k.clientOptions)
+
+	c := &kubeClientsets{
+		config:            cfg,
+		IgnoreAnnotations: k.IgnoreAnnotations,
+		IgnoreLabels:      k.IgnoreLabels,
+	}
+	if k.clusters == nil {
+		k.clusters = make(map[string]*kubeClientsets)
+	}
+	k.clusters[string(key)] = c
+	return c, nil
+}
+
 func providerConfigure(ctx context.Context, d *schema.ResourceData, terraformVersion string) (interface{}, diag.Diagnostics) {
+	kc, err := expandKubeconfig(d)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	clientOptions, err := expandClientOptions(d)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	impersonate, err := expandImpersonate(d)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+
 	// Config initialization
-	cfg, err := initializeConfiguration(d)
+	cfg, err := initializeConfiguration(d, kc, impersonate)
 	if err != nil {
 		return nil, diag.FromErr(err)
 	}
@@ -459,14 +774,8 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData, terraformVer
 		cfg = &restclient.Config{}
 	}
 
-	cfg.UserAgent = fmt.Sprintf("HashiCorp/1.0 Terraform/%s", terraformVersion)
-
-	if logging.IsDebugOrHigher() {
-		log.Printf("[DEBUG] Enabling HTTP requests/responses tracing")
-		cfg.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
-			return logging.NewTransport("Kubernetes", rt)
-		}
-	}
+	userAgent := fmt.Sprintf("HashiCorp/1.0 Terraform/%s", terraformVersion)
+	completeClientConfig(cfg, userAgent, clientOptions)
 
 	ignoreAnnotations := []string{}
 	ignoreLabels := []string{}
@@ -478,23 +787,112 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData, terraformVer
 		ignoreLabels = expandStringSlice(v)
 	}
 
-	m := kubeClientsets{
-		config:              cfg,
-		mainClientset:       nil,
-		aggregatorClientset: nil,
-		IgnoreAnnotations:   ignoreAnnotations,
-		IgnoreLabels:        ignoreLabels,
+	m := &kubeClientsets{
+		config:            cfg,
+		kubeconfig:        kc,
+		userAgent:         userAgent,
+		clientOptions:     clientOptions,
+		impersonate:       impersonate,
+		IgnoreAnnotations: ignoreAnnotations,
+		IgnoreLabels:      ignoreLabels,
 	}
 	return m, diag.Diagnostics{}
 }
 
-func initializeConfiguration(d *schema.ResourceData) (*restclient.Config, error) {
-	overrides := &clientcmd.ConfigOverrides{}
-	loader := &clientcmd.ClientConfigLoadingRules{}
+// completeClientConfig sets the user agent, request tracing, rate limiting, timeout and retries of a client configuration
+func completeClientConfig(cfg *restclient.Config, userAgent string, opts util.ClientOptions) {
+	cfg.UserAgent = userAgent
+
+	if logging.IsDebugOrHigher() {
+		log.Printf("[DEBUG] Enabling HTTP requests/responses tracing")
+		cfg.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+			return logging.NewTransport("Kubernetes", rt)
+		}
+	}
+
+	opts.Apply(cfg)
+}
+
+// expandClientOptions reads the rate limiting, timeout and retry settings of the provider configuration
+func expandClientOptions(d *schema.ResourceData) (util.ClientOptions, error) {
+	opts := util.ClientOptions{
+		QPS:   float32(d.Get("qps").(float64)),
+		Burst: d.Get("burst").(int),
+	}
+	if v, ok := d.GetOk("request_timeout"); ok {
+		t, err := time.ParseDuration(v.(string))
+		if err != nil {
+			return opts, fmt.Errorf("Failed to parse request_timeout: %s", err)
+		}
+		opts.Timeout = t
+	}
+
+	v, ok := d.GetOk("retry")
+	if !ok {
+		return opts, nil
+	}
+	opts.Retry = util.NewRetryPolicy()
+	spec, ok := v.([]interface{})[0].(map[string]interface{})
+	if !ok {
+		// an empty block enables retries with the default settings
+		return opts, nil
+	}
+	if v, ok := spec["max_attempts"].(int); ok && v > 0 {
+		opts.Retry.MaxAttempts = v
+	}
+	if v, ok := spec["min_backoff"].(string); ok && v != "" {
+		b, err := time.ParseDuration(v)
+		if err != nil {
+			return opts, fmt.Errorf("Failed to parse retry.min_backoff: %s", err)
+		}
+		opts.Retry.MinBackoff = b
+	}
+	if v, ok := spec["max_backoff"].(string); ok && v != "" {
+		b, err := time.ParseDuration(v)
+		if err != nil {
+			return opts, fmt.Errorf("Failed to parse retry.max_backoff: %s", err)
+		}
+		opts.Retry.MaxBackoff = b
+	}
+	if v, ok := spec["retryable_status_codes"].([]interface{}); ok && len(v) > 0 {
+		opts.Retry.RetryableStatusCodes = make([]int, 0, len(v))
+		for _, c := range v {
+			opts.Retry.RetryableStatusCodes = append(opts.Retry.RetryableStatusCodes, c.(int))
+		}
+	}
+	opts.Retry.RetryOnConflict = spec["retry_on_conflict"].(bool)
+	return opts, nil
+}
+
+// kubeconfig holds the kube config files, or the inline kube config, of the provider configuration
+type kubeconfig struct {
+	loader *clientcmd.ClientConfigLoadingRules
+	raw    *clientcmdapi.Config
+}
 
+func (kc kubeconfig) isSet() bool {
+	return kc.raw != nil || kc.loader.ExplicitPath != "" || len(kc.loader.Precedence) > 0
+}
+
+func (kc kubeconfig) clientConfig(overrides *clientcmd.ConfigOverrides) clientcmd.ClientConfig {
+	if kc.raw != nil {
+		return clientcmd.NewNonInteractiveClientConfig(*kc.raw, overrides.CurrentContext, overrides, nil)
+	}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(kc.loader, overrides)
+}
+
+func expandKubeconfig(d *schema.ResourceData) (kubeconfig, error) {
+	kc := kubeconfig{loader: &clientcmd.ClientConfigLoadingRules{}}
 	configPaths := []string{}
 
-	if v, ok := d.Get("config_path").(string); ok && v != "" {
+	if v, ok := d.Get("config_raw").(string); ok && v != "" {
+		c, err := clientcmd.Load([]byte(v))
+		if err != nil {
+			return kc, fmt.Errorf("Failed to parse config_raw: %s", err)
+		}
+		log.Printf("[DEBUG] Using kubeconfig from config_raw")
+		kc.raw = c
+	} else if v, ok := d.Get("config_path").(string); ok && v != "" {
 		configPaths = []string{v}
 	} else if v, ok := d.Get("config_paths").([]interface{}); ok && len(v) > 0 {
 		for _, p := range v {
@@ -511,7 +909,7 @@ func initializeConfiguration(d *schema.ResourceData) (*restclient.Config, error)
 		for _, p := range configPaths {
 			path, err := homedir.Expand(p)
 			if err != nil {
-				return nil, err
+				return kc, err
 			}
 
 			log.Printf("[DEBUG] Using kubeconfig: %s", path)
@@ -519,11 +917,53 @@ func initializeConfiguration(d *schema.ResourceData) (*restclient.Config, error)
 		}
 
 		if len(expandedPaths) == 1 {
-			loader.ExplicitPath = expandedPaths[0]
+			kc.loader.ExplicitPath = expandedPaths[0]
 		} else {
-			loader.Precedence = expandedPaths
+			kc.loader.Precedence = expandedPaths
 		}
+	}
+	return kc, nil
+}
 
+// expandImpersonate reads the user, UID, groups and extra fields to impersonate from the "impersonate" block
+func expandImpersonate(d *schema.ResourceData) (clientcmdapi.AuthInfo, error) {
+	imp := clientcmdapi.AuthInfo{}
+	v, ok := d.GetOk("impersonate")
+	if !ok {
+		return imp, nil
+	}
+	spec, ok := v.([]interface{})[0].(map[string]interface{})
+	if !ok {
+		return imp, fmt.Errorf("Failed to parse impersonate")
+	}
+	imp.Impersonate = spec["user"].(string)
+	imp.ImpersonateUID = spec["uid"].(string)
+	imp.ImpersonateGroups = expandStringSlice(spec["groups"].([]interface{}))
+	if extra := spec["extra"].([]interface{}); len(extra) > 0 {
+		imp.ImpersonateUserExtra = make(map[string][]string, len(extra))
+		for _, e := range extra {
+			if e, ok := e.(map[string]interface{}); ok {
+				key := e["key"].(string)
+				imp.ImpersonateUserExtra[key] = append(imp.ImpersonateUserExtra[key], expandStringSlice(e["values"].([]interface{}))...)
+			}
+		}
+	}
+	log.Printf("[DEBUG] Impersonating user %q", imp.Impersonate)
+	return imp, nil
+}
+
+// setImpersonate copies the impersonation settings of imp to authInfo
+func setImpersonate(authInfo *clientcmdapi.AuthInfo, imp clientcmdapi.AuthInfo) {
+	authInfo.Impersonate = imp.Impersonate
+	authInfo.ImpersonateUID = imp.ImpersonateUID
+	authInfo.ImpersonateGroups = imp.ImpersonateGroups
+	authInfo.ImpersonateUserExtra = imp.ImpersonateUserExtra
+}
+
+func initializeConfiguration(d *schema.ResourceData, kc kubeconfig, impersonate clientcmdapi.AuthInfo) (*restclient.Config, error) {
+	overrides := &clientcmd.ConfigOverrides{}
+
+	if kc.isSet() {
 		ctxSuffix := "; default context"
 
 		kubectx, ctxOk := d.GetOk("config_context")
@@ -563,20 +1003,28 @@ func initializeConfiguration(d *schema.ResourceData) (*restclient.Config, error)
 	if v, ok := d.GetOk("client_certificate"); ok {
 		overrides.AuthInfo.ClientCertificateData = bytes.NewBufferString(v.(string)).Bytes()
 	}
+	if v, ok := d.GetOk("cluster_ca_certificate_file"); ok {
+		path, err := homedir.Expand(v.(string))
+		if err != nil {
+			return nil, err
+		}
+		overrides.ClusterInfo.CertificateAuthority = path
+	}
+	if v, ok := d.GetOk("client_certificate_file"); ok {
+		path, err := homedir.Expand(v.(string))
+		if err != nil {
+			return nil, err
+		}
+		overrides.AuthInfo.ClientCertificate = path
+	}
 	if v, ok := d.GetOk("host"); ok {
 		// Server has to be the complete address of the kubernetes cluster (scheme://hostname:port), not just the hostname,
 		// because `overrides` are processed too late to be taken into account by `defaultServerUrlFor()`.
-		// This basically replicates what defaultServerUrlFor() does with config but for overrides,
-		// see https://github.com/kubernetes/client-go/blob/v12.0.0/rest/url_utils.go#L85-L87
-		hasCA := len(overrides.ClusterInfo.CertificateAuthorityData) != 0
-		hasCert := len(overrides.AuthInfo.ClientCertificateData) != 0
-		defaultTLS := hasCA || hasCert || overrides.ClusterInfo.InsecureSkipTLSVerify
-		host, _, err := restclient.DefaultServerURL(v.(string), "", apimachineryschema.GroupVersion{}, defaultTLS)
+		host, err := defaultServerURL(v.(string), overrides)
 		if err != nil {
-			return nil, fmt.Errorf("Failed to parse host: %s", err)
+			return nil, err
 		}
-
-		overrides.ClusterInfo.Server = host.String()
+		overrides.ClusterInfo.Server = host
 	}
 	if v, ok := d.GetOk("username"); ok {
 		overrides.AuthInfo.Username = v.(string)
@@ -587,32 +1035,40 @@ func initializeConfiguration(d *schema.ResourceData) (*restclient.Config, error)
 	if v, ok := d.GetOk("client_key"); ok {
 		overrides.AuthInfo.ClientKeyData = bytes.NewBufferString(v.(string)).Bytes()
 	}
+	if v, ok := d.GetOk("client_key_file"); ok {
+		path, err := homedir.Expand(v.(string))
+		if err != nil {
+			return nil, err
+		}
+		overrides.AuthInfo.ClientKey = path
+	}
 	if v, ok := d.GetOk("token"); ok {
 		overrides.AuthInfo.Token = v.(string)
 	}
+	if v, ok := d.GetOk("token_file"); ok {
+		// client-go re-reads the token from the file periodically, and after an Unauthorized response
+		path, err := homedir.Expand(v.(string))
+		if err != nil {
+			return nil, err
+		}
+		overrides.AuthInfo.TokenFile = path
+	}
 
 	if v, ok := d.GetOk("exec"); ok {
-		exec := &clientcmdapi.ExecConfig{}
-		if spec, ok := v.([]interface{})[0].(map[string]interface{}); ok {
-			exec.InteractiveMode = clientcmdapi.IfAvailableExecInteractiveMode
-			exec.APIVersion = spec["api_version"].(string)
-			exec.Command = spec["command"].(string)
-			exec.Args = expandStringSlice(spec["args"].([]interface{}))
-			for kk, vv := range spec["env"].(map[string]interface{}) {
-				exec.Env = append(exec.Env, clientcmdapi.ExecEnvVar{Name: kk, Value: vv.(string)})
-			}
-		} else {
-			return nil, fmt.Errorf("Failed to parse exec")
+		exec, err := expandExecConfig(v.([]interface{}))
+		if err != nil {
+			return nil, err
 		}
 		overrides.AuthInfo.Exec = exec
 	}
 
+	setImpersonate(&overrides.AuthInfo, impersonate)
+
 	if v, ok := d.GetOk("proxy_url"); ok {
 		overrides.ClusterDefaults.ProxyURL = v.(string)
 	}
 
-	cc := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loader, overrides)
-	cfg, err := cc.ClientConfig()
+	cfg, err := kc.clientConfig(overrides).ClientConfig()
 	if err != nil {
 		log.Printf("[WARN] Invalid provider configuration was supplied. Provider operations likely to fail: %v", err)
 		return nil, nil
@@ -621,6 +1077,40 @@ func initializeConfiguration(d *schema.ResourceData) (*restclient.Config, error)
 	return cfg, nil
 }
 
+// defaultServerURL returns the complete address of the Kubernetes API server (scheme://hostname:port) for host,
+// using https by default when TLS is configured.
+// This basically replicates what defaultServerUrlFor() does with config but for overrides,
+// see https://github.com/kubernetes/client-go/blob/v12.0.0/rest/url_utils.go#L85-L87
+func defaultServerURL(host string, overrides *clientcmd.ConfigOverrides) (string, error) {
+	hasCA := len(overrides.ClusterInfo.CertificateAuthorityData) != 0 || overrides.ClusterInfo.CertificateAuthority != ""
+	hasCert := len(overrides.AuthInfo.ClientCertificateData) != 0 || overrides.AuthInfo.ClientCertificate != ""
+	defaultTLS := hasCA || hasCert || overrides.ClusterInfo.InsecureSkipTLSVerify
+	u, _, err := restclient.DefaultServerURL(host, "", apimachineryschema.GroupVersion{}, defaultTLS)
+	if err != nil {
+		return "", fmt.Errorf("Failed to parse host: %s", err)
+	}
+	return u.String(), nil
+}
+
+func expandExecConfig(v []interface{}) (*clientcmdapi.ExecConfig, error) {
+	spec, ok := v[0].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Failed to parse exec")
+	}
+	exec := &clientcmdapi.ExecConfig{
+		InteractiveMode: clientcmdapi.IfAvailableExecInteractiveMode,
+		APIVersion:      spec["api_version"].(string),
+		Command:         spec["command"].(string),
+		Args:            expandStringSlice(spec["args"].([]interface{})),
+	}
+	for kk, vv := range spec["env"].(map[string]interface{}) {
+		exec.Env = append(exec.Env, clientcmdapi.ExecEnvVar{Name: kk, Value: vv.(string)})
+	}
+	// the configuration identifies the cluster in the cache of clients of the cluster block
+	sort.Slice(exec.Env, func(i, j int) bool { return exec.Env[i].Name < exec.Env[j].Name })
+	return exec, nil
+}
+
 var useadmissionregistrationv1beta1 *bool
 
 func useAdmissionregistrationV1beta1(conn *kubernetes.Clientset) (bool, error) {
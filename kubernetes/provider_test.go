@@ -11,7 +11,9 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 
@@ -20,6 +22,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	api "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 // Global constants for testing images (reduces the number of docker pulls).
@@ -102,6 +105,246 @@ func TestProvider_configure_paths(t *testing.T) {
 	}
 }
 
+func TestProvider_configure_clientOptions(t *testing.T) {
+	ctx := context.TODO()
+	resetEnv := unsetEnv(t)
+	defer resetEnv()
+
+	os.Setenv("KUBE_CONFIG_PATH", "test-fixtures/kube-config.yaml")
+	os.Setenv("KUBE_CTX", "gcp")
+
+	rc := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"qps":             50,
+		"burst":           100,
+		"request_timeout": "45s",
+		"retry": []interface{}{
+			map[string]interface{}{
+				"max_attempts":           3,
+				"retryable_status_codes": []interface{}{429},
+			},
+		},
+	})
+	p := Provider()
+	diags := p.Configure(ctx, rc)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	kc := p.Meta().(*kubeClientsets)
+	cfg := kc.config
+	// the timeout is set on each request but watches, rather than on the client
+	if cfg.QPS != 50 || cfg.Burst != 100 || cfg.Timeout != 0 || kc.clientOptions.Timeout != 45*time.Second {
+		t.Fatalf("unexpected client configuration: qps %v, burst %d, timeout %s", cfg.QPS, cfg.Burst, kc.clientOptions.Timeout)
+	}
+	if cfg.WrapTransport == nil {
+		t.Fatal("expected the transport to be wrapped by the timeout and retry transports")
+	}
+}
+
+func TestProvider_configure_impersonate(t *testing.T) {
+	ctx := context.TODO()
+	resetEnv := unsetEnv(t)
+	defer resetEnv()
+
+	os.Setenv("KUBE_CONFIG_PATH", "test-fixtures/kube-config.yaml")
+	os.Setenv("KUBE_CTX", "gcp")
+
+	rc := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"impersonate": []interface{}{
+			map[string]interface{}{
+				"user":   "system:serviceaccount:tenant:deployer",
+				"groups": []interface{}{"tenant-admins"},
+				"extra": []interface{}{
+					map[string]interface{}{
+						"key":    "scopes",
+						"values": []interface{}{"view", "edit"},
+					},
+				},
+			},
+		},
+	})
+	p := Provider()
+	diags := p.Configure(ctx, rc)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	imp := p.Meta().(*kubeClientsets).config.Impersonate
+	if imp.UserName != "system:serviceaccount:tenant:deployer" {
+		t.Fatalf("expected the user to be impersonated, got %q", imp.UserName)
+	}
+	if len(imp.Groups) != 1 || imp.Groups[0] != "tenant-admins" {
+		t.Fatalf("unexpected groups: %v", imp.Groups)
+	}
+	if len(imp.Extra["scopes"]) != 2 {
+		t.Fatalf("unexpected extra fields: %v", imp.Extra)
+	}
+
+	for _, cluster := range []map[string]interface{}{
+		{"config_context": "oidc"},
+		{"host": "https://10.0.0.1:6443", "token": "test"},
+	} {
+		c, err := p.Meta().(KubeClientsets).ClusterClientsets(cluster)
+		if err != nil {
+			t.Fatal(err)
+		}
+		imp := c.(*kubeClientsets).config.Impersonate
+		if imp.UserName != "system:serviceaccount:tenant:deployer" {
+			t.Fatalf("expected the user to be impersonated in cluster %v, got %q", cluster, imp.UserName)
+		}
+		if len(imp.Groups) != 1 || imp.Groups[0] != "tenant-admins" {
+			t.Fatalf("unexpected groups in cluster %v: %v", cluster, imp.Groups)
+		}
+		if len(imp.Extra["scopes"]) != 2 {
+			t.Fatalf("unexpected extra fields in cluster %v: %v", cluster, imp.Extra)
+		}
+	}
+}
+
+func TestProvider_configure_raw(t *testing.T) {
+	ctx := context.TODO()
+	resetEnv := unsetEnv(t)
+	defer resetEnv()
+
+	raw, err := os.ReadFile("test-fixtures/kube-config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("rotated-token"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	rc := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"config_raw":     string(raw),
+		"config_context": "gcp",
+		"token_file":     tokenFile,
+	})
+	p := Provider()
+	diags := p.Configure(ctx, rc)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	cfg := p.Meta().(*kubeClientsets).config
+	if cfg.Host != "https://127.0.0.1" {
+		t.Fatalf("expected the host of the kube config, got %q", cfg.Host)
+	}
+	if cfg.BearerTokenFile != tokenFile {
+		t.Fatalf("expected the token to be read from %q, got %q", tokenFile, cfg.BearerTokenFile)
+	}
+}
+
+func TestProvider_configure_sharedClients(t *testing.T) {
+	ctx := context.TODO()
+	resetEnv := unsetEnv(t)
+	defer resetEnv()
+
+	rc := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"host":     "https://127.0.0.1:6443",
+		"insecure": true,
+		"token":    "test",
+	})
+	p := Provider()
+	diags := p.Configure(ctx, rc)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	m := p.Meta().(KubeClientsets)
+
+	var wg sync.WaitGroup
+	clients := make([]*kubernetes.Clientset, 10)
+	for i := range clients {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var err error
+			if clients[i], err = m.MainClientset(); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	for _, c := range clients {
+		if c == nil || c != clients[0] {
+			t.Fatal("expected all callers to share the same clientset")
+		}
+	}
+
+	dc1, err := m.DiscoveryClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dc2, _ := m.DiscoveryClient()
+	if dc1 != dc2 {
+		t.Fatal("expected the discovery client to be shared")
+	}
+	rm1, err := m.RESTMapper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rm2, _ := m.RESTMapper()
+	if rm1 != rm2 {
+		t.Fatal("expected the REST mapper to be shared")
+	}
+}
+
+func TestProvider_configure_clusterClientsets(t *testing.T) {
+	ctx := context.TODO()
+	resetEnv := unsetEnv(t)
+	defer resetEnv()
+
+	raw, err := os.ReadFile("test-fixtures/kube-config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"config_raw":     string(raw),
+		"config_context": "gcp",
+		"qps":            50,
+	})
+	p := Provider()
+	diags := p.Configure(ctx, rc)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	m := p.Meta().(KubeClientsets)
+
+	oidc, err := m.ClusterClientsets(map[string]interface{}{"config_context": "oidc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := oidc.(*kubeClientsets).config
+	if cfg.AuthProvider == nil || cfg.AuthProvider.Name != "oidc" {
+		t.Fatalf("expected the user of the oidc context, got %#v", cfg.AuthProvider)
+	}
+	if cfg.QPS != 50 {
+		t.Fatalf("expected the client settings of the provider configuration, got qps %v", cfg.QPS)
+	}
+	if c, _ := m.ClusterClientsets(map[string]interface{}{"config_context": "oidc"}); c != oidc {
+		t.Fatal("expected the clients of a cluster to be shared")
+	}
+	if oidc == m {
+		t.Fatal("expected the clients of the cluster to differ from the ones of the provider configuration")
+	}
+
+	static, err := m.ClusterClientsets(map[string]interface{}{
+		"host":  "https://10.0.0.1:6443",
+		"token": "test",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg = static.(*kubeClientsets).config
+	if cfg.Host != "https://10.0.0.1:6443" || cfg.BearerToken != "test" {
+		t.Fatalf("unexpected host %q or token %q", cfg.Host, cfg.BearerToken)
+	}
+	if cfg.AuthProvider != nil {
+		t.Fatal("expected a cluster without a context not to inherit the user of the kube config")
+	}
+
+	if _, err := m.ClusterClientsets(map[string]interface{}{"config_context": "missing"}); err == nil {
+		t.Fatal("expected an error for a context missing from the kube config")
+	}
+}
+
 func unsetEnv(t *testing.T) func() {
 	e := getEnv()
 
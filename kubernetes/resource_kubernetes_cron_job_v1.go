@@ -59,7 +59,7 @@ func resourceKubernetesCronJobV1Create(ctx context.Context, d *schema.ResourceDa
 	}
 
 	configAnnotations := d.Get("metadata.0.annotations").(map[string]interface{})
-	ignoreAnnotations := meta.(kubeClientsets).IgnoreAnnotations
+	ignoreAnnotations := meta.(*kubeClientsets).IgnoreAnnotations
 	annotations := removeInternalKeys(metadata.Annotations, make(map[string]interface{}))
 	metadata.Annotations = removeKeys(annotations, configAnnotations, ignoreAnnotations)
 
@@ -97,7 +97,7 @@ func resourceKubernetesCronJobV1Update(ctx context.Context, d *schema.ResourceDa
 		return diag.FromErr(err)
 	}
 	configAnnotations := d.Get("metadata.0.annotations").(map[string]interface{})
-	ignoreAnnotations := meta.(kubeClientsets).IgnoreAnnotations
+	ignoreAnnotations := meta.(*kubeClientsets).IgnoreAnnotations
 	annotations := removeInternalKeys(metadata.Annotations, make(map[string]interface{}))
 	metadata.Annotations = removeKeys(annotations, configAnnotations, ignoreAnnotations)
 	spec.JobTemplate.ObjectMeta.Annotations = metadata.Annotations
@@ -21,7 +21,6 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
-	"k8s.io/client-go/restmapper"
 )
 
 func resourceKubernetesEnv() *schema.Resource {
@@ -31,7 +30,7 @@ func resourceKubernetesEnv() *schema.Resource {
 		UpdateContext: resourceKubernetesEnvUpdate,
 		DeleteContext: resourceKubernetesEnvDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceKubernetesEnvImportState,
 		},
 		Schema: map[string]*schema.Schema{
 			"metadata": {
@@ -233,27 +232,35 @@ func resourceKubernetesEnvCreate(ctx context.Context, d *schema.ResourceData, m
 	return diag
 }
 
-func resourceKubernetesEnvRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	conn, err := m.(KubeClientsets).DynamicClient()
+// resourceKubernetesEnvImportState checks the scope of the namespace of the imported ID, which is
+// not checked for IDs read back from the state
+func resourceKubernetesEnvImportState(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	restMapper, err := m.(KubeClientsets).RESTMapper()
 	if err != nil {
-		return diag.FromErr(err)
+		return nil, err
 	}
+	if _, _, _, err := util.ParseResourceID(d.Id(), restMapper); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
 
-	gvk, name, namespace, err := util.ParseResourceID(d.Id())
+func resourceKubernetesEnvRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	conn, err := m.(KubeClientsets).DynamicClient()
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	// figure out which resource client to use
-	dc, err := m.(KubeClientsets).DiscoveryClient()
+	restMapper, err := m.(KubeClientsets).RESTMapper()
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	agr, err := restmapper.GetAPIGroupResources(dc)
+
+	gvk, name, namespace, err := util.ParseStateResourceID(d.Id(), restMapper)
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	restMapper := restmapper.NewDiscoveryRESTMapper(agr)
 	mapping, err := restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
 	if err != nil {
 		return diag.FromErr(err)
@@ -401,15 +408,10 @@ func resourceKubernetesEnvUpdate(ctx context.Context, d *schema.ResourceData, m
 	namespace := metadata.GetNamespace()
 
 	// figure out which resource client to use
-	dc, err := m.(KubeClientsets).DiscoveryClient()
-	if err != nil {
-		return diag.FromErr(err)
-	}
-	agr, err := restmapper.GetAPIGroupResources(dc)
+	restMapper, err := m.(KubeClientsets).RESTMapper()
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	restMapper := restmapper.NewDiscoveryRESTMapper(agr)
 	gv, err := k8sschema.ParseGroupVersion(apiVersion)
 	if err != nil {
 		return diag.FromErr(err)
@@ -20,7 +20,6 @@ import (
 	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
-	"k8s.io/client-go/restmapper"
 )
 
 func resourceKubernetesLabels() *schema.Resource {
@@ -102,21 +101,16 @@ func resourceKubernetesLabelsRead(ctx context.Context, d *schema.ResourceData, m
 		return diag.FromErr(err)
 	}
 
-	gvk, name, namespace, err := util.ParseResourceID(d.Id())
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
 	// figure out which resource client to use
-	dc, err := m.(KubeClientsets).DiscoveryClient()
+	restMapper, err := m.(KubeClientsets).RESTMapper()
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	agr, err := restmapper.GetAPIGroupResources(dc)
+
+	gvk, name, namespace, err := util.ParseStateResourceID(d.Id(), restMapper)
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	restMapper := restmapper.NewDiscoveryRESTMapper(agr)
 	mapping, err := restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
 	if err != nil {
 		return diag.FromErr(err)
@@ -202,15 +196,10 @@ func resourceKubernetesLabelsUpdate(ctx context.Context, d *schema.ResourceData,
 	namespace := metadata.GetNamespace()
 
 	// figure out which resource client to use
-	dc, err := m.(KubeClientsets).DiscoveryClient()
-	if err != nil {
-		return diag.FromErr(err)
-	}
-	agr, err := restmapper.GetAPIGroupResources(dc)
+	restMapper, err := m.(KubeClientsets).RESTMapper()
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	restMapper := restmapper.NewDiscoveryRESTMapper(agr)
 	gv, err := k8sschema.ParseGroupVersion(apiVersion)
 	if err != nil {
 		return diag.FromErr(err)
@@ -0,0 +1,83 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubernetes
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// resettingRESTMapper resets the discovery data cached by a REST mapper when a lookup fails
+// because the resource type is not found, and retries it once with fresh data.
+// Once populated, the in-memory discovery cache is otherwise never refreshed.
+type resettingRESTMapper struct {
+	meta.RESTMapper
+}
+
+func newResettingRESTMapper(m meta.RESTMapper) meta.ResettableRESTMapper {
+	return resettingRESTMapper{m}
+}
+
+func (m resettingRESTMapper) Reset() {
+	meta.MaybeResetRESTMapper(m.RESTMapper)
+}
+
+func isResourceTypeNotFound(err error) bool {
+	return meta.IsNoMatchError(err) || errors.IsNotFound(err)
+}
+
+func (m resettingRESTMapper) KindFor(resource schema.GroupVersionResource) (schema.GroupVersionKind, error) {
+	gvk, err := m.RESTMapper.KindFor(resource)
+	if isResourceTypeNotFound(err) {
+		m.Reset()
+		return m.RESTMapper.KindFor(resource)
+	}
+	return gvk, err
+}
+
+func (m resettingRESTMapper) KindsFor(resource schema.GroupVersionResource) ([]schema.GroupVersionKind, error) {
+	gvks, err := m.RESTMapper.KindsFor(resource)
+	if isResourceTypeNotFound(err) {
+		m.Reset()
+		return m.RESTMapper.KindsFor(resource)
+	}
+	return gvks, err
+}
+
+func (m resettingRESTMapper) ResourceFor(input schema.GroupVersionResource) (schema.GroupVersionResource, error) {
+	gvr, err := m.RESTMapper.ResourceFor(input)
+	if isResourceTypeNotFound(err) {
+		m.Reset()
+		return m.RESTMapper.ResourceFor(input)
+	}
+	return gvr, err
+}
+
+func (m resettingRESTMapper) ResourcesFor(input schema.GroupVersionResource) ([]schema.GroupVersionResource, error) {
+	gvrs, err := m.RESTMapper.ResourcesFor(input)
+	if isResourceTypeNotFound(err) {
+		m.Reset()
+		return m.RESTMapper.ResourcesFor(input)
+	}
+	return gvrs, err
+}
+
+func (m resettingRESTMapper) RESTMapping(gk schema.GroupKind, versions ...string) (*meta.RESTMapping, error) {
+	mapping, err := m.RESTMapper.RESTMapping(gk, versions...)
+	if isResourceTypeNotFound(err) {
+		m.Reset()
+		return m.RESTMapper.RESTMapping(gk, versions...)
+	}
+	return mapping, err
+}
+
+func (m resettingRESTMapper) RESTMappings(gk schema.GroupKind, versions ...string) ([]*meta.RESTMapping, error) {
+	mappings, err := m.RESTMapper.RESTMappings(gk, versions...)
+	if isResourceTypeNotFound(err) {
+		m.Reset()
+		return m.RESTMapper.RESTMappings(gk, versions...)
+	}
+	return mappings, err
+}
@@ -0,0 +1,64 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package kubernetes
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// staleRESTMapper only knows about the kinds added to it once it has been reset
+type staleRESTMapper struct {
+	*meta.DefaultRESTMapper
+	pending []schema.GroupVersionKind
+	resets  int
+}
+
+func (m *staleRESTMapper) Reset() {
+	m.resets++
+	for _, gvk := range m.pending {
+		m.Add(gvk, meta.RESTScopeNamespace)
+	}
+	m.pending = nil
+}
+
+func TestResettingRESTMapper(t *testing.T) {
+	configMap := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	widget := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+
+	stale := &staleRESTMapper{
+		DefaultRESTMapper: meta.NewDefaultRESTMapper(nil),
+		pending:           []schema.GroupVersionKind{widget},
+	}
+	stale.Add(configMap, meta.RESTScopeNamespace)
+	m := newResettingRESTMapper(stale)
+
+	if _, err := m.RESTMapping(configMap.GroupKind(), configMap.Version); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stale.resets != 0 {
+		t.Fatalf("expected no reset for a known kind, got %d", stale.resets)
+	}
+
+	mapping, err := m.RESTMapping(widget.GroupKind(), widget.Version)
+	if err != nil {
+		t.Fatalf("expected the kind to be found after a reset, got: %v", err)
+	}
+	if mapping.Resource.Resource != "widgets" {
+		t.Fatalf("unexpected resource %q", mapping.Resource.Resource)
+	}
+	if stale.resets != 1 {
+		t.Fatalf("expected 1 reset, got %d", stale.resets)
+	}
+
+	_, err = m.RESTMapping(schema.GroupKind{Group: "example.com", Kind: "Gadget"}, "v1")
+	if !meta.IsNoMatchError(err) {
+		t.Fatalf("expected a no match error, got: %v", err)
+	}
+	if stale.resets != 2 {
+		t.Fatalf("expected the lookup to be retried only once, got %d resets", stale.resets)
+	}
+}
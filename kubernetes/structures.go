@@ -124,7 +124,7 @@ func flattenMetadata(meta metav1.ObjectMeta, d *schema.ResourceData, providerMet
 
 	if prefix == "" {
 		configAnnotations := d.Get(prefix + "metadata.0.annotations").(map[string]interface{})
-		ignoreAnnotations := providerMetadata.(kubeClientsets).IgnoreAnnotations
+		ignoreAnnotations := providerMetadata.(*kubeClientsets).IgnoreAnnotations
 		annotations := removeInternalKeys(meta.Annotations, configAnnotations)
 		m["annotations"] = removeKeys(annotations, configAnnotations, ignoreAnnotations)
 	} else {
@@ -136,7 +136,7 @@ func flattenMetadata(meta metav1.ObjectMeta, d *schema.ResourceData, providerMet
 	}
 
 	configLabels := d.Get(prefix + "metadata.0.labels").(map[string]interface{})
-	ignoreLabels := providerMetadata.(kubeClientsets).IgnoreLabels
+	ignoreLabels := providerMetadata.(*kubeClientsets).IgnoreLabels
 	labels := removeInternalKeys(meta.Labels, configLabels)
 	m["labels"] = removeKeys(labels, configLabels, ignoreLabels)
 	m["name"] = meta.Name
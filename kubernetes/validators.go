@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -202,6 +203,16 @@ func validateIntGreaterThan(minValue int) func(value interface{}, key string) (w
 	}
 }
 
+func validateDuration(value interface{}, key string) (ws []string, es []error) {
+	v := value.(string)
+	if d, err := time.ParseDuration(v); err != nil {
+		es = append(es, fmt.Errorf("%s: cannot parse %q as a duration: %s", key, v, err))
+	} else if d < 0 {
+		es = append(es, fmt.Errorf("%s must not be negative", key))
+	}
+	return
+}
+
 // validateTypeStringNullableInt provides custom error messaging for TypeString ints
 // Some arguments require an int value or unspecified, empty field.
 func validateTypeStringNullableInt(v interface{}, k string) (ws []string, es []error) {
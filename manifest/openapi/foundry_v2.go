@@ -7,9 +7,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
@@ -19,6 +21,36 @@ var ObjectMetaGVK = schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Obj
 // NewFoundryFromSpecV2 creates a new tftypes.Type foundry from an OpenAPI v2 spec document
 // * spec argument should be a valid OpenAPI v2 JSON document
 func NewFoundryFromSpecV2(spec []byte) (Foundry, error) {
+	f, err := newFoapiv2(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	err = f.buildGvkIndex()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build GVK index when creating new foundry: %s", err)
+	}
+
+	return f, nil
+}
+
+// NewFoundryFromSpecV2WithIndex creates a new tftypes.Type foundry from an OpenAPI v2 spec document
+// and a GVK index previously obtained from a foundry for the same document, skipping the index build.
+func NewFoundryFromSpecV2WithIndex(spec []byte, index GVKIndex) (Foundry, error) {
+	f, err := newFoapiv2(spec)
+	if err != nil {
+		return nil, err
+	}
+	for gvk, id := range index {
+		if _, ok := f.swagger.Definitions[id]; !ok {
+			return nil, fmt.Errorf("GVK index refers to unknown definition %q", id)
+		}
+		f.gkvIndex.Store(gvk, id)
+	}
+	return f, nil
+}
+
+func newFoapiv2(spec []byte) (*foapiv2, error) {
 	if len(spec) < 6 { // unlikely to be valid json
 		return nil, errors.New("empty spec")
 	}
@@ -34,20 +66,13 @@ func NewFoundryFromSpecV2(spec []byte) (Foundry, error) {
 		return nil, errors.New("spec has no type information")
 	}
 
-	f := foapiv2{
+	return &foapiv2{
 		swagger:        &swg,
 		typeCache:      sync.Map{},
 		gkvIndex:       sync.Map{}, //reverse lookup index from GVK to OpenAPI definition IDs
 		recursionDepth: 50,         // arbitrarily large number - a type this deep will likely kill Terraform anyway
 		gate:           sync.Mutex{},
-	}
-
-	err = f.buildGvkIndex()
-	if err != nil {
-		return nil, fmt.Errorf("failed to build GVK index when creating new foundry: %s", err)
-	}
-
-	return &f, nil
+	}, nil
 }
 
 // Foundry is a mechanism to construct tftypes out of OpenAPI specifications
@@ -55,6 +80,52 @@ type Foundry interface {
 	GetTypeByGVK(gvk schema.GroupVersionKind) (tftypes.Type, map[string]string, error)
 }
 
+// IndexedFoundry is a Foundry which can export its GVK index, so that it can be
+// stored along with the spec document and passed to NewFoundryFromSpecV2WithIndex
+type IndexedFoundry interface {
+	Foundry
+	GVKIndex() GVKIndex
+}
+
+// GVKIndex associates the GVK of each resource to the ID of its definition in an OpenAPI v2 spec
+type GVKIndex map[schema.GroupVersionKind]string
+
+type gvkIndexEntry struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+	ID      string `json:"id"`
+}
+
+// MarshalJSON encodes the index as a list, as GVKs can't be used as JSON object keys
+func (i GVKIndex) MarshalJSON() ([]byte, error) {
+	entries := make([]gvkIndexEntry, 0, len(i))
+	for gvk, id := range i {
+		entries = append(entries, gvkIndexEntry{gvk.Group, gvk.Version, gvk.Kind, id})
+	}
+	sort.Slice(entries, func(a, b int) bool {
+		ea, eb := entries[a], entries[b]
+		if ea.ID != eb.ID {
+			return ea.ID < eb.ID
+		}
+		return fmt.Sprint(ea) < fmt.Sprint(eb)
+	})
+	return json.Marshal(entries)
+}
+
+// UnmarshalJSON decodes an index encoded by MarshalJSON
+func (i *GVKIndex) UnmarshalJSON(data []byte) error {
+	var entries []gvkIndexEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+	*i = make(GVKIndex, len(entries))
+	for _, e := range entries {
+		(*i)[schema.GroupVersionKind{Group: e.Group, Version: e.Version, Kind: e.Kind}] = e.ID
+	}
+	return nil
+}
+
 type foapiv2 struct {
 	swagger        *openapi2.T
 	typeCache      sync.Map
@@ -89,6 +160,16 @@ func (f *foapiv2) GetTypeByGVK(gvk schema.GroupVersionKind) (tftypes.Type, map[s
 	return t, hints, err
 }
 
+// GVKIndex returns a copy of the reverse lookup index from GVK to OpenAPI definition IDs
+func (f *foapiv2) GVKIndex() GVKIndex {
+	idx := GVKIndex{}
+	f.gkvIndex.Range(func(k, v interface{}) bool {
+		idx[k.(schema.GroupVersionKind)] = v.(string)
+		return true
+	})
+	return idx
+}
+
 func (f *foapiv2) getTypeByID(id string, h map[string]string, ap tftypes.AttributePath) (tftypes.Type, error) {
 	swd, ok := f.swagger.Definitions[id]
 
@@ -111,10 +192,24 @@ func (f *foapiv2) getTypeByID(id string, h map[string]string, ap tftypes.Attribu
 // buildGvkIndex builds the reverse lookup index that associates each GVK
 // to its corresponding string key in the swagger.Definitions map
 func (f *foapiv2) buildGvkIndex() error {
-	for did, dRef := range f.swagger.Definitions {
-		def, err := resolveSchemaRef(dRef, f.swagger.Definitions)
+	idx, err := gvkIndexFromDefinitions(f.swagger.Definitions)
+	if err != nil {
+		return err
+	}
+	for gvk, id := range idx {
+		f.gkvIndex.Store(gvk, id)
+	}
+	return nil
+}
+
+// gvkIndexFromDefinitions associates the GVKs tagged with "x-kubernetes-group-version-kind"
+// to the ID of the definition they are found in
+func gvkIndexFromDefinitions(defs map[string]*openapi3.SchemaRef) (GVKIndex, error) {
+	idx := GVKIndex{}
+	for did, dRef := range defs {
+		def, err := resolveSchemaRef(dRef, defs)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		ex, ok := def.Extensions["x-kubernetes-group-version-kind"]
 		if !ok {
@@ -123,11 +218,11 @@ func (f *foapiv2) buildGvkIndex() error {
 		gvk := []schema.GroupVersionKind{}
 		err = json.Unmarshal(([]byte)(ex.(json.RawMessage)), &gvk)
 		if err != nil {
-			return fmt.Errorf("failed to unmarshall GVK from OpenAPI schema extention: %v", err)
+			return nil, fmt.Errorf("failed to unmarshall GVK from OpenAPI schema extention: %v", err)
 		}
 		for i := range gvk {
-			f.gkvIndex.Store(gvk[i], did)
+			idx[gvk[i]] = did
 		}
 	}
-	return nil
+	return idx, nil
 }
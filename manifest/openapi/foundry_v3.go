@@ -4,6 +4,7 @@
 package openapi
 
 import (
+	"encoding/json"
 	"fmt"
 	"sync"
 
@@ -64,3 +65,92 @@ func (f *foapiv3) GetTypeByGVK(_ schema.GroupVersionKind) (tftypes.Type, map[str
 	tftype, err := getTypeFromSchema(sch, 50, &(f.typeCache), f.doc.Components.Schemas, ap, hints)
 	return tftype, hints, err
 }
+
+// NewFoundryFromGroupVersionsV3 creates a new tftypes.Type foundry backed by the OpenAPI v3 documents
+// a cluster publishes for each API group version (e.g. at /openapi/v3/apis/apps/v1).
+// A document is retrieved using the supplied function the first time a type from its group version
+// is requested. The function should return the document as JSON.
+func NewFoundryFromGroupVersionsV3(get func(gv schema.GroupVersion) ([]byte, error)) Foundry {
+	return &foapiv3gv{
+		get:            get,
+		docs:           make(map[schema.GroupVersion]*foapiv3doc),
+		recursionDepth: 50,
+	}
+}
+
+type foapiv3gv struct {
+	get            func(gv schema.GroupVersion) ([]byte, error)
+	docs           map[schema.GroupVersion]*foapiv3doc
+	recursionDepth uint64
+	gate           sync.Mutex
+}
+
+type foapiv3doc struct {
+	schemas   openapi3.Schemas
+	gvkIndex  GVKIndex
+	typeCache sync.Map
+}
+
+// GetTypeByGVK looks up a type by its GVK in the OpenAPI v3 document of its
+// group version and returns its (nearest) tftypes.Type equivalent
+func (f *foapiv3gv) GetTypeByGVK(gvk schema.GroupVersionKind) (tftypes.Type, map[string]string, error) {
+	f.gate.Lock()
+	defer f.gate.Unlock()
+
+	var hints map[string]string = make(map[string]string)
+	ap := tftypes.AttributePath{}
+
+	doc, err := f.getDocument(gvk.GroupVersion())
+	if err != nil {
+		return nil, hints, err
+	}
+
+	// ObjectMeta is not tagged with a GVK, but is part of the core/v1 document
+	id := "io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"
+	if gvk != ObjectMetaGVK {
+		var ok bool
+		id, ok = doc.gvkIndex[gvk]
+		if !ok {
+			return nil, hints, fmt.Errorf("%v resource not found in OpenAPI index", gvk)
+		}
+	}
+	sref, ok := doc.schemas[id]
+	if !ok || sref == nil {
+		return nil, hints, fmt.Errorf("%v resource has no schema in OpenAPI document", gvk)
+	}
+	sch, err := resolveSchemaRef(sref, doc.schemas)
+	if err != nil {
+		return nil, hints, fmt.Errorf("failed to resolve schema: %s", err)
+	}
+	t, err := getTypeFromSchema(sch, f.recursionDepth, &(doc.typeCache), doc.schemas, ap, hints)
+	return t, hints, err
+}
+
+func (f *foapiv3gv) getDocument(gv schema.GroupVersion) (*foapiv3doc, error) {
+	if doc, ok := f.docs[gv]; ok {
+		return doc, nil
+	}
+	spec, err := f.get(gv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get OpenAPI v3 document for %s: %w", gv, err)
+	}
+	// the document is decoded without resolving references, as the
+	// type generation depends on them to detect recursive types
+	var oapi3 openapi3.T
+	if err := json.Unmarshal(spec, &oapi3); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI v3 document for %s: %s", gv, err)
+	}
+	if len(oapi3.Components.Schemas) == 0 {
+		return nil, fmt.Errorf("OpenAPI v3 document for %s has no type information", gv)
+	}
+	idx, err := gvkIndexFromDefinitions(oapi3.Components.Schemas)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build GVK index for %s: %s", gv, err)
+	}
+	doc := &foapiv3doc{
+		schemas:  oapi3.Components.Schemas,
+		gvkIndex: idx,
+	}
+	f.docs[gv] = doc
+	return doc, nil
+}
@@ -5,7 +5,11 @@ package openapi
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func TestNewFoundryFromSpecV3(t *testing.T) {
@@ -66,3 +70,94 @@ func TestNewFoundryFromSpecV3(t *testing.T) {
 		t.Fail()
 	}
 }
+
+const sampleGroupVersionSpecV3 = `{
+  "openapi": "3.0.0",
+  "info": {"title": "Kubernetes", "version": "v1.25.0"},
+  "paths": {},
+  "components": {
+    "schemas": {
+      "com.hashicorp.v1.Widget": {
+        "type": "object",
+        "properties": {
+          "apiVersion": {"type": "string"},
+          "kind": {"type": "string"},
+          "metadata": {
+            "default": {},
+            "allOf": [{"$ref": "#/components/schemas/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"}]
+          },
+          "spec": {
+            "type": "object",
+            "properties": {
+              "port": {"allOf": [{"$ref": "#/components/schemas/io.k8s.apimachinery.pkg.util.intstr.IntOrString"}]},
+              "tags": {"type": "array", "items": {"type": "string", "default": ""}}
+            }
+          }
+        },
+        "x-kubernetes-group-version-kind": [{"group": "hashicorp.com", "kind": "Widget", "version": "v1"}]
+      },
+      "io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta": {
+        "type": "object",
+        "properties": {
+          "name": {"type": "string"},
+          "labels": {"type": "object", "additionalProperties": {"type": "string", "default": ""}}
+        }
+      },
+      "io.k8s.apimachinery.pkg.util.intstr.IntOrString": {
+        "type": "string",
+        "format": "int-or-string"
+      }
+    }
+  }
+}`
+
+func TestNewFoundryFromGroupVersionsV3(t *testing.T) {
+	widgetGVK := schema.GroupVersionKind{Group: "hashicorp.com", Version: "v1", Kind: "Widget"}
+	requests := map[schema.GroupVersion]int{}
+	f := NewFoundryFromGroupVersionsV3(func(gv schema.GroupVersion) ([]byte, error) {
+		requests[gv]++
+		if gv != widgetGVK.GroupVersion() {
+			return nil, errors.New("not found")
+		}
+		return []byte(sampleGroupVersionSpecV3), nil
+	})
+
+	metaType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"name":   tftypes.String,
+		"labels": tftypes.Map{ElementType: tftypes.String},
+	}}
+	expected := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"apiVersion": tftypes.String,
+		"kind":       tftypes.String,
+		"metadata":   metaType,
+		"spec": tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+			"port": tftypes.String,
+			"tags": tftypes.List{ElementType: tftypes.String},
+		}},
+	}}
+
+	typ, hints, err := f.GetTypeByGVK(widgetGVK)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !typ.Equal(expected) {
+		t.Fatalf("unexpected type:\n%s\nexpected:\n%s", typ, expected)
+	}
+	if hints["AttributeName(\"spec\").AttributeName(\"port\")"] != "io.k8s.apimachinery.pkg.util.intstr.IntOrString" {
+		t.Fatalf("missing int-or-string hint: %v", hints)
+	}
+
+	// further types of the same group version don't fetch the document again
+	_, _, err = f.GetTypeByGVK(widgetGVK.GroupVersion().WithKind("Gadget"))
+	if err == nil {
+		t.Fatal("expected error for unknown kind")
+	}
+	if requests[widgetGVK.GroupVersion()] != 1 {
+		t.Fatalf("expected document to be fetched once, got %d", requests[widgetGVK.GroupVersion()])
+	}
+
+	_, _, err = f.GetTypeByGVK(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"})
+	if err == nil {
+		t.Fatal("expected error for group version without a document")
+	}
+}
@@ -85,6 +85,16 @@ func getTypeFromSchema(elem *openapi3.Schema, stackdepth uint64, typeCache *sync
 	// 		return t.(tftypes.Type), nil
 	// 	}
 	// }
+	// OpenAPI v3 documents wrap references in "allOf" to be able to attach a description
+	// or default value to them. These are equivalent to the referenced schema.
+	if elem.Type == "" && len(elem.AllOf) == 1 && elem.Properties == nil {
+		s, err := resolveSchemaRef(elem.AllOf[0], defs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve schema: %s", err)
+		}
+		return getTypeFromSchema(s, stackdepth-1, typeCache, defs, ap, th)
+	}
+
 	switch elem.Type {
 	case "string":
 		if elem.Format == "int-or-string" {
@@ -23,9 +23,20 @@ import (
 var defaultCreateTimeout = "10m"
 var defaultUpdateTimeout = "10m"
 var defaultDeleteTimeout = "10m"
+var defaultReadTimeout = "10m"
 
 // ApplyResourceChange function
 func (s *RawProviderServer) ApplyResourceChange(ctx context.Context, req *tfprotov5.ApplyResourceChangeRequest) (*tfprotov5.ApplyResourceChangeResponse, error) {
+	// the resource is applied with the clients of the cluster it targets, or of the one it is deleted from
+	s, diags := s.forResourceCluster(req.TypeName, req.PlannedState, req.PriorState)
+	if len(diags) > 0 {
+		return &tfprotov5.ApplyResourceChangeResponse{Diagnostics: diags}, nil
+	}
+
+	if req.TypeName == "kubernetes_manifest_set" {
+		return s.ApplyManifestSetChange(ctx, req)
+	}
+
 	resp := &tfprotov5.ApplyResourceChangeResponse{}
 
 	execDiag := s.canExecute()
@@ -33,6 +44,10 @@ func (s *RawProviderServer) ApplyResourceChange(ctx context.Context, req *tfprot
 		resp.Diagnostics = append(resp.Diagnostics, execDiag...)
 		return resp, nil
 	}
+	if d := s.canExecuteOnline("apply"); len(d) > 0 {
+		resp.Diagnostics = append(resp.Diagnostics, d...)
+		return resp, nil
+	}
 
 	rt, err := GetResourceType(req.TypeName)
 	if err != nil {
@@ -53,7 +68,6 @@ func (s *RawProviderServer) ApplyResourceChange(ctx context.Context, req *tfprot
 		})
 		return resp, nil
 	}
-	s.logger.Trace("[ApplyResourceChange][PlannedState] %#v", applyPlannedState)
 
 	applyPriorState, err := req.PriorState.Unmarshal(rt)
 	if err != nil {
@@ -64,7 +78,9 @@ func (s *RawProviderServer) ApplyResourceChange(ctx context.Context, req *tfprot
 		})
 		return resp, nil
 	}
-	s.logger.Trace("[ApplyResourceChange]", "[PriorState]", dump(applyPriorState))
+	logFields := sensitiveFieldsOfStates(applyPlannedState, applyPriorState)
+	s.logger.Trace("[ApplyResourceChange]", "[PlannedState]", dump(redactState(applyPlannedState, logFields)))
+	s.logger.Trace("[ApplyResourceChange]", "[PriorState]", dump(redactState(applyPriorState, logFields)))
 
 	config, err := req.Config.Unmarshal(rt)
 	if err != nil {
@@ -132,6 +148,27 @@ func (s *RawProviderServer) ApplyResourceChange(ctx context.Context, req *tfprot
 		computedFields[atp.String()] = atp
 	}
 
+	sensitiveFields, err := getSensitiveFields(plannedStateVal)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Invalid sensitive_fields",
+			Detail:    err.Error(),
+			Attribute: tftypes.NewAttributePath().WithAttributeName("sensitive_fields"),
+		})
+		return resp, nil
+	}
+	ignoreFields, err := getIgnoreFields(plannedStateVal)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Invalid ignore_fields",
+			Detail:    err.Error(),
+			Attribute: tftypes.NewAttributePath().WithAttributeName("ignore_fields"),
+		})
+		return resp, nil
+	}
+
 	c, err := s.getDynamicClient()
 	if err != nil {
 		resp.Diagnostics = append(resp.Diagnostics,
@@ -166,6 +203,21 @@ func (s *RawProviderServer) ApplyResourceChange(ctx context.Context, req *tfprot
 			return resp, nil
 		}
 
+		plannedObj := obj
+		if !obj.IsKnown() {
+			// planning was deferred because the resource type was not known to the
+			// cluster yet, most likely because its CRD is created in the same apply
+			timeout, _ := time.ParseDuration(s.getTimeouts(plannedStateVal)["create"])
+			ctxDeferred, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			var d []*tfprotov5.Diagnostic
+			obj, d = s.planDeferredObject(ctxDeferred, plannedStateVal["manifest"])
+			resp.Diagnostics = append(resp.Diagnostics, d...)
+			if len(d) > 0 {
+				return resp, nil
+			}
+		}
+
 		gvk, err := GVKFromTftypesObject(&obj, m)
 		if err != nil {
 			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
@@ -224,8 +276,30 @@ func (s *RawProviderServer) ApplyResourceChange(ctx context.Context, req *tfprot
 			return resp, nil
 		}
 
+		// The planned object only holds placeholders for sensitive fields, their values come from the manifest.
+		obj, err = restoreSensitiveFields(obj, plannedStateVal["manifest"], sensitiveFields)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Failed to restore sensitive values in proposed value",
+				Detail:   err.Error(),
+			})
+			return resp, nil
+		}
+
+		// Ignored fields are left out of the request, so that their ownership is released to their manager
+		obj, err = removeIgnoredFields(obj, ignoreFields)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Failed to remove ignored fields from proposed value",
+				Detail:   err.Error(),
+			})
+			return resp, nil
+		}
+
 		nullObj := morph.UnknownToNull(obj)
-		s.logger.Trace("[ApplyResourceChange][Apply]", "[UnknownToNull]", dump(nullObj))
+		s.logger.Trace("[ApplyResourceChange][Apply]", "[UnknownToNull]", dump(redactValue(nullObj, sensitiveFields)))
 
 		// Remove empty objects unless explicitly set by the user in manifest.
 		// They only serve a structural purpose in the planning phase and should not be included in the API payload.
@@ -280,7 +354,7 @@ func (s *RawProviderServer) ApplyResourceChange(ctx context.Context, req *tfprot
 		if err != nil {
 			return resp, err
 		}
-		s.logger.Trace("[ApplyResourceChange][Apply]", "[payload.FromTFValue]", dump(pu))
+		s.logger.Trace("[ApplyResourceChange][Apply]", "[payload.FromTFValue]", dump(redactUnstructured(pu, sensitiveFields)))
 
 		// remove null attributes - the API doesn't appreciate requests that include them
 		rqObj := mapRemoveNulls(pu.(map[string]interface{}))
@@ -313,8 +387,11 @@ func (s *RawProviderServer) ApplyResourceChange(ctx context.Context, req *tfprot
 			rs = c.Resource(gvr)
 		}
 
+		// resources named by the API server are created with a POST instead of server-side apply
+		generateName := applyPriorState.IsNull() && rname == "" && uo.GetGenerateName() != ""
+
 		// Check the resource does not exist if this is a create operation
-		if applyPriorState.IsNull() {
+		if applyPriorState.IsNull() && !generateName {
 			_, err := rs.Get(ctx, rname, metav1.GetOptions{})
 			if err == nil {
 				resp.Diagnostics = append(resp.Diagnostics,
@@ -370,13 +447,24 @@ func (s *RawProviderServer) ApplyResourceChange(ctx context.Context, req *tfprot
 		defer cancel()
 
 		// Call the Kubernetes API to create the new resource
-		s.logger.Trace("[ApplyResourceChange][API Payload]: %s", jsonManifest)
-		result, err := rs.Patch(ctxDeadline, rname, types.ApplyPatchType, jsonManifest,
-			metav1.PatchOptions{
-				FieldManager: fieldManagerName,
-				Force:        &forceConflicts,
-			},
-		)
+		s.logger.Trace("[ApplyResourceChange][API Payload]", "manifest", dump(redactUnstructured(uo.Object, sensitiveFields)))
+		var result *unstructured.Unstructured
+		if generateName {
+			result, err = s.createWithGeneratedName(ctxDeadline, rs, &uo, fieldManagerName, forceConflicts)
+			if err == nil {
+				rname = result.GetName()
+				rnn = types.NamespacedName{Namespace: rnamespace, Name: rname}.String()
+			} else {
+				rnn = types.NamespacedName{Namespace: rnamespace, Name: uo.GetGenerateName()}.String()
+			}
+		} else {
+			result, err = rs.Patch(ctxDeadline, rname, types.ApplyPatchType, jsonManifest,
+				metav1.PatchOptions{
+					FieldManager: fieldManagerName,
+					Force:        &forceConflicts,
+				},
+			)
+		}
 		if err != nil {
 			s.logger.Error("[ApplyResourceChange][Apply]", "API error", dump(err), "API response", dump(result))
 			if apierrors.IsConflict(err) {
@@ -392,7 +480,7 @@ func (s *RawProviderServer) ApplyResourceChange(ctx context.Context, req *tfprot
 					},
 				)
 			} else if status := apierrors.APIStatus(nil); errors.As(err, &status) {
-				resp.Diagnostics = append(resp.Diagnostics, APIStatusErrorToDiagnostics(status.Status())...)
+				resp.Diagnostics = append(resp.Diagnostics, APIStatusErrorToManifestDiagnostics(status.Status(), plannedStateVal["manifest"])...)
 			} else {
 				resp.Diagnostics = append(resp.Diagnostics,
 					&tfprotov5.Diagnostic{
@@ -425,26 +513,17 @@ func (s *RawProviderServer) ApplyResourceChange(ctx context.Context, req *tfprot
 		if !waitConfig.IsNull() {
 			err = s.waitForCompletion(ctxDeadline, waitConfig, rs, rname, wt, th)
 			if err != nil {
-				if reason, ok := err.(WaiterError); ok {
-					resp.Diagnostics = append(resp.Diagnostics,
-						&tfprotov5.Diagnostic{
-							Severity: tfprotov5.DiagnosticSeverityError,
-							Summary:  "Operation timed out",
-							Detail:   reason.Error(),
-						})
-				} else {
-					resp.Diagnostics = append(resp.Diagnostics,
-						&tfprotov5.Diagnostic{
-							Severity: tfprotov5.DiagnosticSeverityError,
-							Summary:  "Error waiting for operation to complete",
-							Detail:   err.Error(),
-						})
+				resp.Diagnostics = append(resp.Diagnostics, WaiterErrorToDiagnostic(rnn, err))
+				switch err.(type) {
+				case WaiterError, ConditionFailedError:
+					// still record the resource in state
+				default:
 					return resp, nil
 				}
 			}
 			r, err := rs.Get(ctx, rname, metav1.GetOptions{})
 			if err != nil {
-				s.logger.Error("[ApplyResourceChange][ReadAfterWait]", "API error", dump(err), "API response", dump(result))
+				s.logger.Error("[ApplyResourceChange][ReadAfterWait]", "API error", dump(err), "API response", dump(redactUnstructured(result.Object, sensitiveFields)))
 				resp.Diagnostics = append(resp.Diagnostics,
 					&tfprotov5.Diagnostic{
 						Severity: tfprotov5.DiagnosticSeverityError,
@@ -456,7 +535,35 @@ func (s *RawProviderServer) ApplyResourceChange(ctx context.Context, req *tfprot
 			result = r
 		}
 
-		newResObject, err := payload.ToTFValue(RemoveServerSideFields(result.Object), tsch, th, tftypes.NewAttributePath())
+		dryRunOnPlan, err := getDryRunOnPlan(plannedStateVal)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Invalid dry_run_on_plan",
+				Detail:   err.Error(),
+			})
+			return resp, nil
+		}
+		waitFields, err := getWaitFieldPaths(plannedStateVal)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Invalid wait fields",
+				Detail:   err.Error(),
+			})
+			return resp, nil
+		}
+		content, err := objectContent(result.Object, fieldManagerName, dryRunOnPlan, waitFields)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics,
+				&tfprotov5.Diagnostic{
+					Severity: tfprotov5.DiagnosticSeverityError,
+					Summary:  "Failed to filter fields by field manager",
+					Detail:   err.Error(),
+				})
+			return resp, nil
+		}
+		newResObject, err := payload.ToTFValue(RemoveServerSideFields(content), tsch, th, tftypes.NewAttributePath())
 		if err != nil {
 			resp.Diagnostics = append(resp.Diagnostics,
 				&tfprotov5.Diagnostic{
@@ -466,16 +573,48 @@ func (s *RawProviderServer) ApplyResourceChange(ctx context.Context, req *tfprot
 				})
 			return resp, nil
 		}
-		s.logger.Trace("[ApplyResourceChange][Apply]", "[payload.ToTFValue]", dump(newResObject))
+		s.logger.Trace("[ApplyResourceChange][Apply]", "[payload.ToTFValue]", dump(redactValue(newResObject, sensitiveFields)))
 
 		compObj, err := morph.DeepUnknown(tsch, newResObject, tftypes.NewAttributePath())
 		if err != nil {
 			return resp, err
 		}
-		plannedStateVal["object"] = morph.UnknownToNull(compObj)
+		if !tsch.Is(tftypes.Object{}) && plannedObj.IsKnown() {
+			// without a schema the type of the object is inferred from the response, make it match the plan
+			compObj, err = morphToPlannedType(compObj, plannedObj.Type())
+			if err != nil {
+				resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+					Severity:  tfprotov5.DiagnosticSeverityError,
+					Summary:   "API response does not match the planned type of the resource",
+					Detail:    fmt.Sprintf("The resource was applied, but the API server returned fields of a different kind than planned, for example because a mutating webhook changed them. The resource is kept in the cluster, and the state is left unchanged.\nError: %s", err),
+					Attribute: tftypes.NewAttributePath().WithAttributeName("object"),
+				})
+				return resp, nil
+			}
+		}
+		// the provider may still share the ownership of an ignored field with its manager
+		compObj, err = removeIgnoredFields(compObj, ignoreFields)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Failed to remove ignored fields from new resource state",
+				Detail:   err.Error(),
+			})
+			return resp, nil
+		}
+		newObj, err := redactSensitiveFields(morph.UnknownToNull(compObj), sensitiveFields)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Failed to redact sensitive fields in new resource state",
+				Detail:   err.Error(),
+			})
+			return resp, nil
+		}
+		plannedStateVal["object"] = newObj
 
 		newStateVal := tftypes.NewValue(applyPlannedState.Type(), plannedStateVal)
-		s.logger.Trace("[ApplyResourceChange][Apply]", "new state value", dump(newStateVal))
+		s.logger.Trace("[ApplyResourceChange][Apply]", "new state value", dump(redactState(newStateVal, sensitiveFields)))
 
 		newResState, err := tfprotov5.NewDynamicValue(newStateVal.Type(), newStateVal)
 		if err != nil {
@@ -503,6 +642,22 @@ func (s *RawProviderServer) ApplyResourceChange(ctx context.Context, req *tfprot
 			return resp, nil
 		}
 
+		deleteCfg, err := getDeleteConfig(priorStateVal)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   "Invalid delete configuration",
+				Detail:    err.Error(),
+				Attribute: tftypes.NewAttributePath().WithAttributeName("delete"),
+			})
+			return resp, nil
+		}
+		if deleteCfg.abandon {
+			s.logger.Trace("[ApplyResourceChange][Delete]", "Resource is abandoned, leaving it in the cluster")
+			resp.NewState = req.PlannedState
+			return resp, nil
+		}
+
 		pu, err := payload.FromTFValue(pco, nil, tftypes.NewAttributePath())
 		if err != nil {
 			return resp, err
@@ -538,7 +693,7 @@ func (s *RawProviderServer) ApplyResourceChange(ctx context.Context, req *tfprot
 		ctxDeadline, cancel := context.WithDeadline(ctx, deadline)
 		defer cancel()
 
-		err = rs.Delete(ctxDeadline, rname, metav1.DeleteOptions{})
+		err = rs.Delete(ctxDeadline, rname, deleteCfg.deleteOptions())
 		if err != nil {
 			rn := types.NamespacedName{Namespace: rnamespace, Name: rname}.String()
 			resp.Diagnostics = append(resp.Diagnostics,
@@ -551,31 +706,33 @@ func (s *RawProviderServer) ApplyResourceChange(ctx context.Context, req *tfprot
 		}
 
 		// wait for delete
-		for {
-			if time.Now().After(deadline) {
-				resp.Diagnostics = append(resp.Diagnostics,
-					&tfprotov5.Diagnostic{
-						Severity: tfprotov5.DiagnosticSeverityError,
-						Summary:  fmt.Sprintf("Timed out when waiting for resource %q to be deleted", rname),
-						Detail:   "Deletion timed out. This can happen when there is a finalizer on a resource. You may need to delete this resource manually with kubectl.",
-					})
-				return resp, nil
-			}
-			_, err := rs.Get(ctxDeadline, rname, metav1.GetOptions{})
-			if err != nil {
-				if apierrors.IsNotFound(err) {
-					s.logger.Trace("[ApplyResourceChange][Delete]", "Resource is deleted")
-					break
+		err = s.waitForDeletion(ctxDeadline, rs, rname, deleteCfg)
+		if err != nil {
+			if te, ok := err.(DeletionTimeoutError); ok {
+				detail := "Deletion timed out. This can happen when there is a finalizer on a resource. You may need to delete this resource manually with kubectl, or set \"remove_finalizers_after\" in the \"delete\" block."
+				if len(te.Finalizers) > 0 {
+					detail = fmt.Sprintf("Deletion timed out. The resource still has the finalizers %q.", te.Finalizers)
+					for _, f := range te.Finalizers {
+						if f == metav1.FinalizerDeleteDependents {
+							detail += " It is waiting for its dependents to be deleted."
+						}
+					}
 				}
 				resp.Diagnostics = append(resp.Diagnostics,
 					&tfprotov5.Diagnostic{
 						Severity: tfprotov5.DiagnosticSeverityError,
-						Summary:  "Error waiting for deletion.",
-						Detail:   fmt.Sprintf("Error when waiting for resource %q to be deleted: %v", rname, err),
+						Summary:  fmt.Sprintf("Timed out when waiting for resource %q to be deleted", rname),
+						Detail:   detail,
 					})
 				return resp, nil
 			}
-			time.Sleep(1 * time.Second) // lintignore:R018
+			resp.Diagnostics = append(resp.Diagnostics,
+				&tfprotov5.Diagnostic{
+					Severity: tfprotov5.DiagnosticSeverityError,
+					Summary:  "Error waiting for deletion.",
+					Detail:   fmt.Sprintf("Error when waiting for resource %q to be deleted: %v", rname, err),
+				})
+			return resp, nil
 		}
 
 		resp.NewState = req.PlannedState
@@ -589,6 +746,7 @@ func (s *RawProviderServer) getTimeouts(v map[string]tftypes.Value) map[string]s
 		"create": defaultCreateTimeout,
 		"update": defaultUpdateTimeout,
 		"delete": defaultDeleteTimeout,
+		"read":   defaultReadTimeout,
 	}
 	if !v["timeouts"].IsNull() && v["timeouts"].IsKnown() {
 		var timeoutsBlock []tftypes.Value
@@ -597,7 +755,7 @@ func (s *RawProviderServer) getTimeouts(v map[string]tftypes.Value) map[string]s
 			var t map[string]tftypes.Value
 			timeoutsBlock[0].As(&t)
 			var s string
-			for _, k := range []string{"create", "update", "delete"} {
+			for _, k := range []string{"create", "update", "delete", "read"} {
 				if vv, ok := t[k]; ok && !vv.IsNull() {
 					vv.As(&s)
 					if s != "" {
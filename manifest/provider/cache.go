@@ -0,0 +1,229 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-provider-kubernetes/manifest/openapi"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/disk"
+	"k8s.io/client-go/metadata"
+)
+
+const defaultCacheTTL = 24 * time.Hour
+
+const (
+	cacheOpenAPIv2File      = "openapi-v2.json"
+	cacheOpenAPIv2IndexFile = "openapi-v2-index.json"
+)
+
+var crdGVR = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// schemaCache stores the OpenAPI spec and the discovery data of a cluster on disk,
+// so they can be reused by later runs of the provider.
+//
+// Entries live in a directory named after the API server host and a key derived from
+// the server version and the resourceVersions of all CRDs. Any change to either of them
+// moves the cache to a new directory and the old one is removed.
+type schemaCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// getSchemaCache returns the on-disk cache for the configured cluster,
+// or nil when no cache directory is configured or it cannot be used.
+func (ps *RawProviderServer) getSchemaCache(ctx context.Context) *schemaCache {
+	if ps.schemaCache != nil || ps.cacheDir == "" {
+		return ps.schemaCache
+	}
+	c, err := ps.newSchemaCache(ctx)
+	if err != nil {
+		ps.logger.Warn("[SchemaCache]", "cache disabled", err.Error())
+		ps.schemaCacheErr = fmt.Errorf("cannot use cache directory %q: %w", ps.cacheDir, err)
+		ps.cacheDir = ""
+		return nil
+	}
+	ps.schemaCache = c
+	return c
+}
+
+// schemaCacheDiagnostics sets up the on-disk cache and returns a warning when the
+// configured cache directory cannot be used. The warning is only returned once.
+func (ps *RawProviderServer) schemaCacheDiagnostics(ctx context.Context) (diags []*tfprotov5.Diagnostic) {
+	if ps.offline != nil {
+		return
+	}
+	ps.getSchemaCache(ctx)
+	if ps.schemaCacheErr == nil {
+		return
+	}
+	ps.schemaCacheWarned.Do(func() {
+		diags = append(diags, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityWarning,
+			Summary:  "Schema cache disabled",
+			Detail:   fmt.Sprintf("The OpenAPI spec and discovery data of the cluster are downloaded without being cached. %s", ps.schemaCacheErr),
+		})
+	})
+	return
+}
+
+func (ps *RawProviderServer) newSchemaCache(ctx context.Context) (*schemaCache, error) {
+	if ps.clientConfig == nil {
+		return nil, fmt.Errorf("no client config")
+	}
+	dc, err := discovery.NewDiscoveryClientForConfig(ps.clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	version, err := dc.ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server version: %w", err)
+	}
+	mc, err := metadata.NewForConfig(ps.clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	crdVersions := map[string]string{}
+	crds, err := mc.Resource(crdGVR).List(ctx, metav1.ListOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to list CRDs: %w", err)
+	}
+	if crds != nil {
+		for _, crd := range crds.Items {
+			crdVersions[crd.GetName()] = crd.GetResourceVersion()
+		}
+	}
+
+	hostDir := filepath.Join(ps.cacheDir, cacheHostDirName(ps.clientConfig.Host))
+	c := &schemaCache{
+		dir: filepath.Join(hostDir, schemaCacheKey(version.GitVersion, crdVersions)),
+		ttl: ps.cacheTTL,
+	}
+	if err := os.MkdirAll(c.dir, 0o750); err != nil {
+		return nil, err
+	}
+	// entries for earlier versions of the cluster won't be used again
+	stale, _ := os.ReadDir(hostDir)
+	for _, e := range stale {
+		if p := filepath.Join(hostDir, e.Name()); p != c.dir {
+			os.RemoveAll(p)
+		}
+	}
+	ps.logger.Debug("[SchemaCache]", "directory", c.dir)
+	return c, nil
+}
+
+var unsafeCachePathChars = regexp.MustCompile(`[^(\w/.)]`)
+
+// cacheHostDirName turns the API server URL into a directory name, the same way kubectl does.
+func cacheHostDirName(host string) string {
+	host = strings.TrimPrefix(host, "https://")
+	host = strings.TrimPrefix(host, "http://")
+	return unsafeCachePathChars.ReplaceAllString(strings.ReplaceAll(host, "/", "_"), "_")
+}
+
+// schemaCacheKey identifies the state of the cluster the cached data was retrieved from.
+func schemaCacheKey(serverVersion string, crdVersions map[string]string) string {
+	names := make([]string, 0, len(crdVersions))
+	for n := range crdVersions {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	h := sha256.New()
+	fmt.Fprintln(h, serverVersion)
+	for _, n := range names {
+		fmt.Fprintf(h, "%s=%s\n", n, crdVersions[n])
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// discoveryClient returns a discovery client which caches its responses in the cache directory.
+func (c *schemaCache) discoveryClient(ps *RawProviderServer) (discovery.CachedDiscoveryInterface, error) {
+	return disk.NewCachedDiscoveryClientForConfig(ps.clientConfig,
+		filepath.Join(c.dir, "discovery"), filepath.Join(c.dir, "http"), c.ttl)
+}
+
+// readFile returns the contents of a cached file, unless it is missing or older than the TTL.
+func (c *schemaCache) readFile(name string) ([]byte, bool) {
+	p := filepath.Join(c.dir, name)
+	fi, err := os.Stat(p)
+	if err != nil || time.Since(fi.ModTime()) > c.ttl {
+		return nil, false
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// writeFile atomically replaces a cached file, so concurrent readers never see partial content.
+func (c *schemaCache) writeFile(name string, data []byte) error {
+	f, err := os.CreateTemp(c.dir, name+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), filepath.Join(c.dir, name))
+}
+
+// getOAPIv2Foundry returns the foundry built from the cached OpenAPI v2 spec and GVK index, if present.
+func (c *schemaCache) getOAPIv2Foundry() (openapi.Foundry, bool) {
+	spec, ok := c.readFile(cacheOpenAPIv2File)
+	if !ok {
+		return nil, false
+	}
+	idxData, ok := c.readFile(cacheOpenAPIv2IndexFile)
+	if !ok {
+		return nil, false
+	}
+	var idx openapi.GVKIndex
+	if err := json.Unmarshal(idxData, &idx); err != nil {
+		return nil, false
+	}
+	f, err := openapi.NewFoundryFromSpecV2WithIndex(spec, idx)
+	if err != nil {
+		return nil, false
+	}
+	return f, true
+}
+
+// putOAPIv2Foundry stores the OpenAPI v2 spec along with the GVK index of the foundry built from it.
+func (c *schemaCache) putOAPIv2Foundry(spec []byte, f openapi.Foundry) error {
+	inf, ok := f.(openapi.IndexedFoundry)
+	if !ok {
+		return fmt.Errorf("foundry does not provide an index")
+	}
+	idx, err := json.Marshal(inf.GVKIndex())
+	if err != nil {
+		return err
+	}
+	// the spec is written last, as it is what makes the entry valid
+	if err := c.writeFile(cacheOpenAPIv2IndexFile, idx); err != nil {
+		return err
+	}
+	return c.writeFile(cacheOpenAPIv2File, spec)
+}
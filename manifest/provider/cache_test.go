@@ -0,0 +1,119 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-provider-kubernetes/manifest/openapi"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const testCacheSpec = `{
+  "swagger": "2.0",
+  "info": {"title": "Kubernetes", "version": "v1.25.0"},
+  "paths": {},
+  "definitions": {
+    "io.k8s.api.core.v1.ConfigMap": {
+      "type": "object",
+      "properties": {
+        "apiVersion": {"type": "string"},
+        "kind": {"type": "string"},
+        "data": {"type": "object", "additionalProperties": {"type": "string"}}
+      },
+      "x-kubernetes-group-version-kind": [{"group": "", "kind": "ConfigMap", "version": "v1"}]
+    }
+  }
+}`
+
+func TestSchemaCacheKey(t *testing.T) {
+	k1 := schemaCacheKey("v1.25.0", map[string]string{"a.example.com": "1", "b.example.com": "2"})
+	k2 := schemaCacheKey("v1.25.0", map[string]string{"b.example.com": "2", "a.example.com": "1"})
+	if k1 != k2 {
+		t.Fatalf("expected key to not depend on CRD order: %q != %q", k1, k2)
+	}
+	changed := map[string]string{
+		"server version":    schemaCacheKey("v1.25.1", map[string]string{"a.example.com": "1", "b.example.com": "2"}),
+		"resource version":  schemaCacheKey("v1.25.0", map[string]string{"a.example.com": "1", "b.example.com": "3"}),
+		"removed CRD":       schemaCacheKey("v1.25.0", map[string]string{"a.example.com": "1"}),
+		"no CRDs installed": schemaCacheKey("v1.25.0", nil),
+	}
+	for name, k := range changed {
+		if k == k1 {
+			t.Errorf("expected key to change with %s", name)
+		}
+	}
+}
+
+func TestSchemaCacheDiagnostics(t *testing.T) {
+	// without a client config the cache can't be set up
+	ps := &RawProviderServer{logger: hclog.NewNullLogger(), cacheDir: t.TempDir()}
+	diags := ps.schemaCacheDiagnostics(context.Background())
+	if len(diags) != 1 || diags[0].Severity != tfprotov5.DiagnosticSeverityWarning {
+		t.Fatalf("expected a warning, got %v", diags)
+	}
+	if ps.getSchemaCache(context.Background()) != nil {
+		t.Fatal("expected the cache to be disabled")
+	}
+	if diags := ps.schemaCacheDiagnostics(context.Background()); len(diags) > 0 {
+		t.Fatalf("expected the warning to be returned once, got %v", diags)
+	}
+}
+
+func TestCacheHostDirName(t *testing.T) {
+	samples := map[string]string{
+		"https://127.0.0.1:6443":            "127.0.0.1_6443",
+		"https://example.com/k8s/clusters/": "example.com_k8s_clusters_",
+		"http://localhost:8080":             "localhost_8080",
+	}
+	for host, dir := range samples {
+		if d := cacheHostDirName(host); d != dir {
+			t.Errorf("expected %q for %q, got %q", dir, host, d)
+		}
+	}
+}
+
+func TestSchemaCacheOAPIv2Foundry(t *testing.T) {
+	c := &schemaCache{dir: t.TempDir(), ttl: time.Hour}
+
+	if _, ok := c.getOAPIv2Foundry(); ok {
+		t.Fatal("expected empty cache")
+	}
+
+	f, err := openapi.NewFoundryFromSpecV2([]byte(testCacheSpec))
+	if err != nil {
+		t.Fatalf("failed to create foundry: %v", err)
+	}
+	if err := c.putOAPIv2Foundry([]byte(testCacheSpec), f); err != nil {
+		t.Fatalf("failed to store foundry: %v", err)
+	}
+
+	cf, ok := c.getOAPIv2Foundry()
+	if !ok {
+		t.Fatal("expected foundry to be read from cache")
+	}
+	typ, _, err := cf.GetTypeByGVK(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"})
+	if err != nil {
+		t.Fatalf("failed to get type from cached foundry: %v", err)
+	}
+	if !typ.Is(tftypes.Object{}) {
+		t.Fatalf("expected object type, got %s", typ)
+	}
+
+	// entries older than the TTL are ignored
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(filepath.Join(c.dir, cacheOpenAPIv2File), old, old); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.getOAPIv2Foundry(); ok {
+		t.Fatal("expected expired cache entry to be ignored")
+	}
+}
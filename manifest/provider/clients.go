@@ -5,15 +5,21 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
 	"github.com/hashicorp/terraform-provider-kubernetes/manifest/openapi"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
@@ -34,6 +40,9 @@ func (ps *RawProviderServer) getDynamicClient() (dynamic.Interface, error) {
 	if ps.dynamicClient != nil {
 		return ps.dynamicClient, nil
 	}
+	if ps.offline != nil {
+		return nil, errOffline
+	}
 	if ps.clientConfig == nil {
 		return nil, fmt.Errorf("cannot create dynamic client: no client config")
 	}
@@ -50,6 +59,9 @@ func (ps *RawProviderServer) getDiscoveryClient() (discovery.DiscoveryInterface,
 	if ps.discoveryClient != nil {
 		return ps.discoveryClient, nil
 	}
+	if ps.offline != nil {
+		return nil, errOffline
+	}
 	if ps.clientConfig == nil {
 		return nil, fmt.Errorf("cannot create discovery client: no client config")
 	}
@@ -66,6 +78,10 @@ func (ps *RawProviderServer) getRestMapper() (meta.RESTMapper, error) {
 	if ps.restMapper != nil {
 		return ps.restMapper, nil
 	}
+	if ps.offline != nil {
+		ps.restMapper = ps.offline.mapper
+		return ps.restMapper, nil
+	}
 	dc, err := ps.getDiscoveryClient()
 	if err != nil {
 		return nil, err
@@ -77,16 +93,36 @@ func (ps *RawProviderServer) getRestMapper() (meta.RESTMapper, error) {
 	// }
 	// mapper := restmapper.NewDeferredDiscoveryRESTMapper(agr)
 
-	cache := memory.NewMemCacheClient(dc)
+	var cache discovery.CachedDiscoveryInterface = memory.NewMemCacheClient(dc)
+	if sc := ps.getSchemaCache(context.TODO()); sc != nil {
+		// stale entries are refreshed by the mapper when a lookup fails
+		dcc, err := sc.discoveryClient(ps)
+		if err != nil {
+			return nil, err
+		}
+		cache = dcc
+	}
 	ps.restMapper = restmapper.NewDeferredDiscoveryRESTMapper(cache)
 	return ps.restMapper, nil
 }
 
+// resetRestMapper invalidates the discovery data cached by the RESTMapper, so that
+// resource types registered since it was populated (e.g. by a new CRD) can be resolved.
+// CRD schemas don't need invalidating as lookUpGVKinCRDs always reads them from the API.
+func (ps *RawProviderServer) resetRestMapper() {
+	if rm, ok := ps.restMapper.(meta.ResettableRESTMapper); ok {
+		rm.Reset()
+	}
+}
+
 // getRestClient returns a raw REST client instance
 func (ps *RawProviderServer) getRestClient() (rest.Interface, error) {
 	if ps.restClient != nil {
 		return ps.restClient, nil
 	}
+	if ps.offline != nil {
+		return nil, errOffline
+	}
 	if ps.clientConfig == nil {
 		return nil, fmt.Errorf("cannot create REST client: no client config")
 	}
@@ -104,6 +140,14 @@ func (ps *RawProviderServer) getOAPIv2Foundry() (openapi.Foundry, error) {
 		return ps.OAPIFoundry, nil
 	}
 
+	sc := ps.getSchemaCache(context.TODO())
+	if sc != nil {
+		if oapif, ok := sc.getOAPIv2Foundry(); ok {
+			ps.OAPIFoundry = oapif
+			return oapif, nil
+		}
+	}
+
 	rc, err := ps.getRestClient()
 	if err != nil {
 		return nil, fmt.Errorf("failed get OpenAPI spec: %s", err)
@@ -122,9 +166,145 @@ func (ps *RawProviderServer) getOAPIv2Foundry() (openapi.Foundry, error) {
 
 	ps.OAPIFoundry = oapif
 
+	if sc != nil {
+		if err := sc.putOAPIv2Foundry(rs, oapif); err != nil {
+			ps.logger.Warn("[SchemaCache]", "failed to store OpenAPI spec", err.Error())
+		}
+	}
+
 	return oapif, nil
 }
 
+// getOAPIFoundry returns an interface to request tftype types of built-in resources. Types are read from
+// the per group-version OpenAPI v3 documents of the cluster when it publishes them, falling back to the
+// OpenAPI v2 spec on servers that don't, or for group-versions that lack a v3 document.
+func (ps *RawProviderServer) getOAPIFoundry() (openapi.Foundry, error) {
+	if ps.OAPIv3Foundry != nil {
+		return ps.OAPIv3Foundry, nil
+	}
+	if ps.offline != nil {
+		return ps.offline.foundry, nil
+	}
+
+	paths, err := ps.getOAPIv3Paths()
+	if err != nil {
+		ps.logger.Debug("[OpenAPI]", "OpenAPI v3 is not available, using v2", err.Error())
+		oapif, err := ps.getOAPIv2Foundry()
+		if err != nil {
+			return nil, err
+		}
+		ps.OAPIv3Foundry = oapif
+		return oapif, nil
+	}
+
+	v3 := openapi.NewFoundryFromGroupVersionsV3(func(gv schema.GroupVersion) ([]byte, error) {
+		p := "apis/" + gv.String()
+		if gv.Group == "" {
+			p = "api/" + gv.Version
+		}
+		u, ok := paths[p]
+		if !ok {
+			return nil, fmt.Errorf("not published by the server")
+		}
+		return ps.getOAPIv3Document(p, u)
+	})
+	ps.OAPIv3Foundry = &fallbackFoundry{
+		primary:  v3,
+		fallback: ps.getOAPIv2Foundry,
+		logger:   ps.logger,
+	}
+	return ps.OAPIv3Foundry, nil
+}
+
+// getOAPIv3Paths returns the URLs of the OpenAPI v3 documents of the cluster, by group-version path
+func (ps *RawProviderServer) getOAPIv3Paths() (map[string]string, error) {
+	rc, err := ps.getRestClient()
+	if err != nil {
+		return nil, err
+	}
+	rs, err := rc.Get().Timeout(30*time.Second).AbsPath("openapi", "v3").DoRaw(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	var disco struct {
+		Paths map[string]struct {
+			ServerRelativeURL string `json:"serverRelativeURL"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(rs, &disco); err != nil {
+		return nil, fmt.Errorf("failed to decode OpenAPI v3 discovery: %s", err)
+	}
+	paths := make(map[string]string, len(disco.Paths))
+	for p, v := range disco.Paths {
+		paths[p] = v.ServerRelativeURL
+	}
+	return paths, nil
+}
+
+// getOAPIv3Document retrieves the OpenAPI v3 document of a group-version, from the cache directory if enabled
+func (ps *RawProviderServer) getOAPIv3Document(path string, uri string) ([]byte, error) {
+	sc := ps.getSchemaCache(context.TODO())
+	cacheFile := "openapi-v3-" + strings.ReplaceAll(path, "/", "_") + ".json"
+	if sc != nil {
+		if doc, ok := sc.readFile(cacheFile); ok {
+			return doc, nil
+		}
+	}
+	rc, err := ps.getRestClient()
+	if err != nil {
+		return nil, err
+	}
+	doc, err := rc.Get().Timeout(30*time.Second).RequestURI(uri).SetHeader("Accept", "application/json").DoRaw(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	if sc != nil {
+		if err := sc.writeFile(cacheFile, doc); err != nil {
+			ps.logger.Warn("[SchemaCache]", "failed to store OpenAPI document", err.Error())
+		}
+	}
+	return doc, nil
+}
+
+// fallbackFoundry looks up types in a secondary foundry when the primary one fails to provide them
+type fallbackFoundry struct {
+	primary  openapi.Foundry
+	fallback func() (openapi.Foundry, error)
+	logger   hclog.Logger
+
+	// the secondary foundry is built once and shared by all lookups,
+	// as building it can mean downloading the whole OpenAPI v2 spec
+	mu        sync.Mutex
+	secondary openapi.Foundry
+}
+
+func (f *fallbackFoundry) GetTypeByGVK(gvk schema.GroupVersionKind) (tftypes.Type, map[string]string, error) {
+	t, h, err := f.primary.GetTypeByGVK(gvk)
+	if err == nil {
+		return t, h, nil
+	}
+	f.logger.Debug("[OpenAPI]", "falling back to OpenAPI v2 for", gvk.String(), "error", err.Error())
+	fb, ferr := f.getSecondary()
+	if ferr != nil {
+		return nil, h, fmt.Errorf("%s; %s", err, ferr)
+	}
+	return fb.GetTypeByGVK(gvk)
+}
+
+func (f *fallbackFoundry) getSecondary() (openapi.Foundry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.secondary != nil {
+		return f.secondary, nil
+	}
+	fb, err := f.fallback()
+	if err != nil {
+		return nil, err
+	}
+	f.secondary = fb
+	return fb, nil
+}
+
 func loggingTransport(rt http.RoundTripper) http.RoundTripper {
 	return &loggingRountTripper{
 		ot: rt,
@@ -138,14 +318,18 @@ type loggingRountTripper struct {
 }
 
 func (t *loggingRountTripper) RoundTrip(req *http.Request) (*http.Response, error) {
-	if req.URL.Path == "/openapi/v2" {
-		// don't trace-log the OpenAPI spec document, it's really big
+	if strings.HasPrefix(req.URL.Path, "/openapi/") {
+		// don't trace-log the OpenAPI spec documents, they're really big
 		return t.ot.RoundTrip(req)
 	}
 	return t.lt.RoundTrip(req)
 }
 
 func (ps *RawProviderServer) checkValidCredentials(ctx context.Context) (diags []*tfprotov5.Diagnostic) {
+	if ps.offline != nil {
+		// there are no credentials to check in offline mode
+		return
+	}
 	rc, err := ps.getRestClient()
 	if err != nil {
 		diags = append(diags, &tfprotov5.Diagnostic{
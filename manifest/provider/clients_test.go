@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-provider-kubernetes/manifest/openapi"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type testFoundry map[schema.GroupVersionKind]tftypes.Type
+
+func (f testFoundry) GetTypeByGVK(gvk schema.GroupVersionKind) (tftypes.Type, map[string]string, error) {
+	if t, ok := f[gvk]; ok {
+		return t, nil, nil
+	}
+	return nil, nil, fmt.Errorf("%s not found", gvk)
+}
+
+func TestFallbackFoundry(t *testing.T) {
+	v3gvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	v2gvk := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Example"}
+	var mu sync.Mutex
+	calls := 0
+	f := &fallbackFoundry{
+		primary: testFoundry{v3gvk: tftypes.String},
+		fallback: func() (openapi.Foundry, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls++
+			return testFoundry{v2gvk: tftypes.Number}, nil
+		},
+		logger: hclog.NewNullLogger(),
+	}
+
+	if tp, _, err := f.GetTypeByGVK(v3gvk); err != nil || !tp.Is(tftypes.String) {
+		t.Fatalf("expected the type of the primary foundry, got %v: %v", tp, err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if tp, _, err := f.GetTypeByGVK(v2gvk); err != nil || !tp.Is(tftypes.Number) {
+				t.Errorf("expected the type of the secondary foundry, got %v: %v", tp, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if _, _, err := f.GetTypeByGVK(schema.GroupVersionKind{Version: "v1", Kind: "Missing"}); err == nil {
+		t.Fatal("expected an error for a type missing from both foundries")
+	}
+	if calls != 1 {
+		t.Fatalf("expected the secondary foundry to be built once, got %d", calls)
+	}
+}
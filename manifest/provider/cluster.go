@@ -0,0 +1,411 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	apimachineryschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// clusterServers holds the servers handling the resources which target the cluster of their "cluster" block,
+// indexed by the client configuration overrides of the block
+type clusterServers struct {
+	sync.Mutex
+	servers map[string]*RawProviderServer
+}
+
+// clusterBlockSchema returns the schema of the "cluster" block, which makes a resource or data source
+// target another cluster than the one of the provider configuration
+func clusterBlockSchema() *tfprotov5.SchemaNestedBlock {
+	return &tfprotov5.SchemaNestedBlock{
+		TypeName: "cluster",
+		Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+		MinItems: 0,
+		MaxItems: 1,
+		Block: &tfprotov5.SchemaBlock{
+			Description: "The cluster to manage the object in, instead of the one of the provider configuration.",
+			Attributes: []*tfprotov5.SchemaAttribute{
+				{
+					Name:        "config_context",
+					Type:        tftypes.String,
+					Optional:    true,
+					Description: "Name of a context of the kube config of the provider configuration, set with `config_path`, `config_paths` or `config_raw`.",
+				},
+				{
+					Name:        "host",
+					Type:        tftypes.String,
+					Optional:    true,
+					Description: "The hostname (in form of URI) of the Kubernetes API server of the cluster.",
+				},
+				{
+					Name:        "insecure",
+					Type:        tftypes.Bool,
+					Optional:    true,
+					Description: "Whether server should be accessed without verifying the TLS certificate.",
+				},
+				{
+					Name:        "tls_server_name",
+					Type:        tftypes.String,
+					Optional:    true,
+					Description: "Server name passed to the server for SNI and is used in the client to check server certificates against.",
+				},
+				{
+					Name:        "cluster_ca_certificate",
+					Type:        tftypes.String,
+					Optional:    true,
+					Description: "PEM-encoded root certificates bundle for TLS authentication.",
+				},
+				{
+					Name:        "client_certificate",
+					Type:        tftypes.String,
+					Optional:    true,
+					Description: "PEM-encoded client certificate for TLS authentication.",
+				},
+				{
+					Name:        "client_key",
+					Type:        tftypes.String,
+					Optional:    true,
+					Sensitive:   true,
+					Description: "PEM-encoded client certificate key for TLS authentication.",
+				},
+				{
+					Name:        "token",
+					Type:        tftypes.String,
+					Optional:    true,
+					Sensitive:   true,
+					Description: "Token to authenticate a service account.",
+				},
+				{
+					Name:        "proxy_url",
+					Type:        tftypes.String,
+					Optional:    true,
+					Description: "URL to the proxy to be used for all API requests to the cluster.",
+				},
+			},
+			BlockTypes: []*tfprotov5.SchemaNestedBlock{
+				{
+					TypeName: "exec",
+					Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+					MinItems: 0,
+					MaxItems: 1,
+					Block: &tfprotov5.SchemaBlock{
+						Description: "Run a command to get the credentials of the cluster.",
+						Attributes: []*tfprotov5.SchemaAttribute{
+							{
+								Name:     "api_version",
+								Type:     tftypes.String,
+								Required: true,
+							},
+							{
+								Name:     "command",
+								Type:     tftypes.String,
+								Required: true,
+							},
+							{
+								Name:     "env",
+								Type:     tftypes.Map{ElementType: tftypes.String},
+								Optional: true,
+							},
+							{
+								Name:     "args",
+								Type:     tftypes.List{ElementType: tftypes.String},
+								Optional: true,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// clusterRequiresReplace are the attributes of the "cluster" block which replace a resource when changed,
+// as they designate another cluster. Its credentials can change without affecting the resource.
+var clusterRequiresReplace = []*tftypes.AttributePath{
+	tftypes.NewAttributePath().WithAttributeName("cluster").WithElementKeyInt(0).WithAttributeName("config_context"),
+	tftypes.NewAttributePath().WithAttributeName("cluster").WithElementKeyInt(0).WithAttributeName("host"),
+}
+
+// clusterBlockForContext returns a "cluster" block of type t which selects a context of the kube config,
+// as set on import, or a null block when configContext is empty
+func clusterBlockForContext(t tftypes.Type, configContext string) tftypes.Value {
+	if configContext == "" {
+		return tftypes.NewValue(t, nil)
+	}
+	et := t.(tftypes.List).ElementType.(tftypes.Object)
+	attrs := make(map[string]tftypes.Value, len(et.AttributeTypes))
+	for k, at := range et.AttributeTypes {
+		attrs[k] = tftypes.NewValue(at, nil)
+	}
+	attrs["config_context"] = tftypes.NewValue(tftypes.String, configContext)
+	return tftypes.NewValue(t, []tftypes.Value{tftypes.NewValue(et, attrs)})
+}
+
+// forResourceCluster returns the server to handle a request about a resource of type typeName,
+// which is s unless the first of values which is not null, e.g. the planned state or
+// the prior state of a deleted resource, sets a "cluster" block.
+func (s *RawProviderServer) forResourceCluster(typeName string, values ...*tfprotov5.DynamicValue) (*RawProviderServer, []*tfprotov5.Diagnostic) {
+	rt, err := GetResourceType(typeName)
+	if err != nil {
+		// reported by the handler of the request
+		return s, nil
+	}
+	return s.forCluster(rt, values...)
+}
+
+// forDataSourceCluster returns the server to read a data source of type typeName
+func (s *RawProviderServer) forDataSourceCluster(typeName string, config *tfprotov5.DynamicValue) (*RawProviderServer, []*tfprotov5.Diagnostic) {
+	rt, err := GetDataSourceType(typeName)
+	if err != nil {
+		// reported by the handler of the request
+		return s, nil
+	}
+	return s.forCluster(rt, config)
+}
+
+func (s *RawProviderServer) forCluster(t tftypes.Type, values ...*tfprotov5.DynamicValue) (*RawProviderServer, []*tfprotov5.Diagnostic) {
+	for _, dv := range values {
+		if dv == nil {
+			continue
+		}
+		v, err := dv.Unmarshal(t)
+		if err != nil || v.IsNull() || !v.IsKnown() {
+			// invalid values are reported by the handler of the request
+			continue
+		}
+		return s.forClusterOfValue(v)
+	}
+	return s, nil
+}
+
+// forClusterOfValue returns the server for the "cluster" block of the value of a resource or data source
+func (s *RawProviderServer) forClusterOfValue(v tftypes.Value) (*RawProviderServer, []*tfprotov5.Diagnostic) {
+	var attrs map[string]tftypes.Value
+	if err := v.As(&attrs); err != nil {
+		// invalid values are reported by the handler of the request
+		return s, nil
+	}
+	return s.forClusterBlock(attrs["cluster"])
+}
+
+// forClusterBlock returns the server which uses the clients of the cluster set in a "cluster" block,
+// creating it when first requested, or s when the block is not set.
+func (s *RawProviderServer) forClusterBlock(v tftypes.Value) (*RawProviderServer, []*tfprotov5.Diagnostic) {
+	if v.IsNull() || s.offline != nil {
+		// there is no cluster to connect to in offline mode
+		return s, nil
+	}
+	if !v.IsFullyKnown() {
+		return s, []*tfprotov5.Diagnostic{{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Unknown cluster",
+			Detail:    "The attributes of the \"cluster\" block must be known when planning, as the schema of the resource is read from the cluster.",
+			Attribute: tftypes.NewAttributePath().WithAttributeName("cluster"),
+		}}
+	}
+	overrides, err := parseClusterBlock(v)
+	if err != nil {
+		return s, []*tfprotov5.Diagnostic{{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Invalid cluster",
+			Detail:    err.Error(),
+			Attribute: tftypes.NewAttributePath().WithAttributeName("cluster"),
+		}}
+	}
+	if overrides == nil {
+		return s, nil
+	}
+	// impersonation applies to all requests, including the ones to the cluster of a resource
+	setImpersonate(&overrides.AuthInfo, s.impersonate)
+	key, err := json.Marshal(overrides)
+	if err != nil {
+		return s, []*tfprotov5.Diagnostic{{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Invalid cluster",
+			Detail:   err.Error(),
+		}}
+	}
+
+	s.clusters.Lock()
+	defer s.clusters.Unlock()
+
+	if cs, ok := s.clusters.servers[string(key)]; ok {
+		return cs, nil
+	}
+
+	var cc clientcmd.ClientConfig
+	if overrides.CurrentContext != "" {
+		cc = s.kubeconfigClientConfig(overrides)
+	} else {
+		// a cluster configured without a context doesn't inherit anything from the kube config
+		cc = clientcmd.NewNonInteractiveClientConfig(clientcmdapi.Config{}, "", overrides, nil)
+	}
+	clientConfig, err := cc.ClientConfig()
+	if err != nil {
+		return s, []*tfprotov5.Diagnostic{{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Cannot load Kubernetes client config of cluster",
+			Detail:    err.Error(),
+			Attribute: tftypes.NewAttributePath().WithAttributeName("cluster"),
+		}}
+	}
+	s.completeClientConfig(clientConfig)
+	s.logger.Trace("[Cluster]", "[ClientConfig]", dump(*clientConfig))
+
+	cs := &RawProviderServer{
+		logger:           s.logger,
+		clientConfig:     clientConfig,
+		cacheDir:         s.cacheDir,
+		cacheTTL:         s.cacheTTL,
+		kubeconfigLoader: s.kubeconfigLoader,
+		kubeconfigRaw:    s.kubeconfigRaw,
+		clientOptions:    s.clientOptions,
+		impersonate:      s.impersonate,
+		providerEnabled:  s.providerEnabled,
+		hostTFVersion:    s.hostTFVersion,
+	}
+	if s.clusters.servers == nil {
+		s.clusters.servers = make(map[string]*RawProviderServer)
+	}
+	s.clusters.servers[string(key)] = cs
+	return cs, nil
+}
+
+// parseClusterBlock returns the overrides of the client configuration set in a "cluster" block,
+// or nil when the block is empty
+func parseClusterBlock(v tftypes.Value) (*clientcmd.ConfigOverrides, error) {
+	var blocks []tftypes.Value
+	if err := v.As(&blocks); err != nil {
+		return nil, err
+	}
+	if len(blocks) == 0 {
+		return nil, nil
+	}
+	var cluster map[string]tftypes.Value
+	if err := blocks[0].As(&cluster); err != nil {
+		return nil, err
+	}
+	str := func(name string) (string, error) {
+		var s string
+		if err := cluster[name].As(&s); err != nil {
+			return "", fmt.Errorf("failed to assert type of '%s' value: %s", name, err)
+		}
+		return s, nil
+	}
+
+	overrides := &clientcmd.ConfigOverrides{}
+	var err error
+	if overrides.CurrentContext, err = str("config_context"); err != nil {
+		return nil, err
+	}
+	if err := cluster["insecure"].As(&overrides.ClusterInfo.InsecureSkipTLSVerify); err != nil {
+		return nil, fmt.Errorf("failed to assert type of 'insecure' value: %s", err)
+	}
+	if overrides.ClusterInfo.TLSServerName, err = str("tls_server_name"); err != nil {
+		return nil, err
+	}
+	if ca, err := str("cluster_ca_certificate"); err != nil {
+		return nil, err
+	} else if ca != "" {
+		overrides.ClusterInfo.CertificateAuthorityData = []byte(ca)
+	}
+	if cert, err := str("client_certificate"); err != nil {
+		return nil, err
+	} else if cert != "" {
+		overrides.AuthInfo.ClientCertificateData = []byte(cert)
+	}
+	if key, err := str("client_key"); err != nil {
+		return nil, err
+	} else if key != "" {
+		overrides.AuthInfo.ClientKeyData = []byte(key)
+	}
+	if overrides.AuthInfo.Token, err = str("token"); err != nil {
+		return nil, err
+	}
+	if overrides.ClusterDefaults.ProxyURL, err = str("proxy_url"); err != nil {
+		return nil, err
+	}
+	if overrides.AuthInfo.Exec, err = parseClusterExecBlock(cluster["exec"]); err != nil {
+		return nil, err
+	}
+
+	host, err := str("host")
+	if err != nil {
+		return nil, err
+	}
+	if host != "" {
+		if _, err := url.ParseRequestURI(host); err != nil {
+			return nil, fmt.Errorf("'host' is not a valid URL: %s", err)
+		}
+		// see the handling of 'host' in ConfigureProvider
+		hasCA := len(overrides.ClusterInfo.CertificateAuthorityData) != 0
+		hasCert := len(overrides.AuthInfo.ClientCertificateData) != 0
+		defaultTLS := hasCA || hasCert || overrides.ClusterInfo.InsecureSkipTLSVerify
+		hostURL, _, err := rest.DefaultServerURL(host, "", apimachineryschema.GroupVersion{}, defaultTLS)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for 'host': %s", err)
+		}
+		overrides.ClusterInfo.Server = hostURL.String()
+	}
+	if overrides.CurrentContext == "" && overrides.ClusterInfo.Server == "" {
+		return nil, fmt.Errorf("either 'config_context' or 'host' must be set")
+	}
+	return overrides, nil
+}
+
+func parseClusterExecBlock(v tftypes.Value) (*clientcmdapi.ExecConfig, error) {
+	var blocks []tftypes.Value
+	if err := v.As(&blocks); err != nil {
+		return nil, fmt.Errorf("failed to assert type of 'exec' value: %s", err)
+	}
+	if len(blocks) == 0 {
+		return nil, nil
+	}
+	var attrs map[string]tftypes.Value
+	if err := blocks[0].As(&attrs); err != nil {
+		return nil, fmt.Errorf("failed to assert type of 'exec' block: %s", err)
+	}
+	exec := &clientcmdapi.ExecConfig{InteractiveMode: clientcmdapi.IfAvailableExecInteractiveMode}
+	if err := attrs["api_version"].As(&exec.APIVersion); err != nil {
+		return nil, fmt.Errorf("failed to assert type of 'api_version' value: %s", err)
+	}
+	if err := attrs["command"].As(&exec.Command); err != nil {
+		return nil, fmt.Errorf("failed to assert type of 'command' value: %s", err)
+	}
+	var args []tftypes.Value
+	if err := attrs["args"].As(&args); err != nil {
+		return nil, fmt.Errorf("failed to assert type of 'args' value: %s", err)
+	}
+	for _, a := range args {
+		var arg string
+		if err := a.As(&arg); err != nil {
+			return nil, fmt.Errorf("failed to assert type of element in 'args' value: %s", err)
+		}
+		exec.Args = append(exec.Args, arg)
+	}
+	var env map[string]tftypes.Value
+	if err := attrs["env"].As(&env); err != nil {
+		return nil, fmt.Errorf("failed to assert type of 'env' value: %s", err)
+	}
+	for k, e := range env {
+		var val string
+		if err := e.As(&val); err != nil {
+			return nil, fmt.Errorf("failed to assert type of element in 'env' value: %s", err)
+		}
+		exec.Env = append(exec.Env, clientcmdapi.ExecEnvVar{Name: k, Value: val})
+	}
+	// the configuration identifies the cluster in the cache of servers
+	sort.Slice(exec.Env, func(i, j int) bool { return exec.Env[i].Name < exec.Env[j].Name })
+	return exec, nil
+}
@@ -0,0 +1,126 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestForClusterBlock(t *testing.T) {
+	rt, err := GetResourceType("kubernetes_manifest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct := rt.(tftypes.Object).AttributeTypes["cluster"]
+	et := ct.(tftypes.List).ElementType.(tftypes.Object)
+	block := func(attrs map[string]tftypes.Value) tftypes.Value {
+		vals := make(map[string]tftypes.Value, len(et.AttributeTypes))
+		for k, at := range et.AttributeTypes {
+			vals[k] = tftypes.NewValue(at, nil)
+		}
+		for k, v := range attrs {
+			vals[k] = v
+		}
+		return tftypes.NewValue(ct, []tftypes.Value{tftypes.NewValue(et, vals)})
+	}
+
+	s := &RawProviderServer{logger: hclog.NewNullLogger()}
+	if cs, diags := s.forClusterBlock(tftypes.NewValue(ct, nil)); len(diags) > 0 || cs != s {
+		t.Fatalf("expected the server of the provider configuration without a cluster block, got diagnostics %v", diags)
+	}
+
+	a := block(map[string]tftypes.Value{
+		"host":  tftypes.NewValue(tftypes.String, "https://10.0.0.1:6443"),
+		"token": tftypes.NewValue(tftypes.String, "test"),
+		"exec": tftypes.NewValue(et.AttributeTypes["exec"], []tftypes.Value{
+			tftypes.NewValue(et.AttributeTypes["exec"].(tftypes.List).ElementType, map[string]tftypes.Value{
+				"api_version": tftypes.NewValue(tftypes.String, "client.authentication.k8s.io/v1beta1"),
+				"command":     tftypes.NewValue(tftypes.String, "get-token"),
+				"args":        tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{tftypes.NewValue(tftypes.String, "a")}),
+				"env": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+					"A": tftypes.NewValue(tftypes.String, "1"),
+					"B": tftypes.NewValue(tftypes.String, "2"),
+				}),
+			}),
+		}),
+	})
+	cs, diags := s.forClusterBlock(a)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+	if cs == s {
+		t.Fatal("expected a server for the cluster")
+	}
+	if cs.clientConfig.Host != "https://10.0.0.1:6443" || cs.clientConfig.BearerToken != "test" {
+		t.Fatalf("unexpected host %q or token %q", cs.clientConfig.Host, cs.clientConfig.BearerToken)
+	}
+	if cs.clientConfig.ExecProvider == nil || cs.clientConfig.ExecProvider.Command != "get-token" {
+		t.Fatalf("unexpected exec provider %#v", cs.clientConfig.ExecProvider)
+	}
+	for i := 0; i < 5; i++ {
+		if c, _ := s.forClusterBlock(a); c != cs {
+			t.Fatal("expected the server of a cluster to be reused")
+		}
+	}
+
+	b := block(map[string]tftypes.Value{
+		"host": tftypes.NewValue(tftypes.String, "https://10.0.0.2:6443"),
+	})
+	if c, _ := s.forClusterBlock(b); c == cs || c == s {
+		t.Fatal("expected another server for another cluster")
+	}
+
+	unknown := block(map[string]tftypes.Value{
+		"host": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+	})
+	if _, diags := s.forClusterBlock(unknown); len(diags) == 0 {
+		t.Fatal("expected an error for an unknown cluster")
+	}
+
+	empty := block(map[string]tftypes.Value{
+		"token": tftypes.NewValue(tftypes.String, "test"),
+	})
+	if _, diags := s.forClusterBlock(empty); len(diags) == 0 {
+		t.Fatal("expected an error for a cluster without a context or a host")
+	}
+
+	impersonating := &RawProviderServer{
+		logger: hclog.NewNullLogger(),
+		impersonate: clientcmdapi.AuthInfo{
+			Impersonate:          "system:serviceaccount:tenant:deployer",
+			ImpersonateGroups:    []string{"tenant-admins"},
+			ImpersonateUserExtra: map[string][]string{"scopes": {"view", "edit"}},
+		},
+	}
+	cs, diags = impersonating.forClusterBlock(b)
+	if len(diags) > 0 {
+		t.Fatal(diags)
+	}
+	imp := cs.clientConfig.Impersonate
+	if imp.UserName != "system:serviceaccount:tenant:deployer" {
+		t.Fatalf("expected the user of the provider configuration to be impersonated in the cluster, got %q", imp.UserName)
+	}
+	if len(imp.Groups) != 1 || imp.Groups[0] != "tenant-admins" {
+		t.Fatalf("unexpected groups: %v", imp.Groups)
+	}
+	if len(imp.Extra["scopes"]) != 2 {
+		t.Fatalf("unexpected extra fields: %v", imp.Extra)
+	}
+
+	if v := clusterBlockForContext(ct, ""); !v.IsNull() {
+		t.Fatalf("expected a null cluster block without a context, got %v", v)
+	}
+	imported := clusterBlockForContext(ct, "staging")
+	v, _, err := tftypes.WalkAttributePath(imported, tftypes.NewAttributePath().WithElementKeyInt(0).WithAttributeName("config_context"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v.(tftypes.Value).Equal(tftypes.NewValue(tftypes.String, "staging")) {
+		t.Fatalf("expected the context to be set in the cluster block, got %v", v)
+	}
+}
@@ -8,14 +8,17 @@ import (
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"math/big"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-provider-kubernetes/util"
 	"github.com/mitchellh/go-homedir"
 	"golang.org/x/mod/semver"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -120,6 +123,174 @@ func (s *RawProviderServer) ConfigureProvider(ctx context.Context, req *tfprotov
 		return response, nil
 	}
 
+	s.cacheTTL = defaultCacheTTL
+	if !providerConfig["cache"].IsNull() && providerConfig["cache"].IsKnown() {
+		var cacheBlock []tftypes.Value
+		err = providerConfig["cache"].As(&cacheBlock)
+		if err != nil {
+			// invalid configuration schema - this shouldn't happen, bail out now
+			response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Provider configuration: failed to extract 'cache' value",
+				Detail:   err.Error(),
+			})
+			return response, nil
+		}
+		if len(cacheBlock) > 0 {
+			var cacheObj map[string]tftypes.Value
+			err := cacheBlock[0].As(&cacheObj)
+			if err != nil {
+				// invalid configuration schema - this shouldn't happen, bail out now
+				response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+					Severity: tfprotov5.DiagnosticSeverityError,
+					Summary:  "Provider configuration: failed to extract 'cache' value",
+					Detail:   err.Error(),
+				})
+				return response, nil
+			}
+			if !cacheObj["directory"].IsNull() && cacheObj["directory"].IsKnown() {
+				err = cacheObj["directory"].As(&s.cacheDir)
+				if err != nil {
+					// invalid attribute type - this shouldn't happen, bail out for now
+					response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+						Severity: tfprotov5.DiagnosticSeverityError,
+						Summary:  "Provider configuration: failed to assert type of 'directory' value",
+						Detail:   err.Error(),
+					})
+					return response, nil
+				}
+				s.cacheDir, err = homedir.Expand(s.cacheDir)
+				if err != nil {
+					response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+						Severity: tfprotov5.DiagnosticSeverityError,
+						Summary:  "Provider configuration: cannot expand 'directory' value",
+						Detail:   err.Error(),
+					})
+					return response, nil
+				}
+			}
+			if !cacheObj["ttl"].IsNull() && cacheObj["ttl"].IsKnown() {
+				var ttl string
+				err = cacheObj["ttl"].As(&ttl)
+				if err != nil {
+					// invalid attribute type - this shouldn't happen, bail out for now
+					response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+						Severity: tfprotov5.DiagnosticSeverityError,
+						Summary:  "Provider configuration: failed to assert type of 'ttl' value",
+						Detail:   err.Error(),
+					})
+					return response, nil
+				}
+				s.cacheTTL, err = time.ParseDuration(ttl)
+				if err != nil {
+					response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+						Severity:  tfprotov5.DiagnosticSeverityError,
+						Summary:   "Provider configuration: invalid 'ttl' value",
+						Detail:    err.Error(),
+						Attribute: tftypes.NewAttributePath().WithAttributeName("cache").WithElementKeyInt(0).WithAttributeName("ttl"),
+					})
+					return response, nil
+				}
+			}
+		}
+	}
+
+	if !providerConfig["offline"].IsNull() && providerConfig["offline"].IsKnown() {
+		var offlineBlock []tftypes.Value
+		err = providerConfig["offline"].As(&offlineBlock)
+		if err != nil {
+			// invalid configuration schema - this shouldn't happen, bail out now
+			response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Provider configuration: failed to extract 'offline' value",
+				Detail:   err.Error(),
+			})
+			return response, nil
+		}
+		if len(offlineBlock) > 0 {
+			var offlineObj map[string]tftypes.Value
+			err := offlineBlock[0].As(&offlineObj)
+			if err != nil {
+				// invalid configuration schema - this shouldn't happen, bail out now
+				response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+					Severity: tfprotov5.DiagnosticSeverityError,
+					Summary:  "Provider configuration: failed to extract 'offline' value",
+					Detail:   err.Error(),
+				})
+				return response, nil
+			}
+			var specFile string
+			if !offlineObj["openapi_spec_file"].IsNull() && offlineObj["openapi_spec_file"].IsKnown() {
+				err = offlineObj["openapi_spec_file"].As(&specFile)
+				if err != nil {
+					// invalid attribute type - this shouldn't happen, bail out for now
+					response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+						Severity: tfprotov5.DiagnosticSeverityError,
+						Summary:  "Provider configuration: failed to assert type of 'openapi_spec_file' value",
+						Detail:   err.Error(),
+					})
+					return response, nil
+				}
+				specFile, err = homedir.Expand(specFile)
+				if err != nil {
+					response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+						Severity: tfprotov5.DiagnosticSeverityError,
+						Summary:  "Provider configuration: cannot expand 'openapi_spec_file' value",
+						Detail:   err.Error(),
+					})
+					return response, nil
+				}
+			}
+			var crdFiles []string
+			if !offlineObj["crd_files"].IsNull() && offlineObj["crd_files"].IsKnown() {
+				var crdFilesVals []tftypes.Value
+				err = offlineObj["crd_files"].As(&crdFilesVals)
+				if err != nil {
+					// invalid attribute type - this shouldn't happen, bail out for now
+					response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+						Severity: tfprotov5.DiagnosticSeverityError,
+						Summary:  "Provider configuration: failed to assert type of 'crd_files' value",
+						Detail:   err.Error(),
+					})
+					return response, nil
+				}
+				for _, v := range crdFilesVals {
+					var f string
+					if err := v.As(&f); err != nil {
+						response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+							Severity: tfprotov5.DiagnosticSeverityError,
+							Summary:  "Provider configuration: failed to assert type of 'crd_files' element",
+							Detail:   err.Error(),
+						})
+						return response, nil
+					}
+					f, err = homedir.Expand(f)
+					if err != nil {
+						response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+							Severity: tfprotov5.DiagnosticSeverityError,
+							Summary:  "Provider configuration: cannot expand 'crd_files' element",
+							Detail:   err.Error(),
+						})
+						return response, nil
+					}
+					crdFiles = append(crdFiles, f)
+				}
+			}
+			s.offline, err = loadOfflineSchema(specFile, crdFiles)
+			if err != nil {
+				response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+					Severity:  tfprotov5.DiagnosticSeverityError,
+					Summary:   "Provider configuration: failed to load offline schema",
+					Detail:    err.Error(),
+					Attribute: tftypes.NewAttributePath().WithAttributeName("offline").WithElementKeyInt(0),
+				})
+				return response, nil
+			}
+			// no client configuration is needed, all API calls are served from the offline schema
+			return response, nil
+		}
+	}
+
 	overrides := &clientcmd.ConfigOverrides{}
 	loader := &clientcmd.ClientConfigLoadingRules{}
 
@@ -199,6 +370,76 @@ func (s *RawProviderServer) ConfigureProvider(ctx context.Context, req *tfprotov
 		loader.Precedence = precedence
 	}
 
+	// Handle 'config_raw' attribute
+	//
+	var rawConfig *clientcmdapi.Config
+	if !providerConfig["config_raw"].IsNull() && providerConfig["config_raw"].IsKnown() {
+		var configRaw string
+		err = providerConfig["config_raw"].As(&configRaw)
+		if err != nil {
+			// invalid attribute - this shouldn't happen, bail out now
+			response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Provider configuration: failed to extract 'config_raw' value",
+				Detail:   err.Error(),
+			})
+			return response, nil
+		}
+		if len(configRaw) > 0 {
+			rawConfig, err = clientcmd.Load([]byte(configRaw))
+			if err != nil {
+				diags = append(diags, &tfprotov5.Diagnostic{
+					Severity: tfprotov5.DiagnosticSeverityInvalid,
+					Summary:  "Invalid attribute in provider configuration",
+					Detail:   "'config_raw' is not a valid kube config: " + err.Error(),
+				})
+			}
+		}
+	}
+
+	// Handle credential file attributes. The files are read by client-go, which re-reads
+	// client certificates when they change and tokens periodically, to pick up rotated credentials.
+	//
+	for _, f := range []struct {
+		name   string
+		target *string
+	}{
+		{"token_file", &overrides.AuthInfo.TokenFile},
+		{"client_certificate_file", &overrides.AuthInfo.ClientCertificate},
+		{"client_key_file", &overrides.AuthInfo.ClientKey},
+		{"cluster_ca_certificate_file", &overrides.ClusterInfo.CertificateAuthority},
+	} {
+		if providerConfig[f.name].IsNull() || !providerConfig[f.name].IsKnown() {
+			continue
+		}
+		var path string
+		err = providerConfig[f.name].As(&path)
+		if err != nil {
+			// invalid attribute type - this shouldn't happen, bail out for now
+			response.Diagnostics = append(response.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  fmt.Sprintf("Provider configuration: failed to assert type of '%s' value", f.name),
+				Detail:   err.Error(),
+			})
+			return response, nil
+		}
+		if len(path) == 0 {
+			continue
+		}
+		absPath, err := homedir.Expand(path)
+		if err == nil {
+			_, err = os.Stat(absPath)
+		}
+		if err != nil {
+			diags = append(diags, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityInvalid,
+				Summary:  "Invalid attribute in provider configuration",
+				Detail:   fmt.Sprintf("'%s' refers to an invalid path: %q: %v", f.name, absPath, err),
+			})
+		}
+		*f.target = absPath
+	}
+
 	// Handle 'client_certificate' attribute
 	//
 	var clientCertificate string
@@ -307,8 +548,8 @@ func (s *RawProviderServer) ConfigureProvider(ctx context.Context, req *tfprotov
 		overrides.ClusterInfo.TLSServerName = tlsServerName
 	}
 
-	hasCA := len(overrides.ClusterInfo.CertificateAuthorityData) != 0
-	hasCert := len(overrides.AuthInfo.ClientCertificateData) != 0
+	hasCA := len(overrides.ClusterInfo.CertificateAuthorityData) != 0 || overrides.ClusterInfo.CertificateAuthority != ""
+	hasCert := len(overrides.AuthInfo.ClientCertificateData) != 0 || overrides.AuthInfo.ClientCertificate != ""
 	defaultTLS := hasCA || hasCert || overrides.ClusterInfo.InsecureSkipTLSVerify
 
 	// Handle 'host' attribute
@@ -643,7 +884,25 @@ func (s *RawProviderServer) ConfigureProvider(ctx context.Context, req *tfprotov
 		}
 	}
 
-	cc := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loader, overrides)
+	var impersonate clientcmdapi.AuthInfo
+	if diags := parseImpersonateBlock(providerConfig["impersonate"], &impersonate); len(diags) > 0 {
+		response.Diagnostics = append(response.Diagnostics, diags...)
+		return response, nil
+	}
+	setImpersonate(&overrides.AuthInfo, impersonate)
+
+	clientOptions, diags := parseClientOptions(providerConfig)
+	if len(diags) > 0 {
+		response.Diagnostics = append(response.Diagnostics, diags...)
+		return response, nil
+	}
+	// kept to create the clients of the clusters targeted by the "cluster" block of resources
+	s.kubeconfigLoader = loader
+	s.kubeconfigRaw = rawConfig
+	s.clientOptions = clientOptions
+	s.impersonate = impersonate
+
+	cc := s.kubeconfigClientConfig(overrides)
 	clientConfig, err := cc.ClientConfig()
 	if err != nil {
 		s.logger.Error("[Configure]", "Failed to load config:", dump(cc))
@@ -659,17 +918,299 @@ func (s *RawProviderServer) ConfigureProvider(ctx context.Context, req *tfprotov
 		return response, nil
 	}
 
+	s.completeClientConfig(clientConfig)
+
+	s.logger.Trace("[Configure]", "[ClientConfig]", dump(*clientConfig))
+	s.clientConfig = clientConfig
+
+	return response, nil
+}
+
+// kubeconfigClientConfig returns the client configuration of the kube config of the provider configuration with overrides
+func (s *RawProviderServer) kubeconfigClientConfig(overrides *clientcmd.ConfigOverrides) clientcmd.ClientConfig {
+	if s.kubeconfigRaw != nil {
+		// an inline kube config takes precedence over 'config_path' and 'config_paths'
+		return clientcmd.NewNonInteractiveClientConfig(*s.kubeconfigRaw, overrides.CurrentContext, overrides, nil)
+	}
+	loader := s.kubeconfigLoader
+	if loader == nil {
+		loader = &clientcmd.ClientConfigLoadingRules{}
+	}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loader, overrides)
+}
+
+// completeClientConfig sets the request tracing, rate limiting, timeout, retries and serializer of a client configuration
+func (s *RawProviderServer) completeClientConfig(clientConfig *rest.Config) {
 	if s.logger.IsTrace() {
 		clientConfig.WrapTransport = loggingTransport
 	}
 
+	s.clientOptions.Apply(clientConfig)
+
 	codec := runtime.NoopEncoder{Decoder: scheme.Codecs.UniversalDecoder()}
 	clientConfig.NegotiatedSerializer = serializer.NegotiatedSerializerWrapper(runtime.SerializerInfo{Serializer: codec})
+}
 
-	s.logger.Trace("[Configure]", "[ClientConfig]", dump(*clientConfig))
-	s.clientConfig = clientConfig
+// parseImpersonateBlock sets the user, UID, groups and extra fields to impersonate from the "impersonate" block
+// of the provider configuration. They are applied by client-go as if set on the user of the kubeconfig.
+func parseImpersonateBlock(v tftypes.Value, authInfo *clientcmdapi.AuthInfo) []*tfprotov5.Diagnostic {
+	if v.IsNull() || !v.IsKnown() {
+		return nil
+	}
+	assertErr := func(name string, err error) []*tfprotov5.Diagnostic {
+		// invalid attribute type - this shouldn't happen, bail out for now
+		return []*tfprotov5.Diagnostic{{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  fmt.Sprintf("Provider configuration: failed to assert type of '%s' value", name),
+			Detail:   err.Error(),
+		}}
+	}
+	asStrings := func(v tftypes.Value) ([]string, error) {
+		var elems []tftypes.Value
+		if err := v.As(&elems); err != nil {
+			return nil, err
+		}
+		ss := make([]string, 0, len(elems))
+		for _, e := range elems {
+			var s string
+			if err := e.As(&s); err != nil {
+				return nil, err
+			}
+			ss = append(ss, s)
+		}
+		return ss, nil
+	}
 
-	return response, nil
+	var impersonateBlock []tftypes.Value
+	if err := v.As(&impersonateBlock); err != nil {
+		return assertErr("impersonate", err)
+	}
+	if len(impersonateBlock) == 0 {
+		return nil
+	}
+	var impersonateObj map[string]tftypes.Value
+	if err := impersonateBlock[0].As(&impersonateObj); err != nil {
+		return assertErr("impersonate", err)
+	}
+	if v := impersonateObj["user"]; !v.IsNull() && v.IsKnown() {
+		if err := v.As(&authInfo.Impersonate); err != nil {
+			return assertErr("user", err)
+		}
+	}
+	if v := impersonateObj["uid"]; !v.IsNull() && v.IsKnown() {
+		if err := v.As(&authInfo.ImpersonateUID); err != nil {
+			return assertErr("uid", err)
+		}
+	}
+	if v := impersonateObj["groups"]; !v.IsNull() && v.IsFullyKnown() {
+		groups, err := asStrings(v)
+		if err != nil {
+			return assertErr("groups", err)
+		}
+		authInfo.ImpersonateGroups = groups
+	}
+	if v := impersonateObj["extra"]; !v.IsNull() && v.IsFullyKnown() {
+		var extraBlocks []tftypes.Value
+		if err := v.As(&extraBlocks); err != nil {
+			return assertErr("extra", err)
+		}
+		for _, eb := range extraBlocks {
+			var extraObj map[string]tftypes.Value
+			if err := eb.As(&extraObj); err != nil {
+				return assertErr("extra", err)
+			}
+			var key string
+			if err := extraObj["key"].As(&key); err != nil {
+				return assertErr("key", err)
+			}
+			values, err := asStrings(extraObj["values"])
+			if err != nil {
+				return assertErr("values", err)
+			}
+			if authInfo.ImpersonateUserExtra == nil {
+				authInfo.ImpersonateUserExtra = make(map[string][]string)
+			}
+			authInfo.ImpersonateUserExtra[key] = append(authInfo.ImpersonateUserExtra[key], values...)
+		}
+	}
+	return nil
+}
+
+// setImpersonate copies the impersonation settings of imp to authInfo
+func setImpersonate(authInfo *clientcmdapi.AuthInfo, imp clientcmdapi.AuthInfo) {
+	authInfo.Impersonate = imp.Impersonate
+	authInfo.ImpersonateUID = imp.ImpersonateUID
+	authInfo.ImpersonateGroups = imp.ImpersonateGroups
+	authInfo.ImpersonateUserExtra = imp.ImpersonateUserExtra
+}
+
+// parseClientOptions reads the rate limiting, timeout and retry settings of the provider configuration.
+// These are applied to the client configuration the same way by the main provider.
+func parseClientOptions(providerConfig map[string]tftypes.Value) (util.ClientOptions, []*tfprotov5.Diagnostic) {
+	var opts util.ClientOptions
+
+	parseNumber := func(v tftypes.Value, path *tftypes.AttributePath) (*big.Float, *tfprotov5.Diagnostic) {
+		if v.IsNull() || !v.IsKnown() {
+			return nil, nil
+		}
+		var n big.Float
+		if err := v.As(&n); err != nil {
+			return nil, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   "Provider configuration: failed to assert type of number value",
+				Detail:    err.Error(),
+				Attribute: path,
+			}
+		}
+		if n.Sign() < 0 {
+			return nil, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   "Provider configuration: invalid number value",
+				Detail:    "The value must not be negative.",
+				Attribute: path,
+			}
+		}
+		return &n, nil
+	}
+	parseDuration := func(v tftypes.Value, path *tftypes.AttributePath) (*time.Duration, *tfprotov5.Diagnostic) {
+		if v.IsNull() || !v.IsKnown() {
+			return nil, nil
+		}
+		var ds string
+		if err := v.As(&ds); err != nil {
+			return nil, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   "Provider configuration: failed to assert type of duration value",
+				Detail:    err.Error(),
+				Attribute: path,
+			}
+		}
+		d, err := time.ParseDuration(ds)
+		if err == nil && d < 0 {
+			err = errors.New("the duration must not be negative")
+		}
+		if err != nil {
+			return nil, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   "Provider configuration: invalid duration value",
+				Detail:    err.Error(),
+				Attribute: path,
+			}
+		}
+		return &d, nil
+	}
+
+	root := tftypes.NewAttributePath()
+	qps, diag := parseNumber(providerConfig["qps"], root.WithAttributeName("qps"))
+	if diag != nil {
+		return opts, []*tfprotov5.Diagnostic{diag}
+	}
+	if qps != nil {
+		f, _ := qps.Float32()
+		opts.QPS = f
+	}
+	burst, diag := parseNumber(providerConfig["burst"], root.WithAttributeName("burst"))
+	if diag != nil {
+		return opts, []*tfprotov5.Diagnostic{diag}
+	}
+	if burst != nil {
+		b, _ := burst.Int64()
+		opts.Burst = int(b)
+	}
+	timeout, diag := parseDuration(providerConfig["request_timeout"], root.WithAttributeName("request_timeout"))
+	if diag != nil {
+		return opts, []*tfprotov5.Diagnostic{diag}
+	}
+	if timeout != nil {
+		opts.Timeout = *timeout
+	}
+
+	if providerConfig["retry"].IsNull() || !providerConfig["retry"].IsKnown() {
+		return opts, nil
+	}
+	var retryBlock []tftypes.Value
+	if err := providerConfig["retry"].As(&retryBlock); err != nil {
+		// invalid configuration schema - this shouldn't happen, bail out now
+		return opts, []*tfprotov5.Diagnostic{{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Provider configuration: failed to extract 'retry' value",
+			Detail:   err.Error(),
+		}}
+	}
+	if len(retryBlock) == 0 {
+		return opts, nil
+	}
+	var retryObj map[string]tftypes.Value
+	if err := retryBlock[0].As(&retryObj); err != nil {
+		// invalid configuration schema - this shouldn't happen, bail out now
+		return opts, []*tfprotov5.Diagnostic{{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Provider configuration: failed to extract 'retry' value",
+			Detail:   err.Error(),
+		}}
+	}
+	retryPath := root.WithAttributeName("retry").WithElementKeyInt(0)
+	opts.Retry = util.NewRetryPolicy()
+
+	maxAttempts, diag := parseNumber(retryObj["max_attempts"], retryPath.WithAttributeName("max_attempts"))
+	if diag != nil {
+		return opts, []*tfprotov5.Diagnostic{diag}
+	}
+	if maxAttempts != nil {
+		n, _ := maxAttempts.Int64()
+		if n > 0 {
+			opts.Retry.MaxAttempts = int(n)
+		}
+	}
+	minBackoff, diag := parseDuration(retryObj["min_backoff"], retryPath.WithAttributeName("min_backoff"))
+	if diag != nil {
+		return opts, []*tfprotov5.Diagnostic{diag}
+	}
+	if minBackoff != nil {
+		opts.Retry.MinBackoff = *minBackoff
+	}
+	maxBackoff, diag := parseDuration(retryObj["max_backoff"], retryPath.WithAttributeName("max_backoff"))
+	if diag != nil {
+		return opts, []*tfprotov5.Diagnostic{diag}
+	}
+	if maxBackoff != nil {
+		opts.Retry.MaxBackoff = *maxBackoff
+	}
+	if v := retryObj["retryable_status_codes"]; !v.IsNull() && v.IsFullyKnown() {
+		var codes []tftypes.Value
+		if err := v.As(&codes); err != nil {
+			return opts, []*tfprotov5.Diagnostic{{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   "Provider configuration: failed to assert type of 'retryable_status_codes' value",
+				Detail:    err.Error(),
+				Attribute: retryPath.WithAttributeName("retryable_status_codes"),
+			}}
+		}
+		if len(codes) > 0 {
+			opts.Retry.RetryableStatusCodes = make([]int, 0, len(codes))
+		}
+		for i, c := range codes {
+			code, diag := parseNumber(c, retryPath.WithAttributeName("retryable_status_codes").WithElementKeyInt(i))
+			if diag != nil {
+				return opts, []*tfprotov5.Diagnostic{diag}
+			}
+			if code != nil {
+				n, _ := code.Int64()
+				opts.Retry.RetryableStatusCodes = append(opts.Retry.RetryableStatusCodes, int(n))
+			}
+		}
+	}
+	if v := retryObj["retry_on_conflict"]; !v.IsNull() && v.IsKnown() {
+		if err := v.As(&opts.Retry.RetryOnConflict); err != nil {
+			return opts, []*tfprotov5.Diagnostic{{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   "Provider configuration: failed to assert type of 'retry_on_conflict' value",
+				Detail:    err.Error(),
+				Attribute: retryPath.WithAttributeName("retry_on_conflict"),
+			}}
+		}
+	}
+	return opts, nil
 }
 
 func (s *RawProviderServer) canExecute() (resp []*tfprotov5.Diagnostic) {
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
@@ -18,9 +19,16 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 )
 
 func (s *RawProviderServer) ReadDataSource(ctx context.Context, req *tfprotov5.ReadDataSourceRequest) (*tfprotov5.ReadDataSourceResponse, error) {
+	// the data source is read with the clients of the cluster it targets
+	s, diags := s.forDataSourceCluster(req.TypeName, req.Config)
+	if len(diags) > 0 {
+		return &tfprotov5.ReadDataSourceResponse{Diagnostics: diags}, nil
+	}
+
 	switch req.TypeName {
 	case "kubernetes_resource":
 		return s.ReadSingularDataSource(ctx, req)
@@ -145,20 +153,24 @@ func (s *RawProviderServer) ReadPluralDataSource(ctx context.Context, req *tfpro
 	listOptions := metav1.ListOptions{
 		LabelSelector: labelSelector,
 		FieldSelector: fieldSelector,
-		Limit:         lim,
 	}
 
-	var res *unstructured.UnstructuredList
+	var allNamespaces bool
+	if v, ok := dsConfig["all_namespaces"]; ok && !v.IsNull() && v.IsKnown() {
+		v.As(&allNamespaces)
+	}
 
-	if ns {
+	var items []unstructured.Unstructured
+	var truncated bool
+	if ns && !allNamespaces {
 		var namespace string
 		dsConfig["namespace"].As(&namespace)
 		if namespace == "" {
 			namespace = "default"
 		}
-		res, err = rcl.Namespace(namespace).List(ctx, listOptions)
+		items, truncated, err = listObjects(ctx, rcl.Namespace(namespace), listOptions, lim)
 	} else {
-		res, err = rcl.List(ctx, listOptions)
+		items, truncated, err = listObjects(ctx, rcl, listOptions, lim)
 	}
 	if err != nil {
 		if apierrors.IsNotFound(err) {
@@ -173,8 +185,46 @@ func (s *RawProviderServer) ReadPluralDataSource(ctx context.Context, req *tfpro
 		return resp, nil
 	}
 
+	if truncated {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityWarning,
+			Summary:   "List of objects truncated",
+			Detail:    fmt.Sprintf("More than %d objects match the data source, only the first %d are returned. Set \"limit\" to return more objects, or narrow down the list with \"label_selector\" or \"field_selector\".", defaultListLimit, defaultListLimit),
+			Attribute: tftypes.NewAttributePath().WithAttributeName("limit"),
+		})
+	}
+
+	var sortBy string
+	if v, ok := dsConfig["sort_by"]; ok && !v.IsNull() && v.IsKnown() {
+		v.As(&sortBy)
+	}
+	if err := sortObjects(items, sortBy); err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Invalid sort_by",
+			Detail:    err.Error(),
+			Attribute: tftypes.NewAttributePath().WithAttributeName("sort_by"),
+		})
+		return resp, nil
+	}
+
+	selectedFields := make(map[string]*tftypes.AttributePath)
+	if err := addFieldPaths(selectedFields, dsConfig["fields"]); err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Invalid fields",
+			Detail:    err.Error(),
+			Attribute: tftypes.NewAttributePath().WithAttributeName("fields"),
+		})
+		return resp, nil
+	}
+	var fields []*tftypes.AttributePath
+	for _, f := range selectedFields {
+		fields = append(fields, f)
+	}
+
 	listObjects := []tftypes.Value{}
-	for _, item := range res.Items {
+	for _, item := range items {
 		nobj, err := payload.ToTFValue(item.Object, objectType, th, tftypes.NewAttributePath())
 		if err != nil {
 			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
@@ -193,6 +243,18 @@ func (s *RawProviderServer) ReadPluralDataSource(ctx context.Context, req *tfpro
 			})
 			return resp, nil
 		}
+		if len(fields) > 0 {
+			nobj, err = selectFields(nobj, fields)
+			if err != nil {
+				resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+					Severity:  tfprotov5.DiagnosticSeverityError,
+					Summary:   "Failed to select fields",
+					Detail:    err.Error(),
+					Attribute: tftypes.NewAttributePath().WithAttributeName("fields"),
+				})
+				return resp, nil
+			}
+		}
 		listObjects = append(listObjects, nobj)
 	}
 
@@ -340,21 +402,59 @@ func (s *RawProviderServer) ReadSingularDataSource(ctx context.Context, req *tfp
 	var name string
 	metadata["name"].As(&name)
 
-	var res *unstructured.Unstructured
+	var rs dynamic.ResourceInterface
 	if ns {
 		var namespace string
 		metadata["namespace"].As(&namespace)
 		if namespace == "" {
 			namespace = "default"
 		}
-		res, err = rcl.Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		rs = rcl.Namespace(namespace)
+	} else {
+		rs = rcl
+	}
+
+	var waitConfig tftypes.Value
+	if w, ok := dsConfig["wait"]; ok && !w.IsNull() && w.IsKnown() {
+		var waitBlocks []tftypes.Value
+		w.As(&waitBlocks)
+		if len(waitBlocks) > 0 {
+			waitConfig = waitBlocks[0]
+		}
+	}
+	var waitForExists bool
+	if v, ok := dsConfig["wait_for_exists"]; ok && !v.IsNull() && v.IsKnown() {
+		v.As(&waitForExists)
+	}
+	timeout, _ := time.ParseDuration(s.getTimeouts(dsConfig)["read"])
+	ctxDeadline, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var res *unstructured.Unstructured
+	if waitForExists {
+		res, err = waitForResource(ctxDeadline, rs, name, s.logger)
 	} else {
-		res, err = rcl.Get(ctx, name, metav1.GetOptions{})
+		res, err = rs.Get(ctx, name, metav1.GetOptions{})
+	}
+	if err == nil && waitConfig.Type() != nil {
+		err = s.waitForCompletion(ctxDeadline, waitConfig, rs, name, objectType, th)
+		if err == nil {
+			// read the resource again, in the state the waiter saw it in
+			res, err = rs.Get(ctx, name, metav1.GetOptions{})
+		}
 	}
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			return resp, nil
 		}
+		if _, ok := err.(WaiterError); ok {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Timed out waiting for data source",
+				Detail:   fmt.Sprintf("The resource %q did not reach the expected state within the read timeout of %s: %s", name, timeout, err),
+			})
+			return resp, nil
+		}
 		d := tfprotov5.Diagnostic{
 			Severity: tfprotov5.DiagnosticSeverityError,
 			Summary:  fmt.Sprintf("Failed to get data source"),
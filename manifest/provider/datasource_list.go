@@ -0,0 +1,197 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// defaultListPageSize is the number of objects requested per page by the kubernetes_resources data source
+const defaultListPageSize int64 = 500
+
+// defaultListLimit is the maximum number of objects returned by the kubernetes_resources data source
+// when its "limit" attribute is not set, so that a broad selector doesn't fill the state with a whole cluster
+const defaultListLimit int64 = 10000
+
+// sortByValues are the supported values of the "sort_by" attribute of the kubernetes_resources data source
+var sortByValues = []string{"name", "creationTimestamp"}
+
+// lister is implemented by both the namespaced and cluster-wide dynamic resource clients
+type lister interface {
+	List(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error)
+}
+
+// listAllPages lists objects page by page, following the continue token of each response,
+// until all objects have been listed or limit is reached. A limit of 0 lists all objects.
+func listAllPages(ctx context.Context, l lister, opts metav1.ListOptions, limit int64) ([]unstructured.Unstructured, error) {
+	var items []unstructured.Unstructured
+	for {
+		opts.Limit = defaultListPageSize
+		if limit > 0 && limit-int64(len(items)) < opts.Limit {
+			opts.Limit = limit - int64(len(items))
+		}
+		res, err := l.List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, res.Items...)
+		if limit > 0 && int64(len(items)) >= limit {
+			return items[:limit], nil
+		}
+		opts.Continue = res.GetContinue()
+		if opts.Continue == "" {
+			return items, nil
+		}
+	}
+}
+
+// listObjects lists objects with listAllPages. Without a limit, at most defaultListLimit objects
+// are returned, and truncated reports whether more objects were left out.
+func listObjects(ctx context.Context, l lister, opts metav1.ListOptions, limit int64) (items []unstructured.Unstructured, truncated bool, err error) {
+	if limit > 0 {
+		items, err = listAllPages(ctx, l, opts, limit)
+		return items, false, err
+	}
+	// listing one more object than the default limit tells if any were left out
+	items, err = listAllPages(ctx, l, opts, defaultListLimit+1)
+	if err != nil {
+		return nil, false, err
+	}
+	if int64(len(items)) > defaultListLimit {
+		return items[:defaultListLimit], true, nil
+	}
+	return items, false, nil
+}
+
+// sortObjects sorts listed objects by name or by creation timestamp. Objects with
+// the same name, or created at the same time, are ordered by namespace and name.
+func sortObjects(items []unstructured.Unstructured, by string) error {
+	byNamespacedName := func(a, b *unstructured.Unstructured) bool {
+		if a.GetNamespace() != b.GetNamespace() {
+			return a.GetNamespace() < b.GetNamespace()
+		}
+		return a.GetName() < b.GetName()
+	}
+	switch by {
+	case "":
+	case "name":
+		sort.SliceStable(items, func(i, j int) bool {
+			if items[i].GetName() != items[j].GetName() {
+				return items[i].GetName() < items[j].GetName()
+			}
+			return byNamespacedName(&items[i], &items[j])
+		})
+	case "creationTimestamp":
+		sort.SliceStable(items, func(i, j int) bool {
+			ti, tj := items[i].GetCreationTimestamp(), items[j].GetCreationTimestamp()
+			if !ti.Equal(&tj) {
+				return ti.Before(&tj)
+			}
+			return byNamespacedName(&items[i], &items[j])
+		})
+	default:
+		return fmt.Errorf("unsupported value %q, must be one of %q", by, sortByValues)
+	}
+	return nil
+}
+
+// selectedField is a node of the tree of fields selected from an object
+type selectedField struct {
+	value    *tftypes.Value
+	children map[string]*selectedField
+}
+
+// selectFields returns an object holding only the given fields of an object, nested as in the original.
+// Map keys become attributes of the result, and fields which are not set are null.
+func selectFields(v tftypes.Value, fields []*tftypes.AttributePath) (tftypes.Value, error) {
+	root := &selectedField{}
+	for _, f := range fields {
+		fv, err := walkField(v, f)
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		node := root
+		for _, step := range f.Steps() {
+			var key string
+			switch s := step.(type) {
+			case tftypes.AttributeName:
+				key = string(s)
+			case tftypes.ElementKeyString:
+				key = string(s)
+			}
+			if node.children == nil {
+				node.children = make(map[string]*selectedField)
+			}
+			if _, ok := node.children[key]; !ok {
+				node.children[key] = &selectedField{}
+			}
+			node = node.children[key]
+		}
+		node.value = &fv
+	}
+	return root.toValue(), nil
+}
+
+func (f *selectedField) toValue() tftypes.Value {
+	if f.value != nil {
+		// a parent of other selected fields holds them all
+		return *f.value
+	}
+	types := make(map[string]tftypes.Type, len(f.children))
+	vals := make(map[string]tftypes.Value, len(f.children))
+	for k, c := range f.children {
+		vals[k] = c.toValue()
+		types[k] = vals[k].Type()
+	}
+	return tftypes.NewValue(tftypes.Object{AttributeTypes: types}, vals)
+}
+
+// walkField returns the value of a field in an object. Unlike tftypes.WalkAttributePath, the keys of maps
+// can be given as attribute names, and fields within null values are null rather than an error.
+func walkField(v tftypes.Value, p *tftypes.AttributePath) (tftypes.Value, error) {
+	for i, step := range p.Steps() {
+		var key string
+		switch s := step.(type) {
+		case tftypes.AttributeName:
+			key = string(s)
+		case tftypes.ElementKeyString:
+			key = string(s)
+		default:
+			return tftypes.Value{}, tftypes.NewAttributePathWithSteps(p.Steps()[:i+1]).NewErrorf("list elements cannot be selected")
+		}
+		var et tftypes.Type
+		switch t := v.Type().(type) {
+		case tftypes.Object:
+			at, ok := t.AttributeTypes[key]
+			if !ok {
+				return tftypes.Value{}, tftypes.NewAttributePathWithSteps(p.Steps()[:i+1]).NewErrorf("no such field")
+			}
+			et = at
+		case tftypes.Map:
+			et = t.ElementType
+		default:
+			return tftypes.Value{}, tftypes.NewAttributePathWithSteps(p.Steps()[:i+1]).NewErrorf("cannot select a field of a %s", typeKind(v.Type()))
+		}
+		if v.IsNull() || !v.IsKnown() {
+			v = tftypes.NewValue(et, nil)
+			continue
+		}
+		var m map[string]tftypes.Value
+		if err := v.As(&m); err != nil {
+			return tftypes.Value{}, err
+		}
+		ev, ok := m[key]
+		if !ok {
+			ev = tftypes.NewValue(et, nil)
+		}
+		v = ev
+	}
+	return v, nil
+}
@@ -0,0 +1,233 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// pagedLister serves a fixed number of objects in pages of at most the requested size
+type pagedLister struct {
+	total int
+	calls []metav1.ListOptions
+}
+
+func (l *pagedLister) List(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	l.calls = append(l.calls, opts)
+	start := 0
+	if opts.Continue != "" {
+		start, _ = strconv.Atoi(opts.Continue)
+	}
+	res := &unstructured.UnstructuredList{}
+	end := start + int(opts.Limit)
+	if end > l.total {
+		end = l.total
+	}
+	for i := start; i < end; i++ {
+		u := unstructured.Unstructured{}
+		u.SetName(fmt.Sprintf("obj-%d", i))
+		res.Items = append(res.Items, u)
+	}
+	if end < l.total {
+		res.SetContinue(strconv.Itoa(end))
+	}
+	return res, nil
+}
+
+func TestListAllPages(t *testing.T) {
+	samples := map[string]struct {
+		Total int
+		Limit int64
+		Items int
+		Calls int
+	}{
+		"single page":          {Total: 10, Items: 10, Calls: 1},
+		"multiple pages":       {Total: 1234, Items: 1234, Calls: 3},
+		"limit within page":    {Total: 1234, Limit: 20, Items: 20, Calls: 1},
+		"limit across pages":   {Total: 1234, Limit: 700, Items: 700, Calls: 2},
+		"limit above total":    {Total: 10, Limit: 700, Items: 10, Calls: 1},
+		"empty":                {Total: 0, Items: 0, Calls: 1},
+		"exact pages boundary": {Total: 1000, Items: 1000, Calls: 2},
+	}
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			l := &pagedLister{total: s.Total}
+			items, err := listAllPages(context.Background(), l, metav1.ListOptions{LabelSelector: "app=test"}, s.Limit)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(items) != s.Items {
+				t.Errorf("expected %d objects, got %d", s.Items, len(items))
+			}
+			if len(l.calls) != s.Calls {
+				t.Errorf("expected %d calls, got %d", s.Calls, len(l.calls))
+			}
+			for _, c := range l.calls {
+				if c.LabelSelector != "app=test" {
+					t.Errorf("expected the list options to be kept, got %v", c)
+				}
+			}
+			if len(items) > 0 && items[len(items)-1].GetName() != fmt.Sprintf("obj-%d", s.Items-1) {
+				t.Errorf("unexpected last object %q", items[len(items)-1].GetName())
+			}
+		})
+	}
+}
+
+func TestListObjects(t *testing.T) {
+	samples := map[string]struct {
+		Total     int
+		Limit     int64
+		Items     int
+		Truncated bool
+	}{
+		"below default limit": {Total: 1234, Items: 1234},
+		"at default limit":    {Total: int(defaultListLimit), Items: int(defaultListLimit)},
+		"above default limit": {Total: int(defaultListLimit) + 1, Items: int(defaultListLimit), Truncated: true},
+		"limit above default": {Total: int(defaultListLimit) + 1, Limit: defaultListLimit + 1, Items: int(defaultListLimit) + 1},
+		"limit below total":   {Total: 1234, Limit: 20, Items: 20},
+	}
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			l := &pagedLister{total: s.Total}
+			items, truncated, err := listObjects(context.Background(), l, metav1.ListOptions{}, s.Limit)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(items) != s.Items {
+				t.Errorf("expected %d objects, got %d", s.Items, len(items))
+			}
+			if truncated != s.Truncated {
+				t.Errorf("expected truncated to be %t", s.Truncated)
+			}
+		})
+	}
+}
+
+func TestSortObjects(t *testing.T) {
+	now := time.Now()
+	newObj := func(namespace, name string, age time.Duration) unstructured.Unstructured {
+		u := unstructured.Unstructured{}
+		u.SetNamespace(namespace)
+		u.SetName(name)
+		u.SetCreationTimestamp(metav1.NewTime(now.Add(-age)))
+		return u
+	}
+	names := func(items []unstructured.Unstructured) []string {
+		var out []string
+		for _, i := range items {
+			out = append(out, i.GetNamespace()+"/"+i.GetName())
+		}
+		return out
+	}
+	items := []unstructured.Unstructured{
+		newObj("b", "web", time.Hour),
+		newObj("a", "web", time.Minute),
+		newObj("a", "api", time.Minute),
+	}
+
+	if err := sortObjects(items, "name"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fmt.Sprint(names(items)) != "[a/api a/web b/web]" {
+		t.Errorf("unexpected order by name: %v", names(items))
+	}
+	if err := sortObjects(items, "creationTimestamp"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fmt.Sprint(names(items)) != "[b/web a/api a/web]" {
+		t.Errorf("unexpected order by creationTimestamp: %v", names(items))
+	}
+	if err := sortObjects(items, "size"); err == nil {
+		t.Error("expected an error for an unsupported value")
+	}
+}
+
+func TestSelectFields(t *testing.T) {
+	labelsType := tftypes.Map{ElementType: tftypes.String}
+	metadataType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"name":      tftypes.String,
+		"namespace": tftypes.String,
+		"labels":    labelsType,
+	}}
+	specType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"replicas": tftypes.Number,
+		"paused":   tftypes.Bool,
+	}}
+	objType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"metadata": metadataType,
+		"spec":     specType,
+	}}
+	obj := tftypes.NewValue(objType, map[string]tftypes.Value{
+		"metadata": tftypes.NewValue(metadataType, map[string]tftypes.Value{
+			"name":      tftypes.NewValue(tftypes.String, "web"),
+			"namespace": tftypes.NewValue(tftypes.String, "default"),
+			"labels": tftypes.NewValue(labelsType, map[string]tftypes.Value{
+				"app": tftypes.NewValue(tftypes.String, "web"),
+			}),
+		}),
+		"spec": tftypes.NewValue(specType, nil),
+	})
+	paths := []*tftypes.AttributePath{}
+	for _, f := range []string{"metadata.name", `metadata.labels["app"]`, "metadata.labels.tier", "spec.replicas"} {
+		p, err := FieldPathToTftypesPath(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, p)
+	}
+
+	out, err := selectFields(obj, paths)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	outLabelsType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"app": tftypes.String, "tier": tftypes.String}}
+	outMetadataType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"name": tftypes.String, "labels": outLabelsType}}
+	outSpecType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"replicas": tftypes.Number}}
+	expected := tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"metadata": outMetadataType,
+		"spec":     outSpecType,
+	}}, map[string]tftypes.Value{
+		"metadata": tftypes.NewValue(outMetadataType, map[string]tftypes.Value{
+			"name": tftypes.NewValue(tftypes.String, "web"),
+			"labels": tftypes.NewValue(outLabelsType, map[string]tftypes.Value{
+				"app":  tftypes.NewValue(tftypes.String, "web"),
+				"tier": tftypes.NewValue(tftypes.String, nil),
+			}),
+		}),
+		"spec": tftypes.NewValue(outSpecType, map[string]tftypes.Value{
+			"replicas": tftypes.NewValue(tftypes.Number, nil),
+		}),
+	})
+	if !out.Equal(expected) {
+		t.Fatalf("unexpected value:\n%s", out)
+	}
+
+	// a selected field holds all of its selected sub-fields
+	out, err = selectFields(obj, []*tftypes.AttributePath{
+		tftypes.NewAttributePath().WithAttributeName("metadata").WithAttributeName("name"),
+		tftypes.NewAttributePath().WithAttributeName("metadata"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !out.Type().(tftypes.Object).AttributeTypes["metadata"].Equal(metadataType) {
+		t.Fatalf("expected the whole metadata, got %s", out)
+	}
+
+	for _, f := range []string{"status.phase", "metadata.name.first", "spec.replicas[0]"} {
+		p, _ := FieldPathToTftypesPath(f)
+		if _, err := selectFields(obj, []*tftypes.AttributePath{p}); err == nil {
+			t.Errorf("expected an error for %q", f)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// finalizers handled by the garbage collector, which are kept when removing stuck finalizers
+var garbageCollectorFinalizers = map[string]bool{
+	metav1.FinalizerDeleteDependents: true,
+	metav1.FinalizerOrphanDependents: true,
+}
+
+// deleteConfig holds the options from the "delete" block
+type deleteConfig struct {
+	propagationPolicy     *metav1.DeletionPropagation
+	gracePeriodSeconds    *int64
+	abandon               bool
+	removeFinalizersAfter time.Duration
+}
+
+// getDeleteConfig extracts the options from the "delete" block of the resource
+func getDeleteConfig(v map[string]tftypes.Value) (deleteConfig, error) {
+	var cfg deleteConfig
+	dv, ok := v["delete"]
+	if !ok || dv.IsNull() || !dv.IsKnown() {
+		return cfg, nil
+	}
+	var deleteBlock []tftypes.Value
+	if err := dv.As(&deleteBlock); err != nil {
+		return cfg, err
+	}
+	if len(deleteBlock) == 0 {
+		return cfg, nil
+	}
+	var d map[string]tftypes.Value
+	if err := deleteBlock[0].As(&d); err != nil {
+		return cfg, err
+	}
+	if pp := d["propagation_policy"]; !pp.IsNull() && pp.IsKnown() {
+		var p string
+		if err := pp.As(&p); err != nil {
+			return cfg, err
+		}
+		policy := metav1.DeletionPropagation(p)
+		switch policy {
+		case metav1.DeletePropagationForeground, metav1.DeletePropagationBackground, metav1.DeletePropagationOrphan:
+		default:
+			return cfg, fmt.Errorf("invalid propagation_policy %q: must be one of %q, %q or %q", p,
+				metav1.DeletePropagationForeground, metav1.DeletePropagationBackground, metav1.DeletePropagationOrphan)
+		}
+		cfg.propagationPolicy = &policy
+	}
+	if gp := d["grace_period_seconds"]; !gp.IsNull() && gp.IsKnown() {
+		var n big.Float
+		if err := gp.As(&n); err != nil {
+			return cfg, err
+		}
+		secs, acc := n.Int64()
+		if acc != big.Exact || secs < 0 {
+			return cfg, fmt.Errorf("invalid grace_period_seconds %s: must be a non-negative whole number", n.String())
+		}
+		cfg.gracePeriodSeconds = &secs
+	}
+	if ab := d["abandon"]; !ab.IsNull() && ab.IsKnown() {
+		if err := ab.As(&cfg.abandon); err != nil {
+			return cfg, err
+		}
+	}
+	if rf := d["remove_finalizers_after"]; !rf.IsNull() && rf.IsKnown() {
+		var after string
+		if err := rf.As(&after); err != nil {
+			return cfg, err
+		}
+		dur, err := time.ParseDuration(after)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid remove_finalizers_after %q: %s", after, err)
+		}
+		cfg.removeFinalizersAfter = dur
+	}
+	return cfg, nil
+}
+
+// deleteOptions returns the options for the API delete call
+func (cfg deleteConfig) deleteOptions() metav1.DeleteOptions {
+	return metav1.DeleteOptions{
+		PropagationPolicy:  cfg.propagationPolicy,
+		GracePeriodSeconds: cfg.gracePeriodSeconds,
+	}
+}
+
+// DeletionTimeoutError is returned when the resource still exists once the delete timeout expires
+type DeletionTimeoutError struct {
+	// Finalizers the resource still had when the timeout expired
+	Finalizers []string
+}
+
+func (e DeletionTimeoutError) Error() string {
+	return "timed out waiting for resource to be deleted"
+}
+
+// waitForDeletion waits for a deleted resource to be gone from the API. With foreground deletion this
+// includes its dependents, which are removed by the garbage collector before the resource itself.
+// If configured, finalizers other than those of the garbage collector are removed once they have
+// held up the deletion for long enough.
+func (s *RawProviderServer) waitForDeletion(ctx context.Context, rs dynamic.ResourceInterface, rname string, cfg deleteConfig) error {
+	var stripAt time.Time
+	if cfg.removeFinalizersAfter > 0 {
+		stripAt = time.Now().Add(cfg.removeFinalizersAfter)
+	}
+	backoff := newWaiterBackoff()
+	for {
+		ro, err := rs.Get(ctx, rname, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				s.logger.Trace("[ApplyResourceChange][Delete]", "Resource is deleted")
+				return nil
+			}
+			if ctx.Err() != nil {
+				return DeletionTimeoutError{}
+			}
+			return err
+		}
+		finalizers := ro.GetFinalizers()
+		if !stripAt.IsZero() && time.Now().After(stripAt) {
+			var keep []string
+			for _, f := range finalizers {
+				if garbageCollectorFinalizers[f] {
+					keep = append(keep, f)
+				}
+			}
+			if len(keep) < len(finalizers) {
+				s.logger.Warn("[ApplyResourceChange][Delete]", "removing finalizers from", rname, "finalizers", dump(finalizers))
+				if err := removeFinalizers(ctx, rs, rname, ro.GetResourceVersion(), keep); err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+					return fmt.Errorf("failed to remove finalizers: %w", err)
+				}
+				continue
+			}
+		}
+		delay := backoff.Step()
+		if d := time.Until(stripAt); !stripAt.IsZero() && d > 0 && d < delay {
+			// the finalizers are removed on time, whatever the back-off
+			delay = d
+		}
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return DeletionTimeoutError{Finalizers: finalizers}
+		case <-t.C:
+		}
+	}
+}
+
+// removeFinalizers replaces the finalizers of the resource. The resourceVersion
+// makes sure no finalizer added since the resource was read is dropped.
+func removeFinalizers(ctx context.Context, rs dynamic.ResourceInterface, rname, resourceVersion string, keep []string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"finalizers":      keep,
+			"resourceVersion": resourceVersion,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = rs.Patch(ctx, rname, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
@@ -0,0 +1,191 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var testDeleteBlockType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+	"propagation_policy":      tftypes.String,
+	"grace_period_seconds":    tftypes.Number,
+	"abandon":                 tftypes.Bool,
+	"remove_finalizers_after": tftypes.String,
+}}
+
+func newTestDeleteBlock(attrs map[string]tftypes.Value) map[string]tftypes.Value {
+	v := map[string]tftypes.Value{
+		"propagation_policy":      tftypes.NewValue(tftypes.String, nil),
+		"grace_period_seconds":    tftypes.NewValue(tftypes.Number, nil),
+		"abandon":                 tftypes.NewValue(tftypes.Bool, nil),
+		"remove_finalizers_after": tftypes.NewValue(tftypes.String, nil),
+	}
+	for k, a := range attrs {
+		v[k] = a
+	}
+	return map[string]tftypes.Value{
+		"delete": tftypes.NewValue(tftypes.List{ElementType: testDeleteBlockType}, []tftypes.Value{
+			tftypes.NewValue(testDeleteBlockType, v),
+		}),
+	}
+}
+
+func TestGetDeleteConfig(t *testing.T) {
+	foreground := metav1.DeletePropagationForeground
+	var zero int64 = 0
+	var thirty int64 = 30
+
+	samples := map[string]struct {
+		In    map[string]tftypes.Value
+		Out   deleteConfig
+		Error bool
+	}{
+		"no block": {
+			In:  map[string]tftypes.Value{},
+			Out: deleteConfig{},
+		},
+		"empty block": {
+			In:  newTestDeleteBlock(nil),
+			Out: deleteConfig{},
+		},
+		"all options": {
+			In: newTestDeleteBlock(map[string]tftypes.Value{
+				"propagation_policy":      tftypes.NewValue(tftypes.String, "Foreground"),
+				"grace_period_seconds":    tftypes.NewValue(tftypes.Number, 30),
+				"abandon":                 tftypes.NewValue(tftypes.Bool, false),
+				"remove_finalizers_after": tftypes.NewValue(tftypes.String, "2m"),
+			}),
+			Out: deleteConfig{
+				propagationPolicy:     &foreground,
+				gracePeriodSeconds:    &thirty,
+				removeFinalizersAfter: 2 * time.Minute,
+			},
+		},
+		"zero grace period": {
+			In: newTestDeleteBlock(map[string]tftypes.Value{
+				"grace_period_seconds": tftypes.NewValue(tftypes.Number, 0),
+			}),
+			Out: deleteConfig{gracePeriodSeconds: &zero},
+		},
+		"abandon": {
+			In: newTestDeleteBlock(map[string]tftypes.Value{
+				"abandon": tftypes.NewValue(tftypes.Bool, true),
+			}),
+			Out: deleteConfig{abandon: true},
+		},
+		"invalid policy": {
+			In: newTestDeleteBlock(map[string]tftypes.Value{
+				"propagation_policy": tftypes.NewValue(tftypes.String, "foreground"),
+			}),
+			Error: true,
+		},
+		"negative grace period": {
+			In: newTestDeleteBlock(map[string]tftypes.Value{
+				"grace_period_seconds": tftypes.NewValue(tftypes.Number, -1),
+			}),
+			Error: true,
+		},
+		"fractional grace period": {
+			In: newTestDeleteBlock(map[string]tftypes.Value{
+				"grace_period_seconds": tftypes.NewValue(tftypes.Number, 1.5),
+			}),
+			Error: true,
+		},
+		"invalid duration": {
+			In: newTestDeleteBlock(map[string]tftypes.Value{
+				"remove_finalizers_after": tftypes.NewValue(tftypes.String, "10"),
+			}),
+			Error: true,
+		},
+	}
+
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := getDeleteConfig(s.In)
+			if s.Error {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(s.Out, cfg, cmp.AllowUnexported(deleteConfig{})); diff != "" {
+				t.Fatalf("unexpected config (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWaitForDeletion(t *testing.T) {
+	s := &RawProviderServer{logger: hclog.NewNullLogger()}
+
+	t.Run("deleted", func(t *testing.T) {
+		client, _ := newTestWaiterClient(newTestJob())
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.waitForDeletion(ctx, client.Resource(testJobGVR).Namespace("default"), "deleted", deleteConfig{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		job := newTestJob()
+		job.SetFinalizers([]string{"example.com/cleanup"})
+		client, _ := newTestWaiterClient(job)
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		err := s.waitForDeletion(ctx, client.Resource(testJobGVR).Namespace("default"), "test", deleteConfig{})
+		te, ok := err.(DeletionTimeoutError)
+		if !ok {
+			t.Fatalf("expected a DeletionTimeoutError, got: %v", err)
+		}
+		if diff := cmp.Diff([]string{"example.com/cleanup"}, te.Finalizers); diff != "" {
+			t.Fatalf("unexpected finalizers (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("remove finalizers", func(t *testing.T) {
+		job := newTestJob()
+		job.SetFinalizers([]string{"example.com/cleanup", metav1.FinalizerDeleteDependents})
+		job.SetResourceVersion("42")
+		client, _ := newTestWaiterClient(job)
+
+		// the fake client doesn't finish deletions, so remove the object once it is patched
+		var patch map[string]interface{}
+		client.PrependReactor("patch", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if err := json.Unmarshal(action.(k8stesting.PatchAction).GetPatch(), &patch); err != nil {
+				t.Fatal(err)
+			}
+			return true, nil, client.Tracker().Delete(testJobGVR, "default", "test")
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		err := s.waitForDeletion(ctx, client.Resource(testJobGVR).Namespace("default"), "test", deleteConfig{removeFinalizersAfter: time.Nanosecond})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"finalizers":      []interface{}{metav1.FinalizerDeleteDependents},
+				"resourceVersion": "42",
+			},
+		}
+		if diff := cmp.Diff(expected, patch); diff != "" {
+			t.Fatalf("unexpected patch (-want +got):\n%s", diff)
+		}
+	})
+}
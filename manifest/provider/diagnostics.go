@@ -5,13 +5,28 @@ package provider
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // APIStatusErrorToDiagnostics converts an Kubernetes API machinery StatusError into Terraform Diagnostics
 func APIStatusErrorToDiagnostics(s metav1.Status) []*tfprotov5.Diagnostic {
+	return apiStatusErrorToDiagnostics(s, func(string) *tftypes.AttributePath { return nil })
+}
+
+// APIStatusErrorToManifestDiagnostics converts an Kubernetes API machinery StatusError into Terraform Diagnostics,
+// pointing the diagnostic of each cause at the offending field in the "manifest" attribute.
+func APIStatusErrorToManifestDiagnostics(s metav1.Status, manifest tftypes.Value) []*tfprotov5.Diagnostic {
+	return apiStatusErrorToDiagnostics(s, func(field string) *tftypes.AttributePath {
+		return manifestPathForField(manifest, field)
+	})
+}
+
+func apiStatusErrorToDiagnostics(s metav1.Status, causePath func(field string) *tftypes.AttributePath) []*tfprotov5.Diagnostic {
 	var diags []*tfprotov5.Diagnostic
 	diags = append(diags, &tfprotov5.Diagnostic{
 		Severity: tfprotov5.DiagnosticSeverityError,
@@ -28,10 +43,82 @@ func APIStatusErrorToDiagnostics(s metav1.Status) []*tfprotov5.Diagnostic {
 	})
 	for _, c := range s.Details.Causes {
 		diags = append(diags, &tfprotov5.Diagnostic{
-			Severity: tfprotov5.DiagnosticSeverityError,
-			Detail:   c.Message,
-			Summary:  c.Field,
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Detail:    c.Message,
+			Summary:   c.Field,
+			Attribute: causePath(c.Field),
 		})
 	}
 	return diags
 }
+
+// manifestPathForField translates the path of a field as reported by the API, such as
+// "spec.template.spec.containers[0].image" or "metadata.labels[app.kubernetes.io/name]",
+// into a path within the "manifest" attribute. The path is cut short where the field is not
+// present in the manifest, so it points at the closest configured parent instead.
+// It returns nil when the field cannot be found in the manifest at all.
+func manifestPathForField(manifest tftypes.Value, field string) *tftypes.AttributePath {
+	if field == "" || field == "<nil>" || manifest.IsNull() || !manifest.IsKnown() {
+		return nil
+	}
+	fp, err := FieldPathToTftypesPath(apiFieldPathToHCL(field))
+	if err != nil {
+		return nil
+	}
+
+	path := tftypes.NewAttributePath().WithAttributeName("manifest")
+	cur := manifest
+	found := 0
+	for _, step := range fp.Steps() {
+		// keys of the manifest are usually object attributes, while the API path may treat them as map keys
+		if k, ok := step.(tftypes.ElementKeyString); ok && cur.Type().Is(tftypes.Object{}) {
+			step = tftypes.AttributeName(k)
+		}
+		if a, ok := step.(tftypes.AttributeName); ok && cur.Type().Is(tftypes.Map{}) {
+			step = tftypes.ElementKeyString(a)
+		}
+		next, _, err := tftypes.WalkAttributePath(cur, tftypes.NewAttributePathWithSteps([]tftypes.AttributePathStep{step}))
+		if err != nil {
+			break
+		}
+		nv, ok := next.(tftypes.Value)
+		if !ok || nv.IsNull() {
+			break
+		}
+		path = tftypes.NewAttributePathWithSteps(append(path.Steps(), step))
+		cur = nv
+		found++
+	}
+	if found == 0 {
+		return nil
+	}
+	return path
+}
+
+// apiFieldPathToHCL quotes the keys in a field path as printed by the API machinery (e.g. "data[tls.crt]"),
+// so it can be parsed by FieldPathToTftypesPath. List indices are left as they are.
+func apiFieldPathToHCL(field string) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(field, '[')
+		if start < 0 {
+			b.WriteString(field)
+			return b.String()
+		}
+		end := strings.IndexByte(field[start:], ']')
+		if end < 0 {
+			b.WriteString(field)
+			return b.String()
+		}
+		end += start
+		b.WriteString(field[:start+1])
+		key := field[start+1 : end]
+		if _, err := strconv.Atoi(key); err == nil || strings.HasPrefix(key, `"`) {
+			b.WriteString(key)
+		} else {
+			b.WriteString(strconv.Quote(key))
+		}
+		b.WriteByte(']')
+		field = field[end+1:]
+	}
+}
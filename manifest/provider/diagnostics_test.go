@@ -0,0 +1,150 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestDeploymentManifest() tftypes.Value {
+	containerType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"name":  tftypes.String,
+		"image": tftypes.String,
+	}}
+	labelsType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"app.kubernetes.io/name": tftypes.String,
+	}}
+	podSpecType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"containers": tftypes.Tuple{ElementTypes: []tftypes.Type{containerType}},
+	}}
+	templateType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"spec": podSpecType,
+	}}
+	specType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"template": templateType,
+	}}
+	metadataType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"name":   tftypes.String,
+		"labels": labelsType,
+	}}
+	return tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"apiVersion": tftypes.String,
+		"kind":       tftypes.String,
+		"metadata":   metadataType,
+		"spec":       specType,
+	}}, map[string]tftypes.Value{
+		"apiVersion": tftypes.NewValue(tftypes.String, "apps/v1"),
+		"kind":       tftypes.NewValue(tftypes.String, "Deployment"),
+		"metadata": tftypes.NewValue(metadataType, map[string]tftypes.Value{
+			"name": tftypes.NewValue(tftypes.String, "test"),
+			"labels": tftypes.NewValue(labelsType, map[string]tftypes.Value{
+				"app.kubernetes.io/name": tftypes.NewValue(tftypes.String, "test"),
+			}),
+		}),
+		"spec": tftypes.NewValue(specType, map[string]tftypes.Value{
+			"template": tftypes.NewValue(templateType, map[string]tftypes.Value{
+				"spec": tftypes.NewValue(podSpecType, map[string]tftypes.Value{
+					"containers": tftypes.NewValue(podSpecType.AttributeTypes["containers"], []tftypes.Value{
+						tftypes.NewValue(containerType, map[string]tftypes.Value{
+							"name":  tftypes.NewValue(tftypes.String, "nginx"),
+							"image": tftypes.NewValue(tftypes.String, ""),
+						}),
+					}),
+				}),
+			}),
+		}),
+	})
+}
+
+func TestManifestPathForField(t *testing.T) {
+	manifest := newTestDeploymentManifest()
+	m := tftypes.NewAttributePath().WithAttributeName("manifest")
+
+	samples := map[string]*tftypes.AttributePath{
+		"spec.template.spec.containers[0].image": m.WithAttributeName("spec").WithAttributeName("template").
+			WithAttributeName("spec").WithAttributeName("containers").WithElementKeyInt(0).WithAttributeName("image"),
+		"metadata.labels[app.kubernetes.io/name]": m.WithAttributeName("metadata").WithAttributeName("labels").
+			WithAttributeName("app.kubernetes.io/name"),
+		// fields which are not configured point at their closest configured parent
+		"spec.template.spec.containers[0].ports[0].containerPort": m.WithAttributeName("spec").WithAttributeName("template").
+			WithAttributeName("spec").WithAttributeName("containers").WithElementKeyInt(0),
+		"spec.replicas":   m.WithAttributeName("spec"),
+		"status.replicas": nil,
+		"":                nil,
+		"<nil>":           nil,
+	}
+	for field, expected := range samples {
+		t.Run(field, func(t *testing.T) {
+			p := manifestPathForField(manifest, field)
+			if expected == nil {
+				if p != nil {
+					t.Fatalf("expected no path, got %s", p)
+				}
+				return
+			}
+			if p == nil || !p.Equal(expected) {
+				t.Fatalf("expected path %s, got %s", expected, p)
+			}
+		})
+	}
+
+	// manifests morphed into the type of the resource hold maps, whose keys the API path prints as attributes
+	cm := newTestConfigMapValue(tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, nil), map[string]tftypes.Value{
+		"b": tftypes.NewValue(tftypes.String, "2"),
+	})
+	expected := m.WithAttributeName("data").WithElementKeyString("b")
+	if p := manifestPathForField(cm, "data.b"); p == nil || !p.Equal(expected) {
+		t.Fatalf("expected path %s, got %s", expected, p)
+	}
+}
+
+func TestAPIStatusErrorToManifestDiagnostics(t *testing.T) {
+	status := metav1.Status{
+		Status:  metav1.StatusFailure,
+		Message: `Deployment.apps "test" is invalid: spec.template.spec.containers[0].image: Required value`,
+		Reason:  metav1.StatusReasonInvalid,
+		Details: &metav1.StatusDetails{
+			Name:  "test",
+			Group: "apps",
+			Kind:  "Deployment",
+			Causes: []metav1.StatusCause{
+				{
+					Type:    metav1.CauseTypeFieldValueRequired,
+					Message: "Required value",
+					Field:   "spec.template.spec.containers[0].image",
+				},
+			},
+		},
+	}
+	diags := APIStatusErrorToManifestDiagnostics(status, newTestDeploymentManifest())
+	if len(diags) != 3 {
+		t.Fatalf("expected 3 diagnostics, got %d", len(diags))
+	}
+	expected := tftypes.NewAttributePath().WithAttributeName("manifest").WithAttributeName("spec").WithAttributeName("template").
+		WithAttributeName("spec").WithAttributeName("containers").WithElementKeyInt(0).WithAttributeName("image")
+	if diags[2].Attribute == nil || !diags[2].Attribute.Equal(expected) {
+		t.Fatalf("expected cause to point at %s, got %s", expected, diags[2].Attribute)
+	}
+	if diags[2].Detail != "Required value" {
+		t.Fatalf("unexpected detail %q", diags[2].Detail)
+	}
+}
+
+func TestAPIFieldPathToHCL(t *testing.T) {
+	samples := map[string]string{
+		"spec.containers[0].image":       "spec.containers[0].image",
+		"data[tls.crt]":                  `data["tls.crt"]`,
+		"metadata.labels[app]":           `metadata.labels["app"]`,
+		`metadata.annotations["quoted"]`: `metadata.annotations["quoted"]`,
+		"spec.unterminated[abc":          "spec.unterminated[abc",
+	}
+	for in, expected := range samples {
+		if out := apiFieldPathToHCL(in); out != expected {
+			t.Errorf("expected %q for %q, got %q", expected, in, out)
+		}
+	}
+}
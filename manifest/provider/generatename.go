@@ -0,0 +1,74 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// createWithGeneratedName creates a resource which only has a metadata.generateName, as server-side apply
+// needs the name of the resource. The resource is then applied under its generated name, and the "Update"
+// entry of the field manager left by the create request is removed, so that its fields are only owned
+// through server-side apply, as if the resource had been created by it. Otherwise fields removed from the
+// manifest later on would not be removed from the resource.
+//
+// An error is only returned when the resource could not be created. Failing to move the ownership
+// of its fields is logged, and the created resource returned, so that it is recorded in state.
+func (s *RawProviderServer) createWithGeneratedName(ctx context.Context, rs dynamic.ResourceInterface, obj *unstructured.Unstructured, fieldManager string, forceConflicts bool) (*unstructured.Unstructured, error) {
+	created, err := rs.Create(ctx, obj, metav1.CreateOptions{FieldManager: fieldManager})
+	if err != nil {
+		return nil, err
+	}
+	name := created.GetName()
+	s.logger.Debug("[ApplyResourceChange][Create]", "generated name", name)
+
+	applied := obj.DeepCopy()
+	applied.SetName(name)
+	jsonManifest, err := applied.MarshalJSON()
+	if err != nil {
+		s.logger.Warn("[ApplyResourceChange][Create]", "failed to marshall resource to JSON", err)
+		return created, nil
+	}
+	result, err := rs.Patch(ctx, name, types.ApplyPatchType, jsonManifest,
+		metav1.PatchOptions{
+			FieldManager: fieldManager,
+			Force:        &forceConflicts,
+		},
+	)
+	if err != nil {
+		s.logger.Warn("[ApplyResourceChange][Create]", "failed to apply created resource", err)
+		return created, nil
+	}
+
+	var managedFields []metav1.ManagedFieldsEntry
+	for _, mf := range result.GetManagedFields() {
+		if mf.Manager == fieldManager && mf.Operation == metav1.ManagedFieldsOperationUpdate {
+			continue
+		}
+		managedFields = append(managedFields, mf)
+	}
+	if len(managedFields) == len(result.GetManagedFields()) || len(managedFields) == 0 {
+		return result, nil
+	}
+	patch, err := json.Marshal([]map[string]interface{}{
+		{"op": "test", "path": "/metadata/resourceVersion", "value": result.GetResourceVersion()},
+		{"op": "replace", "path": "/metadata/managedFields", "value": managedFields},
+	})
+	if err != nil {
+		s.logger.Warn("[ApplyResourceChange][Create]", "failed to marshall managed fields patch", err)
+		return result, nil
+	}
+	migrated, err := rs.Patch(ctx, name, types.JSONPatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		s.logger.Warn("[ApplyResourceChange][Create]", "failed to remove managed fields of create request", err)
+		return result, nil
+	}
+	return migrated, nil
+}
@@ -0,0 +1,98 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestCreateWithGeneratedName(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme())
+
+	newJob := func(managers ...metav1.ManagedFieldsOperationType) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("batch/v1")
+		u.SetKind("Job")
+		u.SetNamespace("default")
+		u.SetGenerateName("migrate-")
+		u.SetName("migrate-x7k2p")
+		u.SetResourceVersion("2")
+		var mfs []metav1.ManagedFieldsEntry
+		for _, op := range managers {
+			mfs = append(mfs, metav1.ManagedFieldsEntry{
+				Manager:    "Terraform",
+				Operation:  op,
+				FieldsType: "FieldsV1",
+				FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:generateName":{}}}`)},
+			})
+		}
+		u.SetManagedFields(mfs)
+		return u
+	}
+
+	client.PrependReactor("create", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, newJob(metav1.ManagedFieldsOperationUpdate), nil
+	})
+	var migrated []metav1.ManagedFieldsEntry
+	client.PrependReactor("patch", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		pa := action.(k8stesting.PatchAction)
+		if pa.GetName() != "migrate-x7k2p" {
+			t.Fatalf("expected the generated name to be patched, got %q", pa.GetName())
+		}
+		switch pa.GetPatchType() {
+		case types.ApplyPatchType:
+			return true, newJob(metav1.ManagedFieldsOperationUpdate, metav1.ManagedFieldsOperationApply), nil
+		case types.JSONPatchType:
+			var ops []struct {
+				Op    string          `json:"op"`
+				Value json.RawMessage `json:"value"`
+			}
+			if err := json.Unmarshal(pa.GetPatch(), &ops); err != nil || len(ops) != 2 {
+				t.Fatalf("failed to decode patch: %v", err)
+			}
+			if err := json.Unmarshal(ops[1].Value, &migrated); err != nil {
+				t.Fatalf("failed to decode managed fields: %v", err)
+			}
+			return true, newJob(metav1.ManagedFieldsOperationApply), nil
+		}
+		t.Fatalf("unexpected patch type %q", pa.GetPatchType())
+		return true, nil, nil
+	})
+
+	s := &RawProviderServer{logger: hclog.NewNullLogger()}
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("batch/v1")
+	obj.SetKind("Job")
+	obj.SetNamespace("default")
+	obj.SetGenerateName("migrate-")
+
+	result, err := s.createWithGeneratedName(context.Background(), client.Resource(gvr).Namespace("default"), obj, "Terraform", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.GetName() != "migrate-x7k2p" {
+		t.Fatalf("expected the generated name, got %q", result.GetName())
+	}
+	if len(client.Actions()) != 3 {
+		t.Fatalf("expected 3 API calls, got %d", len(client.Actions()))
+	}
+	if len(migrated) != 1 || migrated[0].Operation != metav1.ManagedFieldsOperationApply {
+		t.Fatalf("expected only the Apply entry to be kept, got %v", migrated)
+	}
+	if obj.GetName() != "" {
+		t.Fatal("the manifest should not be modified")
+	}
+}
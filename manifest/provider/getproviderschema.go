@@ -9,6 +9,25 @@ import (
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 )
 
+// serverCapabilities are the optional protocol features supported by the provider
+var serverCapabilities = &tfprotov5.ServerCapabilities{
+	MoveResourceState: true,
+}
+
+// GetMetadata function
+func (s *RawProviderServer) GetMetadata(ctx context.Context, req *tfprotov5.GetMetadataRequest) (*tfprotov5.GetMetadataResponse, error) {
+	resp := &tfprotov5.GetMetadataResponse{
+		ServerCapabilities: serverCapabilities,
+	}
+	for name := range GetProviderResourceSchema() {
+		resp.Resources = append(resp.Resources, tfprotov5.ResourceMetadata{TypeName: name})
+	}
+	for name := range GetProviderDataSourceSchema() {
+		resp.DataSources = append(resp.DataSources, tfprotov5.DataSourceMetadata{TypeName: name})
+	}
+	return resp, nil
+}
+
 // GetProviderSchema function
 func (s *RawProviderServer) GetProviderSchema(ctx context.Context, req *tfprotov5.GetProviderSchemaRequest) (*tfprotov5.GetProviderSchemaResponse, error) {
 	cfgSchema := GetProviderConfigSchema()
@@ -16,8 +35,9 @@ func (s *RawProviderServer) GetProviderSchema(ctx context.Context, req *tfprotov
 	dsSchema := GetProviderDataSourceSchema()
 
 	return &tfprotov5.GetProviderSchemaResponse{
-		Provider:          cfgSchema,
-		ResourceSchemas:   resSchema,
-		DataSourceSchemas: dsSchema,
+		Provider:           cfgSchema,
+		ResourceSchemas:    resSchema,
+		DataSourceSchemas:  dsSchema,
+		ServerCapabilities: serverCapabilities,
 	}, nil
 }
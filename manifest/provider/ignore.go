@@ -0,0 +1,48 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// getIgnoreFields returns the paths of the fields listed in the "ignore_fields" attribute of a kubernetes_manifest resource.
+// These fields are managed by other actors, e.g. "spec.replicas" by a HorizontalPodAutoscaler, so they are left out
+// of the apply requests and of the "object" attribute.
+func getIgnoreFields(v map[string]tftypes.Value) (map[string]*tftypes.AttributePath, error) {
+	fields := make(map[string]*tftypes.AttributePath)
+	return fields, addFieldPaths(fields, v["ignore_fields"])
+}
+
+// removeIgnoredFields removes the ignored fields from a manifest or resource object. Object attributes
+// are set to null, so that the type of the value doesn't change, and map elements are removed,
+// matching the object that is read back from the API once the field is no longer owned by the provider.
+func removeIgnoredFields(v tftypes.Value, fields map[string]*tftypes.AttributePath) (tftypes.Value, error) {
+	if len(fields) == 0 || v.IsNull() || !v.IsKnown() {
+		return v, nil
+	}
+	return tftypes.Transform(v, func(ap *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
+		if _, ok := fields[fieldPathKey(ap.Steps())]; ok {
+			return tftypes.NewValue(v.Type(), nil), nil
+		}
+		if !v.Type().Is(tftypes.Map{}) || v.IsNull() || !v.IsKnown() {
+			return v, nil
+		}
+		var elems map[string]tftypes.Value
+		if err := v.As(&elems); err != nil {
+			return v, err
+		}
+		removed := false
+		for k := range elems {
+			if _, ok := fields[fieldPathKey(ap.WithElementKeyString(k).Steps())]; ok {
+				delete(elems, k)
+				removed = true
+			}
+		}
+		if !removed {
+			return v, nil
+		}
+		return tftypes.NewValue(v.Type(), elems), nil
+	})
+}
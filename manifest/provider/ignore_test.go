@@ -0,0 +1,102 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestGetIgnoreFields(t *testing.T) {
+	fields, err := getIgnoreFields(map[string]tftypes.Value{
+		"ignore_fields": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "spec.replicas"),
+			tftypes.NewValue(tftypes.String, `metadata.annotations["example.com/revision"]`),
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fields) != 2 {
+		t.Fatalf("expected 2 fields, got %d", len(fields))
+	}
+
+	fields, err = getIgnoreFields(map[string]tftypes.Value{})
+	if err != nil || len(fields) != 0 {
+		t.Fatalf("expected no fields, got %v (%v)", fields, err)
+	}
+
+	_, err = getIgnoreFields(map[string]tftypes.Value{
+		"ignore_fields": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "spec..replicas"),
+		}),
+	})
+	if err == nil {
+		t.Fatal("expected an error for an invalid path")
+	}
+}
+
+func TestRemoveIgnoredFields(t *testing.T) {
+	annotationsType := tftypes.Map{ElementType: tftypes.String}
+	metadataType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"name":        tftypes.String,
+		"annotations": annotationsType,
+	}}
+	specType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"replicas": tftypes.Number,
+		"paused":   tftypes.Bool,
+	}}
+	objType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"metadata": metadataType,
+		"spec":     specType,
+	}}
+	obj := tftypes.NewValue(objType, map[string]tftypes.Value{
+		"metadata": tftypes.NewValue(metadataType, map[string]tftypes.Value{
+			"name": tftypes.NewValue(tftypes.String, "test"),
+			"annotations": tftypes.NewValue(annotationsType, map[string]tftypes.Value{
+				"example.com/owner":    tftypes.NewValue(tftypes.String, "team"),
+				"example.com/revision": tftypes.NewValue(tftypes.String, "3"),
+			}),
+		}),
+		"spec": tftypes.NewValue(specType, map[string]tftypes.Value{
+			"replicas": tftypes.NewValue(tftypes.Number, 5),
+			"paused":   tftypes.NewValue(tftypes.Bool, false),
+		}),
+	})
+	fields := make(map[string]*tftypes.AttributePath)
+	for _, p := range []*tftypes.AttributePath{
+		tftypes.NewAttributePath().WithAttributeName("spec").WithAttributeName("replicas"),
+		// parsed from a path in the manifest, where map keys are attributes
+		tftypes.NewAttributePath().WithAttributeName("metadata").WithAttributeName("annotations").WithAttributeName("example.com/revision"),
+	} {
+		fields[fieldPathKey(p.Steps())] = p
+	}
+
+	out, err := removeIgnoredFields(obj, fields)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := tftypes.NewValue(objType, map[string]tftypes.Value{
+		"metadata": tftypes.NewValue(metadataType, map[string]tftypes.Value{
+			"name": tftypes.NewValue(tftypes.String, "test"),
+			"annotations": tftypes.NewValue(annotationsType, map[string]tftypes.Value{
+				"example.com/owner": tftypes.NewValue(tftypes.String, "team"),
+			}),
+		}),
+		"spec": tftypes.NewValue(specType, map[string]tftypes.Value{
+			"replicas": tftypes.NewValue(tftypes.Number, nil),
+			"paused":   tftypes.NewValue(tftypes.Bool, false),
+		}),
+	})
+	if !out.Equal(expected) {
+		t.Fatalf("unexpected value:\n%s", out)
+	}
+
+	unknown := tftypes.NewValue(objType, tftypes.UnknownValue)
+	out, err = removeIgnoredFields(unknown, fields)
+	if err != nil || !out.Equal(unknown) {
+		t.Fatalf("expected unknown value to be kept, got %s (%v)", out, err)
+	}
+}
@@ -13,8 +13,11 @@ import (
 	"github.com/hashicorp/terraform-provider-kubernetes/manifest/payload"
 	"github.com/hashicorp/terraform-provider-kubernetes/util"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/restmapper"
 )
 
 // ImportResourceState function
@@ -25,57 +28,129 @@ func (s *RawProviderServer) ImportResourceState(ctx context.Context, req *tfprot
 	// Presumably the Kubernetes API machinery already has a standard for expressing such a group. We should look there first.
 	resp := &tfprotov5.ImportResourceStateResponse{}
 
+	if req.TypeName == "kubernetes_manifest_set" {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Import is not supported",
+			Detail:   "Resources of type kubernetes_manifest_set cannot be imported.",
+		})
+		return resp, nil
+	}
+
 	execDiag := s.canExecute()
 	if len(execDiag) > 0 {
 		resp.Diagnostics = append(resp.Diagnostics, execDiag...)
 		return resp, nil
 	}
+	if d := s.canExecuteOnline("import"); len(d) > 0 {
+		resp.Diagnostics = append(resp.Diagnostics, d...)
+		return resp, nil
+	}
 
-	gvk, name, namespace, err := util.ParseResourceID(req.ID)
+	rt, err := GetResourceType(req.TypeName)
 	if err != nil {
 		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
 			Severity: tfprotov5.DiagnosticSeverityError,
-			Summary:  "Failed to parse import ID",
+			Summary:  "Failed to determine resource type",
 			Detail:   err.Error(),
 		})
+		return resp, nil
 	}
-	s.logger.Trace("[ImportResourceState]", "[ID]", gvk, name, namespace)
-	rt, err := GetResourceType(req.TypeName)
+
+	// the resource is imported from the cluster of the context prefixed to the ID, if any,
+	// which is kept in the state so that the resource is then read from the same cluster
+	configContext, id := util.ParseClusterImportID(req.ID)
+	cluster := clusterBlockForContext(rt.(tftypes.Object).AttributeTypes["cluster"], configContext)
+	s, diags := s.forClusterBlock(cluster)
+	if len(diags) > 0 {
+		resp.Diagnostics = append(resp.Diagnostics, diags...)
+		return resp, nil
+	}
+
+	rm, err := s.getRestMapper()
 	if err != nil {
 		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
 			Severity: tfprotov5.DiagnosticSeverityError,
-			Summary:  "Failed to determine resource type",
+			Summary:  "Failed to get RESTMapper client",
 			Detail:   err.Error(),
 		})
 		return resp, nil
 	}
-	rm, err := s.getRestMapper()
+	dc, err := s.getDiscoveryClient()
 	if err != nil {
 		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
 			Severity: tfprotov5.DiagnosticSeverityError,
-			Summary:  "Failed to get RESTMapper client",
+			Summary:  "Failed to get discovery client",
 			Detail:   err.Error(),
 		})
 		return resp, nil
 	}
-	client, err := s.getDynamicClient()
+	gvk, name, namespace, err := util.ParseResourceID(id, restmapper.NewShortcutExpander(rm, dc))
 	if err != nil {
 		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
 			Severity: tfprotov5.DiagnosticSeverityError,
-			Summary:  "failed to get Dynamic client",
+			Summary:  "Failed to parse import ID",
 			Detail:   err.Error(),
 		})
 		return resp, nil
 	}
-	ns, err := IsResourceNamespaced(gvk, rm)
+	s.logger.Trace("[ImportResourceState]", "[ID]", gvk, name, namespace)
+	nsVal, diags := s.importedState(ctx, rt, rm, gvk, name, namespace, cluster)
+	if len(diags) > 0 {
+		resp.Diagnostics = append(resp.Diagnostics, diags...)
+		return resp, nil
+	}
+	impState, err := tfprotov5.NewDynamicValue(nsVal.Type(), nsVal)
 	if err != nil {
 		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
 			Severity: tfprotov5.DiagnosticSeverityError,
-			Summary:  "Failed to get namespacing requirement from RESTMapper",
+			Summary:  "Failed to construct dynamic value for imported state",
 			Detail:   err.Error(),
 		})
 		return resp, nil
 	}
+	fb, err := importedPrivateState()
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityWarning,
+			Summary:  "Failed to earmark imported resource",
+			Detail:   err.Error(),
+		})
+	}
+	nr := &tfprotov5.ImportedResource{
+		TypeName: req.TypeName,
+		State:    &impState,
+		Private:  fb,
+	}
+	resp.ImportedResources = append(resp.ImportedResources, nr)
+	resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+		Severity: tfprotov5.DiagnosticSeverityWarning,
+		Summary:  "Apply needed after 'import'",
+		Detail:   "Please run apply after a successful import to realign the resource state to the configuration in Terraform.",
+	})
+	return resp, nil
+}
+
+// importedState reads the object gvk/namespace/name from the cluster and returns the state of a resource of type rt
+// managing it, as imported. Only the "object" attribute is set, the "manifest" attribute is filled from the
+// configuration in the next plan.
+func (s *RawProviderServer) importedState(ctx context.Context, rt tftypes.Type, rm meta.RESTMapper, gvk schema.GroupVersionKind, name, namespace string, cluster tftypes.Value) (tftypes.Value, []*tfprotov5.Diagnostic) {
+	client, err := s.getDynamicClient()
+	if err != nil {
+		return tftypes.Value{}, []*tfprotov5.Diagnostic{{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "failed to get Dynamic client",
+			Detail:   err.Error(),
+		}}
+	}
+	ns, err := IsResourceNamespaced(gvk, rm)
+	if err != nil {
+		return tftypes.Value{}, []*tfprotov5.Diagnostic{{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to get namespacing requirement from RESTMapper",
+			Detail:   err.Error(),
+		}}
+	}
 
 	io := unstructured.Unstructured{}
 	io.SetKind(gvk.Kind)
@@ -85,12 +160,11 @@ func (s *RawProviderServer) ImportResourceState(ctx context.Context, req *tfprot
 
 	gvr, err := GVRFromUnstructured(&io, rm)
 	if err != nil {
-		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+		return tftypes.Value{}, []*tfprotov5.Diagnostic{{
 			Severity: tfprotov5.DiagnosticSeverityError,
 			Summary:  "Failed to get GVR from GVK via RESTMapper",
 			Detail:   err.Error(),
-		})
-		return resp, nil
+		}}
 	}
 	rcl := client.Resource(gvr)
 
@@ -101,51 +175,62 @@ func (s *RawProviderServer) ImportResourceState(ctx context.Context, req *tfprot
 		ro, err = rcl.Get(ctx, name, metav1.GetOptions{})
 	}
 	if err != nil {
-		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+		return tftypes.Value{}, []*tfprotov5.Diagnostic{{
 			Severity: tfprotov5.DiagnosticSeverityError,
 			Summary:  fmt.Sprintf("Failed to get resource %+v from API", io),
 			Detail:   err.Error(),
-		})
-		return resp, nil
+		}}
+	}
+	// sensitive_fields is not known on import, only the defaults for the resource type apply
+	sensitiveFields := make(map[string]*tftypes.AttributePath)
+	for _, f := range defaultSensitiveFields[gvk] {
+		atp := tftypes.NewAttributePath().WithAttributeName(f)
+		sensitiveFields[fieldPathKey(atp.Steps())] = atp
 	}
-	s.logger.Trace("[ImportResourceState]", "[API Resource]", ro)
+	s.logger.Trace("[ImportResourceState]", "[API Resource]", dump(redactUnstructured(ro.Object, sensitiveFields)))
 
 	objectType, th, err := s.TFTypeFromOpenAPI(ctx, gvk, false)
 	if err != nil {
-		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+		return tftypes.Value{}, []*tfprotov5.Diagnostic{{
 			Severity: tfprotov5.DiagnosticSeverityError,
 			Summary:  fmt.Sprintf("Failed to determine resource type from GVK: %s", gvk),
 			Detail:   err.Error(),
-		})
-		return resp, nil
+		}}
 	}
 
 	fo := RemoveServerSideFields(ro.UnstructuredContent())
 	nobj, err := payload.ToTFValue(fo, objectType, th, tftypes.NewAttributePath())
 	if err != nil {
-		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+		return tftypes.Value{}, []*tfprotov5.Diagnostic{{
 			Severity: tfprotov5.DiagnosticSeverityError,
 			Summary:  "Failed to convert unstructured to tftypes.Value",
 			Detail:   err.Error(),
-		})
-		return resp, nil
+		}}
 	}
 	nobj, err = morph.DeepUnknown(objectType, nobj, tftypes.NewAttributePath())
 	if err != nil {
-		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+		return tftypes.Value{}, []*tfprotov5.Diagnostic{{
 			Severity: tfprotov5.DiagnosticSeverityError,
 			Summary:  "Failed to backfill unknown values during import",
 			Detail:   err.Error(),
-		})
-		return resp, nil
+		}}
 	}
-	s.logger.Trace("[ImportResourceState]", "[tftypes.Value]", nobj)
+	nobj, err = redactSensitiveFields(nobj, sensitiveFields)
+	if err != nil {
+		return tftypes.Value{}, []*tfprotov5.Diagnostic{{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to redact sensitive fields during import",
+			Detail:   err.Error(),
+		}}
+	}
+	s.logger.Trace("[ImportResourceState]", "[tftypes.Value]", dump(nobj))
 
 	newState := make(map[string]tftypes.Value)
 	wftype := rt.(tftypes.Object).AttributeTypes["wait_for"]
 	wtype := rt.(tftypes.Object).AttributeTypes["wait"]
 	timeoutsType := rt.(tftypes.Object).AttributeTypes["timeouts"]
 	fmType := rt.(tftypes.Object).AttributeTypes["field_manager"]
+	delType := rt.(tftypes.Object).AttributeTypes["delete"]
 	cmpType := rt.(tftypes.Object).AttributeTypes["computed_fields"]
 
 	newState["manifest"] = tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{}}, nil)
@@ -154,40 +239,20 @@ func (s *RawProviderServer) ImportResourceState(ctx context.Context, req *tfprot
 	newState["wait"] = tftypes.NewValue(wtype, nil)
 	newState["timeouts"] = tftypes.NewValue(timeoutsType, nil)
 	newState["field_manager"] = tftypes.NewValue(fmType, nil)
+	newState["delete"] = tftypes.NewValue(delType, nil)
+	newState["sensitive_fields"] = tftypes.NewValue(rt.(tftypes.Object).AttributeTypes["sensitive_fields"], nil)
+	newState["ignore_fields"] = tftypes.NewValue(rt.(tftypes.Object).AttributeTypes["ignore_fields"], nil)
+	newState["dry_run_on_plan"] = tftypes.NewValue(tftypes.Bool, nil)
 	newState["computed_fields"] = tftypes.NewValue(cmpType, nil)
+	newState["cluster"] = cluster
+	return tftypes.NewValue(rt, newState), nil
+}
 
-	nsVal := tftypes.NewValue(rt, newState)
-
-	impState, err := tfprotov5.NewDynamicValue(nsVal.Type(), nsVal)
-	if err != nil {
-		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
-			Severity: tfprotov5.DiagnosticSeverityError,
-			Summary:  "Failed to construct dynamic value for imported state",
-			Detail:   err.Error(),
-		})
-		return resp, nil
-	}
+// importedPrivateState returns the private state of a resource which flags it as imported,
+// so that it is not replaced when its "manifest" is first set in the next plan
+func importedPrivateState() ([]byte, error) {
 	impf := tftypes.NewValue(privateStateSchema,
 		map[string]tftypes.Value{"IsImported": tftypes.NewValue(tftypes.Bool, true)},
 	)
-	fb, err := impf.MarshalMsgPack(privateStateSchema)
-	if err != nil {
-		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
-			Severity: tfprotov5.DiagnosticSeverityWarning,
-			Summary:  "Failed to earmark imported resource",
-			Detail:   err.Error(),
-		})
-	}
-	nr := &tfprotov5.ImportedResource{
-		TypeName: req.TypeName,
-		State:    &impState,
-		Private:  fb,
-	}
-	resp.ImportedResources = append(resp.ImportedResources, nr)
-	resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
-		Severity: tfprotov5.DiagnosticSeverityWarning,
-		Summary:  "Apply needed after 'import'",
-		Detail:   "Please run apply after a successful import to realign the resource state to the configuration in Terraform.",
-	})
-	return resp, nil
+	return impf.MarshalMsgPack(privateStateSchema)
 }
@@ -0,0 +1,980 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-provider-kubernetes/manifest/payload"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+)
+
+// manifestSetKindOrder lists the kinds other resources commonly depend on, in the order
+// they are applied. Resources of any other kind are applied after these, in the order
+// they appear in the content. Deletion happens in reverse order.
+var manifestSetKindOrder = []string{
+	"Namespace",
+	"CustomResourceDefinition.apiextensions.k8s.io",
+	"ResourceQuota",
+	"LimitRange",
+	"PriorityClass.scheduling.k8s.io",
+	"ServiceAccount",
+	"Secret",
+	"ConfigMap",
+	"StorageClass.storage.k8s.io",
+	"PersistentVolume",
+	"PersistentVolumeClaim",
+	"ClusterRole.rbac.authorization.k8s.io",
+	"ClusterRoleBinding.rbac.authorization.k8s.io",
+	"Role.rbac.authorization.k8s.io",
+	"RoleBinding.rbac.authorization.k8s.io",
+	"Service",
+}
+
+var crdGroupKind = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+
+// manifestSetKindRank returns the position of a resource in the apply order
+func manifestSetKindRank(u *unstructured.Unstructured) int {
+	gk := u.GroupVersionKind().GroupKind().String()
+	for i, k := range manifestSetKindOrder {
+		if k == gk {
+			return i
+		}
+	}
+	return len(manifestSetKindOrder)
+}
+
+// sortManifestSet sorts resources in the order they should be applied
+func sortManifestSet(objs []*unstructured.Unstructured) {
+	sort.SliceStable(objs, func(i, j int) bool {
+		return manifestSetKindRank(objs[i]) < manifestSetKindRank(objs[j])
+	})
+}
+
+// manifestSetKey returns the key a resource is tracked by in the "objects" attribute
+func manifestSetKey(u *unstructured.Unstructured) string {
+	parts := []string{u.GetAPIVersion(), u.GetKind()}
+	if ns := u.GetNamespace(); ns != "" {
+		parts = append(parts, ns)
+	}
+	return strings.Join(append(parts, u.GetName()), "/")
+}
+
+// parseManifestSet decodes a multi-document YAML or JSON string into a list of resources.
+// Empty documents are skipped and List kinds are expanded into their items.
+func parseManifestSet(content string) ([]*unstructured.Unstructured, error) {
+	var objs []*unstructured.Unstructured
+	r := yaml.NewYAMLReader(bufio.NewReader(strings.NewReader(content)))
+	for i := 1; ; i++ {
+		doc, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read document %d: %s", i, err)
+		}
+		js, err := yaml.ToJSON(doc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse document %d: %s", i, err)
+		}
+		if js = bytes.TrimSpace(js); len(js) == 0 || string(js) == "null" {
+			continue
+		}
+		u := &unstructured.Unstructured{}
+		if err := u.UnmarshalJSON(js); err != nil {
+			return nil, fmt.Errorf("failed to decode document %d: %s", i, err)
+		}
+		if !u.IsList() {
+			objs = append(objs, u)
+			continue
+		}
+		l, err := u.ToList()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode list in document %d: %s", i, err)
+		}
+		for j := range l.Items {
+			objs = append(objs, &l.Items[j])
+		}
+	}
+
+	keys := make(map[string]bool, len(objs))
+	for _, u := range objs {
+		if u.GetAPIVersion() == "" || u.GetKind() == "" {
+			return nil, fmt.Errorf("resource %q must have \"apiVersion\" and \"kind\" set", u.GetName())
+		}
+		if u.GetName() == "" {
+			return nil, fmt.Errorf("%s resource must have \"metadata.name\" set", u.GetKind())
+		}
+		k := manifestSetKey(u)
+		if _, ok := u.Object["status"]; ok {
+			return nil, fmt.Errorf("resource %q: \"status\" is not allowed in manifest configuration", k)
+		}
+		if keys[k] {
+			return nil, fmt.Errorf("resource %q is defined more than once", k)
+		}
+		keys[k] = true
+	}
+	return objs, nil
+}
+
+// manifestSetObjectsFromValue decodes the "objects" attribute into resources keyed by manifestSetKey
+func manifestSetObjectsFromValue(v tftypes.Value) (map[string]*unstructured.Unstructured, error) {
+	objs := make(map[string]*unstructured.Unstructured)
+	if v.IsNull() || !v.IsKnown() {
+		return objs, nil
+	}
+	var vm map[string]tftypes.Value
+	err := v.As(&vm)
+	if err != nil {
+		return nil, err
+	}
+	for k, vv := range vm {
+		pu, err := payload.FromTFValue(vv, nil, tftypes.NewAttributePath().WithAttributeName(k))
+		if err != nil {
+			return nil, err
+		}
+		o, ok := pu.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected value for resource %q in state", k)
+		}
+		objs[k] = &unstructured.Unstructured{Object: o}
+	}
+	return objs, nil
+}
+
+// manifestSetObjectsToValue encodes resources keyed by manifestSetKey into the "objects" attribute
+func manifestSetObjectsToValue(objs map[string]*unstructured.Unstructured) (tftypes.Value, error) {
+	vals := make(map[string]tftypes.Value, len(objs))
+	types := make(map[string]tftypes.Type, len(objs))
+	for k, o := range objs {
+		v, err := payload.ToTFValue(mapRemoveNulls(o.Object), tftypes.DynamicPseudoType, nil, tftypes.NewAttributePath().WithAttributeName(k))
+		if err != nil {
+			return tftypes.Value{}, err
+		}
+		vals[k] = v
+		types[k] = v.Type()
+	}
+	return tftypes.NewValue(tftypes.Object{AttributeTypes: types}, vals), nil
+}
+
+// manifestSetResourceInterface returns a dynamic client for a resource of the set.
+// The RESTMapper is reset when the kind is not found, as its CRD may have just been applied.
+func (s *RawProviderServer) manifestSetResourceInterface(u *unstructured.Unstructured) (dynamic.ResourceInterface, error) {
+	c, err := s.getDynamicClient()
+	if err != nil {
+		return nil, err
+	}
+	m, err := s.getRestMapper()
+	if err != nil {
+		return nil, err
+	}
+	gvr, err := GVRFromUnstructured(u, m)
+	if meta.IsNoMatchError(err) {
+		s.resetRestMapper()
+		gvr, err = GVRFromUnstructured(u, m)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine resource GVR: %w", err)
+	}
+	ns, err := IsResourceNamespaced(u.GroupVersionKind(), m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover scope of resource: %s", err)
+	}
+	if !ns {
+		if u.GetNamespace() != "" {
+			return nil, fmt.Errorf("resources of type %q cannot have a namespace", u.GroupVersionKind())
+		}
+		return c.Resource(gvr), nil
+	}
+	if u.GetNamespace() == "" {
+		return nil, fmt.Errorf("resources of type %q require a namespace", u.GroupVersionKind())
+	}
+	return c.Resource(gvr).Namespace(u.GetNamespace()), nil
+}
+
+// deleteManifestSetObject deletes a resource of the set and waits until it is gone
+func (s *RawProviderServer) deleteManifestSetObject(ctx context.Context, u *unstructured.Unstructured) error {
+	rs, err := s.manifestSetResourceInterface(u)
+	if err != nil {
+		if meta.IsNoMatchError(errors.Unwrap(err)) {
+			// the resource type itself is gone, e.g. its CRD was deleted
+			return nil
+		}
+		return err
+	}
+	err = rs.Delete(ctx, u.GetName(), metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	backoff := newWaiterBackoff()
+	for {
+		_, err := rs.Get(ctx, u.GetName(), metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		t := time.NewTimer(backoff.Step())
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return fmt.Errorf("timed out waiting for the resource to be deleted. This can happen when there is a finalizer on a resource")
+		case <-t.C:
+		}
+	}
+}
+
+// manifestSetWriteOnlyFields lists the fields which are not returned by the API server,
+// so they are left out when comparing the resources with their desired state
+var manifestSetWriteOnlyFields = map[schema.GroupVersionKind][]string{
+	{Version: "v1", Kind: "Secret"}: {"stringData"},
+}
+
+// manifestSetSensitiveFields returns the paths of the sensitive fields of a resource of the set,
+// made of the "sensitive_fields" attribute and the defaults for the type of the resource
+func manifestSetSensitiveFields(u *unstructured.Unstructured, sensitiveFields tftypes.Value) (map[string]*tftypes.AttributePath, error) {
+	fields := make(map[string]*tftypes.AttributePath)
+	for _, f := range defaultSensitiveFields[u.GroupVersionKind()] {
+		atp := tftypes.NewAttributePath().WithAttributeName(f)
+		fields[fieldPathKey(atp.Steps())] = atp
+	}
+	return fields, addFieldPaths(fields, sensitiveFields)
+}
+
+// manifestSetObject prepares a resource read from the cluster to be stored in the "objects" attribute.
+// The values of its sensitive fields are redacted, marking the ones that differ from the desired resource.
+func manifestSetObject(in *unstructured.Unstructured, desired *unstructured.Unstructured, fields map[string]*tftypes.AttributePath) *unstructured.Unstructured {
+	var d interface{}
+	if desired != nil {
+		d = desired.Object
+	}
+	o := redactChangedUnstructured(RemoveServerSideFields(in.Object), d, fields)
+	return &unstructured.Unstructured{Object: o.(map[string]interface{})}
+}
+
+// manifestSetObjectDrifted tells if any of the fields set for a resource in the content of the set
+// has a different value in the resource as stored in the "objects" attribute
+func manifestSetObjectDrifted(stored *unstructured.Unstructured, desired *unstructured.Unstructured, fields map[string]*tftypes.AttributePath) bool {
+	d := desired.DeepCopy()
+	for _, f := range manifestSetWriteOnlyFields[d.GroupVersionKind()] {
+		unstructured.RemoveNestedField(d.Object, f)
+	}
+	// the stored sensitive values are placeholders, which only match if they have not changed
+	return !unstructuredContains(stored.Object, redactUnstructured(d.Object, fields))
+}
+
+// unstructuredContains tells if all the values set in desired are found in actual.
+// Lists must have the same length, fields and list elements missing in desired are ignored.
+func unstructuredContains(actual interface{}, desired interface{}) bool {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok && actual != nil {
+			return false
+		}
+		for k, dv := range d {
+			if !unstructuredContains(a[k], dv) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if (!ok && actual != nil) || len(a) != len(d) {
+			return false
+		}
+		for i := range d {
+			if !unstructuredContains(a[i], d[i]) {
+				return false
+			}
+		}
+		return true
+	case nil:
+		return true
+	}
+	return unstructuredValueEqual(actual, desired)
+}
+
+// manifestSetWaitBlocks returns the wait blocks that apply to a resource of the set
+func manifestSetWaitBlocks(v tftypes.Value, u *unstructured.Unstructured) []tftypes.Value {
+	var blocks, matching []tftypes.Value
+	if v.IsNull() || !v.IsKnown() {
+		return nil
+	}
+	v.As(&blocks)
+	for _, b := range blocks {
+		var w map[string]tftypes.Value
+		b.As(&w)
+		var kind, name string
+		if !w["kind"].IsNull() {
+			w["kind"].As(&kind)
+		}
+		if !w["name"].IsNull() {
+			w["name"].As(&name)
+		}
+		if (kind == "" || kind == u.GetKind()) && (name == "" || name == u.GetName()) {
+			matching = append(matching, b)
+		}
+	}
+	return matching
+}
+
+// manifestSetState assembles the new state of a kubernetes_manifest_set resource
+func manifestSetState(stateType tftypes.Type, stateVal map[string]tftypes.Value, objs map[string]*unstructured.Unstructured) (*tfprotov5.DynamicValue, error) {
+	ov, err := manifestSetObjectsToValue(objs)
+	if err != nil {
+		return nil, err
+	}
+	stateVal["objects"] = ov
+	newState, err := tfprotov5.NewDynamicValue(stateType, tftypes.NewValue(stateType, stateVal))
+	if err != nil {
+		return nil, err
+	}
+	return &newState, nil
+}
+
+// ValidateManifestSetConfig validates the configuration of a kubernetes_manifest_set resource
+func (s *RawProviderServer) ValidateManifestSetConfig(ctx context.Context, req *tfprotov5.ValidateResourceTypeConfigRequest) (*tfprotov5.ValidateResourceTypeConfigResponse, error) {
+	resp := &tfprotov5.ValidateResourceTypeConfigResponse{}
+
+	rt, err := GetResourceType(req.TypeName)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to determine resource type",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	config, err := req.Config.Unmarshal(rt)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to unmarshal resource state",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	configVal := make(map[string]tftypes.Value)
+	err = config.As(&configVal)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to extract resource state from SDK value",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+
+	if content := configVal["content"]; !content.IsNull() && content.IsKnown() {
+		var c string
+		content.As(&c)
+		if _, err := parseManifestSet(c); err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   "Invalid content",
+				Detail:    err.Error(),
+				Attribute: tftypes.NewAttributePath().WithAttributeName("content"),
+			})
+		}
+	}
+
+	resp.Diagnostics = append(resp.Diagnostics, s.validateTimeouts(configVal)...)
+
+	if err := addFieldPaths(map[string]*tftypes.AttributePath{}, configVal["sensitive_fields"]); err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Invalid sensitive_fields",
+			Detail:    err.Error(),
+			Attribute: tftypes.NewAttributePath().WithAttributeName("sensitive_fields"),
+		})
+	}
+
+	if wait := configVal["wait"]; !wait.IsNull() && wait.IsKnown() {
+		var waitBlocks []tftypes.Value
+		wait.As(&waitBlocks)
+		for i, b := range waitBlocks {
+			if !b.IsKnown() {
+				continue
+			}
+			var w map[string]tftypes.Value
+			b.As(&w)
+			resp.Diagnostics = append(resp.Diagnostics, validateWaitBlock(tftypes.NewAttributePath().WithAttributeName("wait").WithElementKeyInt(i), w)...)
+		}
+	}
+
+	return resp, nil
+}
+
+// PlanManifestSetChange plans changes to a kubernetes_manifest_set resource. The "objects"
+// attribute is only known ahead of apply when none of the resources need to be applied.
+func (s *RawProviderServer) PlanManifestSetChange(ctx context.Context, req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	resp := &tfprotov5.PlanResourceChangeResponse{}
+
+	execDiag := s.canExecute()
+	if len(execDiag) > 0 {
+		resp.Diagnostics = append(resp.Diagnostics, execDiag...)
+		return resp, nil
+	}
+
+	rt, err := GetResourceType(req.TypeName)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to determine planned resource type",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	proposedState, err := req.ProposedNewState.Unmarshal(rt)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to unmarshal planned resource state",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	if proposedState.IsNull() {
+		// we plan to delete the resources
+		resp.PlannedState = req.ProposedNewState
+		return resp, nil
+	}
+	resp.RequiresReplace = append(resp.RequiresReplace, clusterRequiresReplace...)
+	priorState, err := req.PriorState.Unmarshal(rt)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to unmarshal prior resource state",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+
+	proposedVal := make(map[string]tftypes.Value)
+	proposedState.As(&proposedVal)
+	priorVal := make(map[string]tftypes.Value)
+	priorState.As(&priorVal)
+
+	unchanged := false
+	if content := proposedVal["content"]; content.IsKnown() {
+		var c string
+		content.As(&c)
+		objs, err := parseManifestSet(c)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   "Invalid content",
+				Detail:    err.Error(),
+				Attribute: tftypes.NewAttributePath().WithAttributeName("content"),
+			})
+			return resp, nil
+		}
+		if !priorState.IsNull() &&
+			content.Equal(priorVal["content"]) &&
+			proposedVal["field_manager"].Equal(priorVal["field_manager"]) &&
+			proposedVal["sensitive_fields"].Equal(priorVal["sensitive_fields"]) {
+			// the resources only need to be applied again if some of them
+			// were deleted or changed outside of Terraform
+			priorObjs, err := manifestSetObjectsFromValue(priorVal["objects"])
+			if err != nil {
+				resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+					Severity:  tfprotov5.DiagnosticSeverityError,
+					Summary:   "Invalid prior state during planning",
+					Detail:    err.Error(),
+					Attribute: tftypes.NewAttributePath().WithAttributeName("objects"),
+				})
+				return resp, nil
+			}
+			unchanged = len(priorObjs) == len(objs)
+			for _, o := range objs {
+				po, ok := priorObjs[manifestSetKey(o)]
+				if !ok {
+					unchanged = false
+					break
+				}
+				fields, err := manifestSetSensitiveFields(o, proposedVal["sensitive_fields"])
+				if err != nil {
+					resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+						Severity:  tfprotov5.DiagnosticSeverityError,
+						Summary:   "Invalid sensitive_fields",
+						Detail:    err.Error(),
+						Attribute: tftypes.NewAttributePath().WithAttributeName("sensitive_fields"),
+					})
+					return resp, nil
+				}
+				if manifestSetObjectDrifted(po, o, fields) {
+					s.logger.Debug("[PlanManifestSetChange]", "Resource changed outside of Terraform", manifestSetKey(o))
+					unchanged = false
+					break
+				}
+			}
+		}
+	}
+	if unchanged {
+		proposedVal["objects"] = priorVal["objects"]
+	} else {
+		proposedVal["objects"] = tftypes.NewValue(tftypes.DynamicPseudoType, tftypes.UnknownValue)
+	}
+
+	propStateVal := tftypes.NewValue(proposedState.Type(), proposedVal)
+	plannedState, err := tfprotov5.NewDynamicValue(propStateVal.Type(), propStateVal)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to assemble proposed state during plan",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	resp.PlannedState = &plannedState
+	return resp, nil
+}
+
+// ApplyManifestSetChange applies the resources of a kubernetes_manifest_set in dependency order
+// and prunes the ones that were removed from its content
+func (s *RawProviderServer) ApplyManifestSetChange(ctx context.Context, req *tfprotov5.ApplyResourceChangeRequest) (*tfprotov5.ApplyResourceChangeResponse, error) {
+	resp := &tfprotov5.ApplyResourceChangeResponse{}
+
+	execDiag := s.canExecute()
+	if len(execDiag) > 0 {
+		resp.Diagnostics = append(resp.Diagnostics, execDiag...)
+		return resp, nil
+	}
+	if d := s.canExecuteOnline("apply"); len(d) > 0 {
+		resp.Diagnostics = append(resp.Diagnostics, d...)
+		return resp, nil
+	}
+
+	rt, err := GetResourceType(req.TypeName)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to determine planned resource type",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	plannedState, err := req.PlannedState.Unmarshal(rt)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to unmarshal planned resource state",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	priorState, err := req.PriorState.Unmarshal(rt)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to unmarshal prior resource state",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	plannedVal := make(map[string]tftypes.Value)
+	plannedState.As(&plannedVal)
+	priorVal := make(map[string]tftypes.Value)
+	priorState.As(&priorVal)
+
+	// state tracks the resources that exist on the cluster, so that
+	// they are recorded even when applying the set fails half way through
+	state, err := manifestSetObjectsFromValue(priorVal["objects"])
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to decode resources from prior state",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	stateVal, stateType := plannedVal, plannedState.Type()
+	if plannedState.IsNull() {
+		stateVal, stateType = priorVal, priorState.Type()
+	}
+	setState := func() {
+		ns, err := manifestSetState(stateType, stateVal, state)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Failed to assemble new resource state",
+				Detail:   err.Error(),
+			})
+			return
+		}
+		resp.NewState = ns
+	}
+
+	var desired []*unstructured.Unstructured
+	if !plannedState.IsNull() {
+		if plannedVal["objects"].IsKnown() {
+			// none of the resources need to be applied
+			resp.NewState = req.PlannedState
+			return resp, nil
+		}
+		var content string
+		plannedVal["content"].As(&content)
+		desired, err = parseManifestSet(content)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   "Invalid content",
+				Detail:    err.Error(),
+				Attribute: tftypes.NewAttributePath().WithAttributeName("content"),
+			})
+			return resp, nil
+		}
+		sortManifestSet(desired)
+	}
+
+	// figure out the timeout deadline
+	timeouts := s.getTimeouts(stateVal)
+	var timeout time.Duration
+	switch {
+	case plannedState.IsNull():
+		timeout, _ = time.ParseDuration(timeouts["delete"])
+	case priorState.IsNull():
+		timeout, _ = time.ParseDuration(timeouts["create"])
+	default:
+		timeout, _ = time.ParseDuration(timeouts["update"])
+	}
+	ctxDeadline, cancel := context.WithDeadline(ctx, time.Now().Add(timeout))
+	defer cancel()
+
+	fieldManagerName, forceConflicts, err := s.getFieldManagerConfig(stateVal)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Could not extract field_manager config",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+
+	desiredKeys := make(map[string]bool, len(desired))
+	for _, u := range desired {
+		k := manifestSetKey(u)
+		desiredKeys[k] = true
+
+		rs, err := s.manifestSetResourceInterface(u)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  fmt.Sprintf("Failed to determine the type of resource %q", k),
+				Detail:   err.Error(),
+			})
+			setState()
+			return resp, nil
+		}
+
+		// Check the resource does not exist if it is new to the set
+		if _, ok := state[k]; !ok {
+			_, err := rs.Get(ctxDeadline, u.GetName(), metav1.GetOptions{})
+			if err == nil {
+				resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+					Severity: tfprotov5.DiagnosticSeverityError,
+					Summary:  "Cannot create resource that already exists",
+					Detail:   fmt.Sprintf("resource %q already exists", k),
+				})
+				setState()
+				return resp, nil
+			} else if !apierrors.IsNotFound(err) {
+				resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+					Severity: tfprotov5.DiagnosticSeverityError,
+					Summary:  fmt.Sprintf("Failed to determine if resource %q exists", k),
+					Detail:   err.Error(),
+				})
+				setState()
+				return resp, nil
+			}
+		}
+
+		fields, err := manifestSetSensitiveFields(u, plannedVal["sensitive_fields"])
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   "Invalid sensitive_fields",
+				Detail:    err.Error(),
+				Attribute: tftypes.NewAttributePath().WithAttributeName("sensitive_fields"),
+			})
+			setState()
+			return resp, nil
+		}
+
+		jsonManifest, err := u.MarshalJSON()
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  fmt.Sprintf("Failed to marshall resource %q to JSON", k),
+				Detail:   err.Error(),
+			})
+			setState()
+			return resp, nil
+		}
+		s.logger.Trace("[ApplyManifestSetChange][API Payload]", "manifest", dump(redactUnstructured(u.Object, fields)))
+		result, err := rs.Patch(ctxDeadline, u.GetName(), types.ApplyPatchType, jsonManifest,
+			metav1.PatchOptions{
+				FieldManager: fieldManagerName,
+				Force:        &forceConflicts,
+			},
+		)
+		if err != nil {
+			s.logger.Error("[ApplyManifestSetChange][Apply]", "API error", dump(err))
+			if apierrors.IsConflict(err) {
+				resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+					Severity: tfprotov5.DiagnosticSeverityError,
+					Summary:  fmt.Sprintf(`There was a field manager conflict when trying to apply the manifest for %q`, k),
+					Detail: fmt.Sprintf(
+						"The API returned the following conflict: %q\n\n"+
+							"You can override this conflict by setting \"force_conflicts\" to true in the \"field_manager\" block.",
+						err.Error(),
+					),
+				})
+			} else if status := apierrors.APIStatus(nil); errors.As(err, &status) {
+				resp.Diagnostics = append(resp.Diagnostics, APIStatusErrorToDiagnostics(status.Status())...)
+			} else {
+				resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+					Severity: tfprotov5.DiagnosticSeverityError,
+					Summary:  fmt.Sprintf(`PATCH for resource %q failed to apply`, k),
+					Detail:   err.Error(),
+				})
+			}
+			setState()
+			return resp, nil
+		}
+		state[k] = manifestSetObject(result, u, fields)
+
+		var waiters []Waiter
+		if u.GroupVersionKind().GroupKind() == crdGroupKind {
+			// custom resources defined by this CRD can only be applied once it is established
+			waiters = append(waiters, &ConditionsWaiter{
+				rs,
+				u.GetName(),
+				[]ConditionMatcher{{conditionType: "Established", status: "True"}},
+				nil,
+				s.logger,
+			})
+		}
+		if waitBlocks := manifestSetWaitBlocks(plannedVal["wait"], u); len(waitBlocks) > 0 {
+			wt, th, err := s.TFTypeFromOpenAPI(ctxDeadline, u.GroupVersionKind(), true)
+			if err != nil {
+				resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+					Severity: tfprotov5.DiagnosticSeverityError,
+					Summary:  fmt.Sprintf("Failed to determine the type of resource %q", k),
+					Detail:   err.Error(),
+				})
+				setState()
+				return resp, nil
+			}
+			for _, b := range waitBlocks {
+				w, err := NewResourceWaiter(rs, u.GetName(), wt, th, b, s.logger)
+				if err != nil {
+					resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+						Severity: tfprotov5.DiagnosticSeverityError,
+						Summary:  "Invalid wait configuration",
+						Detail:   err.Error(),
+					})
+					setState()
+					return resp, nil
+				}
+				waiters = append(waiters, w)
+			}
+		}
+		for _, w := range waiters {
+			if err := w.Wait(ctxDeadline); err != nil {
+				resp.Diagnostics = append(resp.Diagnostics, WaiterErrorToDiagnostic(k, err))
+				setState()
+				return resp, nil
+			}
+		}
+		if len(waiters) > 0 {
+			r, err := rs.Get(ctx, u.GetName(), metav1.GetOptions{})
+			if err != nil {
+				resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+					Severity: tfprotov5.DiagnosticSeverityError,
+					Summary:  fmt.Sprintf(`Failed to read resource %q after wait conditions`, k),
+					Detail:   err.Error(),
+				})
+				setState()
+				return resp, nil
+			}
+			state[k] = manifestSetObject(r, u, fields)
+		}
+	}
+
+	// prune the resources that are no longer part of the set
+	var prune []*unstructured.Unstructured
+	for k, u := range state {
+		if !desiredKeys[k] {
+			prune = append(prune, u)
+		}
+	}
+	sort.SliceStable(prune, func(i, j int) bool {
+		return manifestSetKey(prune[i]) < manifestSetKey(prune[j])
+	})
+	sortManifestSet(prune)
+	for i := len(prune) - 1; i >= 0; i-- {
+		k := manifestSetKey(prune[i])
+		if err := s.deleteManifestSetObject(ctxDeadline, prune[i]); err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  fmt.Sprintf("Error deleting resource %q", k),
+				Detail:   err.Error(),
+			})
+			setState()
+			return resp, nil
+		}
+		s.logger.Trace("[ApplyManifestSetChange][Delete]", "Resource is deleted", k)
+		delete(state, k)
+	}
+
+	if plannedState.IsNull() {
+		resp.NewState = req.PlannedState
+		return resp, nil
+	}
+	setState()
+	return resp, nil
+}
+
+// ReadManifestSet refreshes the resources of a kubernetes_manifest_set. Resources that no longer
+// exist are dropped from the "objects" attribute so that they are applied again.
+func (s *RawProviderServer) ReadManifestSet(ctx context.Context, req *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+	resp := &tfprotov5.ReadResourceResponse{}
+	resp.Private = req.Private
+
+	execDiag := s.canExecute()
+	if len(execDiag) > 0 {
+		resp.Diagnostics = append(resp.Diagnostics, execDiag...)
+		return resp, nil
+	}
+	// there is no cluster to refresh from in offline mode
+	if s.offline != nil {
+		resp.NewState = req.CurrentState
+		return resp, nil
+	}
+
+	rt, err := GetResourceType(req.TypeName)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to determine resource type",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	currentState, err := req.CurrentState.Unmarshal(rt)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to decode current state",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	if currentState.IsNull() {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to read resource",
+			Detail:   "Incomplete of missing state",
+		})
+		return resp, nil
+	}
+	stateVal := make(map[string]tftypes.Value)
+	currentState.As(&stateVal)
+	objs, err := manifestSetObjectsFromValue(stateVal["objects"])
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to decode resources from current state",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+
+	// the resources in content tell which sensitive values were changed outside of Terraform
+	desired := make(map[string]*unstructured.Unstructured)
+	if content := stateVal["content"]; !content.IsNull() && content.IsKnown() {
+		var c string
+		content.As(&c)
+		// invalid content is reported during planning
+		dl, _ := parseManifestSet(c)
+		for _, u := range dl {
+			desired[manifestSetKey(u)] = u
+		}
+	}
+
+	for k, u := range objs {
+		fields, err := manifestSetSensitiveFields(u, stateVal["sensitive_fields"])
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   "Invalid sensitive_fields",
+				Detail:    err.Error(),
+				Attribute: tftypes.NewAttributePath().WithAttributeName("sensitive_fields"),
+			})
+			return resp, nil
+		}
+		rs, err := s.manifestSetResourceInterface(u)
+		if err != nil {
+			if meta.IsNoMatchError(errors.Unwrap(err)) {
+				delete(objs, k)
+				continue
+			}
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  fmt.Sprintf("Failed to determine the type of resource %q", k),
+				Detail:   err.Error(),
+			})
+			return resp, nil
+		}
+		ro, err := rs.Get(ctx, u.GetName(), metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				delete(objs, k)
+				continue
+			}
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  fmt.Sprintf("Cannot GET resource %q", k),
+				Detail:   err.Error(),
+			})
+			return resp, nil
+		}
+		objs[k] = manifestSetObject(ro, desired[k], fields)
+	}
+
+	newState, err := manifestSetState(currentState.Type(), stateVal, objs)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to assemble new resource state",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	resp.NewState = newState
+	return resp, nil
+}
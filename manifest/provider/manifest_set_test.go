@@ -0,0 +1,320 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestParseManifestSet(t *testing.T) {
+	samples := map[string]struct {
+		content string
+		keys    []string
+		err     string
+	}{
+		"multi-document": {
+			content: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: foo
+  namespace: default
+data:
+  replicas: "3"
+---
+# only a comment
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: bar
+`,
+			keys: []string{"v1/ConfigMap/default/foo", "v1/Namespace/bar"},
+		},
+		"json": {
+			content: `{"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "foo"}}`,
+			keys:    []string{"v1/Namespace/foo"},
+		},
+		"list": {
+			content: `
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Namespace
+  metadata:
+    name: foo
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: bar
+    namespace: foo
+`,
+			keys: []string{"v1/Namespace/foo", "apps/v1/Deployment/foo/bar"},
+		},
+		"empty": {
+			content: "",
+		},
+		"missing name": {
+			content: "apiVersion: v1\nkind: Namespace\n",
+			err:     `must have "metadata.name" set`,
+		},
+		"missing kind": {
+			content: "apiVersion: v1\nmetadata:\n  name: foo\n",
+			err:     "failed to decode document 1",
+		},
+		"duplicate": {
+			content: "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: foo\n---\napiVersion: v1\nkind: Namespace\nmetadata:\n  name: foo\n",
+			err:     `resource "v1/Namespace/foo" is defined more than once`,
+		},
+		"status": {
+			content: "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: foo\nstatus:\n  phase: Active\n",
+			err:     `"status" is not allowed`,
+		},
+		"invalid yaml": {
+			content: "apiVersion: v1\nkind: [Namespace\n",
+			err:     "failed to parse document 1",
+		},
+	}
+
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			objs, err := parseManifestSet(s.content)
+			if s.err != "" {
+				if err == nil || !strings.Contains(err.Error(), s.err) {
+					t.Fatalf("expected error containing %q, got: %v", s.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var keys []string
+			for _, o := range objs {
+				keys = append(keys, manifestSetKey(o))
+			}
+			if !cmp.Equal(keys, s.keys) {
+				t.Fatalf("unexpected keys: %s", cmp.Diff(s.keys, keys))
+			}
+		})
+	}
+}
+
+func TestSortManifestSet(t *testing.T) {
+	objs, err := parseManifestSet(`
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: w
+  namespace: ns
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: d
+  namespace: ns
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: s
+  namespace: ns
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: ns
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sortManifestSet(objs)
+	var kinds []string
+	for _, o := range objs {
+		kinds = append(kinds, o.GetKind())
+	}
+	expected := []string{"Namespace", "CustomResourceDefinition", "Service", "Widget", "Deployment"}
+	if !cmp.Equal(kinds, expected) {
+		t.Fatalf("unexpected order: %s", cmp.Diff(expected, kinds))
+	}
+}
+
+func TestManifestSetObjectsValue(t *testing.T) {
+	objs := map[string]*unstructured.Unstructured{
+		"v1/ConfigMap/default/foo": {Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name":              "foo",
+				"namespace":         "default",
+				"creationTimestamp": nil,
+			},
+			"data": map[string]interface{}{
+				"key": "value",
+			},
+		}},
+		"apps/v1/Deployment/default/bar": {Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name":      "bar",
+				"namespace": "default",
+			},
+			"spec": map[string]interface{}{
+				"replicas": int64(2),
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{"name": "nginx", "image": "nginx"},
+						},
+					},
+				},
+			},
+		}},
+	}
+	rt, err := GetResourceType("kubernetes_manifest_set")
+	if err != nil {
+		t.Fatalf("failed to get resource type: %v", err)
+	}
+	stateVal := map[string]tftypes.Value{}
+	for k, at := range rt.(tftypes.Object).AttributeTypes {
+		stateVal[k] = tftypes.NewValue(at, nil)
+	}
+	ns, err := manifestSetState(rt, stateVal, objs)
+	if err != nil {
+		t.Fatalf("failed to encode state: %v", err)
+	}
+	sv, err := ns.Unmarshal(rt)
+	if err != nil {
+		t.Fatalf("failed to decode state: %v", err)
+	}
+	sv.As(&stateVal)
+	decoded, err := manifestSetObjectsFromValue(stateVal["objects"])
+	if err != nil {
+		t.Fatalf("failed to decode objects: %v", err)
+	}
+	if len(decoded) != len(objs) {
+		t.Fatalf("expected %d objects, got %d", len(objs), len(decoded))
+	}
+	for k, o := range objs {
+		d, ok := decoded[k]
+		if !ok {
+			t.Fatalf("object %q is missing", k)
+		}
+		if manifestSetKey(d) != k {
+			t.Fatalf("object %q decoded with key %q", k, manifestSetKey(d))
+		}
+		if !cmp.Equal(d.Object["spec"], o.Object["spec"]) && o.Object["spec"] != nil {
+			t.Fatalf("unexpected spec for %q: %s", k, cmp.Diff(o.Object["spec"], d.Object["spec"]))
+		}
+	}
+}
+
+func TestManifestSetObjectDrifted(t *testing.T) {
+	desired, err := parseManifestSet(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: foo
+  namespace: default
+data:
+  password: aHVudGVyMg==
+stringData:
+  username: admin
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: bar
+  namespace: default
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	secret, deployment := desired[0], desired[1]
+	secretFields, err := manifestSetSensitiveFields(secret, tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	deploymentFields, err := manifestSetSensitiveFields(deployment, tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+		tftypes.NewValue(tftypes.String, "spec.template.spec.containers[0].image"),
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	readSecret := func(password string) *unstructured.Unstructured {
+		return manifestSetObject(&unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata": map[string]interface{}{
+				"name":            "foo",
+				"namespace":       "default",
+				"resourceVersion": "1",
+			},
+			"type": "Opaque",
+			"data": map[string]interface{}{
+				"password": password,
+				"username": "YWRtaW4=",
+			},
+		}}, secret, secretFields)
+	}
+	readDeployment := func(replicas int64, image string) *unstructured.Unstructured {
+		return manifestSetObject(&unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name":      "bar",
+				"namespace": "default",
+			},
+			"spec": map[string]interface{}{
+				"replicas": replicas,
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{"name": "nginx", "image": image, "imagePullPolicy": "Always"},
+						},
+					},
+				},
+			},
+		}}, deployment, deploymentFields)
+	}
+
+	s := readSecret("aHVudGVyMg==")
+	if s.Object["data"].(map[string]interface{})["password"] != sensitiveValuePlaceholder {
+		t.Fatalf("expected the secret data to be redacted, got %v", s.Object["data"])
+	}
+	if manifestSetObjectDrifted(s, secret, secretFields) {
+		t.Error("expected the secret to be unchanged")
+	}
+	if !manifestSetObjectDrifted(readSecret("Y2hhbmdlZA=="), secret, secretFields) {
+		t.Error("expected a change to the secret data to be detected")
+	}
+	if manifestSetObjectDrifted(readDeployment(2, "nginx"), deployment, deploymentFields) {
+		t.Error("expected the deployment to be unchanged")
+	}
+	if !manifestSetObjectDrifted(readDeployment(3, "nginx"), deployment, deploymentFields) {
+		t.Error("expected a change to the replicas to be detected")
+	}
+	if !manifestSetObjectDrifted(readDeployment(2, "nginx:latest"), deployment, deploymentFields) {
+		t.Error("expected a change to the sensitive image to be detected")
+	}
+}
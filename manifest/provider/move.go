@@ -0,0 +1,170 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-provider-kubernetes/util"
+)
+
+// MoveResourceState function
+//
+// The state of a typed resource of the provider, such as kubernetes_deployment_v1, is moved to a
+// kubernetes_manifest resource by reading the object it manages from its cluster, the same way
+// the object is imported. The object itself is left untouched.
+func (s *RawProviderServer) MoveResourceState(ctx context.Context, req *tfprotov5.MoveResourceStateRequest) (*tfprotov5.MoveResourceStateResponse, error) {
+	resp := &tfprotov5.MoveResourceStateResponse{}
+
+	if req.TargetTypeName != "kubernetes_manifest" {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Move is not supported",
+			Detail:   fmt.Sprintf("Resources cannot be moved to the %s resource type.", req.TargetTypeName),
+		})
+		return resp, nil
+	}
+	gvk, ok := util.TypedResourceKind(req.SourceTypeName)
+	if !ok || !util.IsKubernetesProviderAddress(req.SourceProviderAddress) {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Move is not supported",
+			Detail:   fmt.Sprintf("Resources of type %s of provider %s cannot be moved to kubernetes_manifest.", req.SourceTypeName, req.SourceProviderAddress),
+		})
+		return resp, nil
+	}
+
+	execDiag := s.canExecute()
+	if len(execDiag) > 0 {
+		resp.Diagnostics = append(resp.Diagnostics, execDiag...)
+		return resp, nil
+	}
+	if d := s.canExecuteOnline("move"); len(d) > 0 {
+		resp.Diagnostics = append(resp.Diagnostics, d...)
+		return resp, nil
+	}
+
+	rt, err := GetResourceType(req.TargetTypeName)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to determine resource type",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	name, namespace, cluster, err := parseTypedResourceState(req.SourceState, rt)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  fmt.Sprintf("Failed to read the state of the %s resource", req.SourceTypeName),
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	s.logger.Trace("[MoveResourceState]", "[Source]", req.SourceTypeName, gvk, name, namespace)
+
+	// the object is read from the cluster of the "cluster" block of the typed resource, which is kept
+	s, diags := s.forClusterBlock(cluster)
+	if len(diags) > 0 {
+		resp.Diagnostics = append(resp.Diagnostics, diags...)
+		return resp, nil
+	}
+	rm, err := s.getRestMapper()
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to get RESTMapper client",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	nsVal, diags := s.importedState(ctx, rt, rm, gvk, name, namespace, cluster)
+	if len(diags) > 0 {
+		resp.Diagnostics = append(resp.Diagnostics, diags...)
+		return resp, nil
+	}
+	targetState, err := tfprotov5.NewDynamicValue(nsVal.Type(), nsVal)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to construct dynamic value for moved state",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	// the moved resource is planned as an imported one, so that setting its manifest doesn't replace it
+	resp.TargetPrivate, err = importedPrivateState()
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to earmark moved resource",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	resp.TargetState = &targetState
+	return resp, nil
+}
+
+// parseTypedResourceState returns the name and namespace of the object managed by a typed resource of the
+// provider, and its "cluster" block as a value of the type of the one of rt, from the raw state of the resource
+func parseTypedResourceState(state *tfprotov5.RawState, rt tftypes.Type) (string, string, tftypes.Value, error) {
+	clusterType := rt.(tftypes.Object).AttributeTypes["cluster"]
+	metadataType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"name":      tftypes.String,
+		"namespace": tftypes.String,
+	}}
+	stateType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"metadata": tftypes.List{ElementType: metadataType},
+		"cluster":  clusterType,
+	}}
+	if state == nil {
+		return "", "", tftypes.Value{}, fmt.Errorf("the state is empty")
+	}
+	v, err := state.UnmarshalWithOpts(stateType, tfprotov5.UnmarshalOpts{
+		ValueFromJSONOpts: tftypes.ValueFromJSONOpts{IgnoreUndefinedAttributes: true},
+	})
+	if err != nil {
+		return "", "", tftypes.Value{}, err
+	}
+	var attrs map[string]tftypes.Value
+	if err := v.As(&attrs); err != nil {
+		return "", "", tftypes.Value{}, err
+	}
+	var metadata []tftypes.Value
+	if err := attrs["metadata"].As(&metadata); err != nil {
+		return "", "", tftypes.Value{}, err
+	}
+	if len(metadata) == 0 {
+		return "", "", tftypes.Value{}, fmt.Errorf("the state has no metadata")
+	}
+	var meta map[string]tftypes.Value
+	if err := metadata[0].As(&meta); err != nil {
+		return "", "", tftypes.Value{}, err
+	}
+	var name, namespace string
+	if err := meta["name"].As(&name); err != nil {
+		return "", "", tftypes.Value{}, err
+	}
+	if err := meta["namespace"].As(&namespace); err != nil {
+		return "", "", tftypes.Value{}, err
+	}
+	if name == "" {
+		return "", "", tftypes.Value{}, fmt.Errorf("the state has no metadata.name")
+	}
+	cluster := attrs["cluster"]
+	var blocks []tftypes.Value
+	if err := cluster.As(&blocks); err != nil {
+		return "", "", tftypes.Value{}, err
+	}
+	if len(blocks) == 0 {
+		// an empty list is stored for a typed resource without a "cluster" block
+		cluster = tftypes.NewValue(clusterType, nil)
+	}
+	return name, namespace, cluster, nil
+}
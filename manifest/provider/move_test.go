@@ -0,0 +1,215 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-provider-kubernetes/manifest/openapi"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+const testMoveSpec = `{
+  "swagger": "2.0",
+  "info": {"title": "Kubernetes", "version": "v1.25.0"},
+  "paths": {},
+  "definitions": {
+    "io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta": {
+      "type": "object",
+      "properties": {
+        "name": {"type": "string"},
+        "namespace": {"type": "string"}
+      }
+    },
+    "io.k8s.api.core.v1.ConfigMap": {
+      "type": "object",
+      "properties": {
+        "apiVersion": {"type": "string"},
+        "kind": {"type": "string"},
+        "metadata": {"$ref": "#/definitions/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"},
+        "data": {"type": "object", "additionalProperties": {"type": "string"}}
+      },
+      "x-kubernetes-group-version-kind": [{"group": "", "kind": "ConfigMap", "version": "v1"}]
+    }
+  }
+}`
+
+func newTestMoveServer(t *testing.T, objects ...runtime.Object) *RawProviderServer {
+	crds := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{crds: "CustomResourceDefinitionList"}, objects...)
+	rm := meta.NewDefaultRESTMapper([]schema.GroupVersion{{Version: "v1"}, crds.GroupVersion()})
+	rm.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	rm.Add(schema.GroupVersionKind{Group: crds.Group, Version: crds.Version, Kind: "CustomResourceDefinition"}, meta.RESTScopeRoot)
+	f, err := openapi.NewFoundryFromSpecV2([]byte(testMoveSpec))
+	if err != nil {
+		t.Fatalf("failed to create foundry: %v", err)
+	}
+	return &RawProviderServer{
+		logger:          hclog.NewNullLogger(),
+		providerEnabled: true,
+		dynamicClient:   client,
+		restMapper:      rm,
+		OAPIv3Foundry:   f,
+	}
+}
+
+func TestParseTypedResourceState(t *testing.T) {
+	rt, err := GetResourceType("kubernetes_manifest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	samples := map[string]struct {
+		state     string
+		name      string
+		namespace string
+		cluster   bool
+		err       bool
+	}{
+		"namespaced": {
+			state:     `{"id":"default/test","metadata":[{"name":"test","namespace":"default","labels":{"a":"b"}}],"data":{"a":"1"},"cluster":[]}`,
+			name:      "test",
+			namespace: "default",
+		},
+		"cluster-wide": {
+			state: `{"id":"test","metadata":[{"name":"test","generation":1}]}`,
+			name:  "test",
+		},
+		"with cluster block": {
+			state:     `{"id":"default/test","metadata":[{"name":"test","namespace":"default"}],"cluster":[{"config_context":"other"}]}`,
+			name:      "test",
+			namespace: "default",
+			cluster:   true,
+		},
+		"without metadata": {
+			state: `{"id":"test","metadata":[]}`,
+			err:   true,
+		},
+	}
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			n, ns, cluster, err := parseTypedResourceState(&tfprotov5.RawState{JSON: []byte(s.state)}, rt)
+			if s.err {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if n != s.name || ns != s.namespace {
+				t.Errorf("expected %s/%s, got %s/%s", s.namespace, s.name, ns, n)
+			}
+			if cluster.IsNull() == s.cluster {
+				t.Errorf("unexpected cluster block: %s", cluster)
+			}
+		})
+	}
+}
+
+func TestMoveResourceState(t *testing.T) {
+	cm := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":      "test",
+			"namespace": "default",
+		},
+		"data": map[string]interface{}{"a": "1"},
+	}}
+	state := &tfprotov5.RawState{JSON: []byte(`{"id":"default/test","metadata":[{"name":"test","namespace":"default"}],"data":{"a":"1"},"cluster":[]}`)}
+
+	t.Run("typed resource", func(t *testing.T) {
+		s := newTestMoveServer(t, cm)
+		resp, err := s.MoveResourceState(context.Background(), &tfprotov5.MoveResourceStateRequest{
+			SourceProviderAddress: "registry.terraform.io/hashicorp/kubernetes",
+			SourceTypeName:        "kubernetes_config_map_v1",
+			SourceState:           state,
+			TargetTypeName:        "kubernetes_manifest",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Diagnostics) > 0 {
+			t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics[0])
+		}
+		rt, err := GetResourceType("kubernetes_manifest")
+		if err != nil {
+			t.Fatal(err)
+		}
+		v, err := resp.TargetState.Unmarshal(rt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var attrs map[string]tftypes.Value
+		if err := v.As(&attrs); err != nil {
+			t.Fatal(err)
+		}
+		if !attrs["manifest"].IsNull() {
+			t.Errorf("expected a null manifest, got %s", attrs["manifest"])
+		}
+		data, _, err := tftypes.WalkAttributePath(attrs["object"], tftypes.NewAttributePath().WithAttributeName("data").WithElementKeyString("a"))
+		if err != nil {
+			t.Fatalf("expected the object to be read from the cluster: %v", err)
+		}
+		if !data.(tftypes.Value).Equal(tftypes.NewValue(tftypes.String, "1")) {
+			t.Errorf("unexpected data: %s", data)
+		}
+		if resp.TargetPrivate == nil {
+			t.Error("expected the moved resource to be marked as imported")
+		}
+	})
+
+	t.Run("object not found", func(t *testing.T) {
+		s := newTestMoveServer(t)
+		resp, err := s.MoveResourceState(context.Background(), &tfprotov5.MoveResourceStateRequest{
+			SourceProviderAddress: "registry.terraform.io/hashicorp/kubernetes",
+			SourceTypeName:        "kubernetes_config_map_v1",
+			SourceState:           state,
+			TargetTypeName:        "kubernetes_manifest",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Diagnostics) == 0 || resp.TargetState != nil {
+			t.Fatal("expected an error moving a missing object")
+		}
+	})
+
+	unsupported := map[string]*tfprotov5.MoveResourceStateRequest{
+		"untyped source": {
+			SourceProviderAddress: "registry.terraform.io/hashicorp/kubernetes",
+			SourceTypeName:        "kubernetes_labels",
+			SourceState:           state,
+			TargetTypeName:        "kubernetes_manifest",
+		},
+		"other provider": {
+			SourceProviderAddress: "registry.terraform.io/hashicorp/helm",
+			SourceTypeName:        "kubernetes_config_map_v1",
+			SourceState:           state,
+			TargetTypeName:        "kubernetes_manifest",
+		},
+	}
+	for name, req := range unsupported {
+		t.Run(name, func(t *testing.T) {
+			s := newTestMoveServer(t, cm)
+			resp, err := s.MoveResourceState(context.Background(), req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Summary != "Move is not supported" {
+				t.Fatalf("expected the move to be rejected, got %v", resp.Diagnostics)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-provider-kubernetes/manifest/morph"
+)
+
+// inferObjectType returns the type to plan a custom resource without a schema against, made of
+// the union of the types of its manifest and of its prior object. Fields removed from the manifest
+// keep their type from the prior object, so that their planned values can be taken from it.
+//
+// It fails when a field has changed its kind, e.g. from an object to a string,
+// in which case the planned values cannot be reconciled with the prior ones.
+func inferObjectType(config tftypes.Type, prior tftypes.Type, p *tftypes.AttributePath) (tftypes.Type, error) {
+	switch {
+	case prior == nil || prior.Is(tftypes.DynamicPseudoType):
+		return config, nil
+	case config.Is(tftypes.DynamicPseudoType):
+		return prior, nil
+	case config.Is(tftypes.Object{}) && prior.Is(tftypes.Object{}):
+		atts := make(map[string]tftypes.Type)
+		for k, t := range prior.(tftypes.Object).AttributeTypes {
+			atts[k] = t
+		}
+		for k, ct := range config.(tftypes.Object).AttributeTypes {
+			pt, ok := atts[k]
+			if !ok {
+				atts[k] = ct
+				continue
+			}
+			t, err := inferObjectType(ct, pt, p.WithAttributeName(k))
+			if err != nil {
+				return nil, err
+			}
+			atts[k] = t
+		}
+		return tftypes.Object{AttributeTypes: atts}, nil
+	case config.Is(tftypes.Map{}) && prior.Is(tftypes.Map{}):
+		et, err := inferObjectType(config.(tftypes.Map).ElementType, prior.(tftypes.Map).ElementType, p)
+		if err != nil {
+			return nil, err
+		}
+		return tftypes.Map{ElementType: et}, nil
+	case config.Is(tftypes.Tuple{}) && prior.Is(tftypes.Tuple{}),
+		config.Is(tftypes.List{}) && prior.Is(tftypes.List{}):
+		// without a schema, lists are replaced as a whole by server-side apply
+		return config, nil
+	case config.Equal(prior):
+		return config, nil
+	}
+	return nil, p.NewErrorf("type changed from %s to %s", typeKind(prior), typeKind(config))
+}
+
+// typeKind names the kind of a type for error messages, without the types of its elements
+func typeKind(t tftypes.Type) string {
+	switch {
+	case t.Is(tftypes.Object{}):
+		return "object"
+	case t.Is(tftypes.Map{}):
+		return "map"
+	case t.Is(tftypes.Tuple{}), t.Is(tftypes.List{}):
+		return "list"
+	case t.Is(tftypes.Set{}):
+		return "set"
+	}
+	return strings.ToLower(strings.TrimPrefix(t.String(), "tftypes."))
+}
+
+// morphToPlannedType converts an object read back from the API without a schema into the type
+// it was planned with, so that its type is consistent with the plan. Attributes which are
+// no longer present are set to null.
+func morphToPlannedType(obj tftypes.Value, planned tftypes.Type) (tftypes.Value, error) {
+	if !planned.Is(tftypes.Object{}) {
+		return obj, nil
+	}
+	mv, d := morph.ValueToType(obj, planned, tftypes.NewAttributePath())
+	if len(d) > 0 {
+		return obj, fmt.Errorf("%s: %s", d[0].Summary, d[0].Detail)
+	}
+	dv, err := morph.DeepUnknown(planned, mv, tftypes.NewAttributePath())
+	if err != nil {
+		return obj, err
+	}
+	return morph.UnknownToNull(dv), nil
+}
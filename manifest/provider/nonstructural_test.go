@@ -0,0 +1,130 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestInferObjectType(t *testing.T) {
+	samples := map[string]struct {
+		Config tftypes.Type
+		Prior  tftypes.Type
+		Out    tftypes.Type
+		Error  bool
+	}{
+		"added and removed attributes": {
+			Config: tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+				"spec": tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+					"size":    tftypes.Number,
+					"storage": tftypes.String,
+				}},
+			}},
+			Prior: tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+				"spec": tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+					"size":     tftypes.Number,
+					"replicas": tftypes.Number,
+				}},
+			}},
+			Out: tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+				"spec": tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+					"size":     tftypes.Number,
+					"storage":  tftypes.String,
+					"replicas": tftypes.Number,
+				}},
+			}},
+		},
+		"lists are taken from the configuration": {
+			Config: tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+				"items": tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.String, tftypes.String}},
+			}},
+			Prior: tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+				"items": tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.String}},
+			}},
+			Out: tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+				"items": tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.String, tftypes.String}},
+			}},
+		},
+		"dynamic prior": {
+			Config: tftypes.Object{AttributeTypes: map[string]tftypes.Type{"a": tftypes.String}},
+			Prior:  tftypes.DynamicPseudoType,
+			Out:    tftypes.Object{AttributeTypes: map[string]tftypes.Type{"a": tftypes.String}},
+		},
+		"object to string": {
+			Config: tftypes.Object{AttributeTypes: map[string]tftypes.Type{"spec": tftypes.String}},
+			Prior: tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+				"spec": tftypes.Object{AttributeTypes: map[string]tftypes.Type{"size": tftypes.Number}},
+			}},
+			Error: true,
+		},
+		"number to string": {
+			Config: tftypes.Object{AttributeTypes: map[string]tftypes.Type{"size": tftypes.String}},
+			Prior:  tftypes.Object{AttributeTypes: map[string]tftypes.Type{"size": tftypes.Number}},
+			Error:  true,
+		},
+	}
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			out, err := inferObjectType(s.Config, s.Prior, tftypes.NewAttributePath())
+			if s.Error {
+				if err == nil {
+					t.Fatalf("expected an error, got type %s", out)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !out.Equal(s.Out) {
+				t.Fatalf("expected type %s, got %s", s.Out, out)
+			}
+		})
+	}
+}
+
+func TestMorphToPlannedType(t *testing.T) {
+	specType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"size":     tftypes.Number,
+		"replicas": tftypes.Number,
+	}}
+	planned := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"kind": tftypes.String,
+		"spec": specType,
+	}}
+	resultSpecType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"size": tftypes.Number,
+	}}
+	result := tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"kind": tftypes.String,
+		"spec": resultSpecType,
+	}}, map[string]tftypes.Value{
+		"kind": tftypes.NewValue(tftypes.String, "Database"),
+		"spec": tftypes.NewValue(resultSpecType, map[string]tftypes.Value{
+			"size": tftypes.NewValue(tftypes.Number, 3),
+		}),
+	})
+
+	out, err := morphToPlannedType(result, planned)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := tftypes.NewValue(planned, map[string]tftypes.Value{
+		"kind": tftypes.NewValue(tftypes.String, "Database"),
+		"spec": tftypes.NewValue(specType, map[string]tftypes.Value{
+			"size":     tftypes.NewValue(tftypes.Number, 3),
+			"replicas": tftypes.NewValue(tftypes.Number, nil),
+		}),
+	})
+	if !out.Equal(expected) {
+		t.Fatalf("unexpected value:\n%s", out)
+	}
+
+	// attributes the plan doesn't know about cannot be converted
+	_, err = morphToPlannedType(result, tftypes.Object{AttributeTypes: map[string]tftypes.Type{"kind": tftypes.String}})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
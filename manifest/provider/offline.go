@@ -0,0 +1,211 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-provider-kubernetes/manifest/openapi"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+// errOffline is returned instead of an API client when the provider runs in offline mode
+var errOffline = errors.New("the provider is configured to run offline, without access to a Kubernetes API server")
+
+// offlineSchema provides the resource types and REST mappings of a cluster from local files,
+// so that kubernetes_manifest resources can be validated and planned without an API server.
+type offlineSchema struct {
+	foundry openapi.Foundry
+	mapper  meta.RESTMapper
+	// openAPIV3Schema of each CRD version, nil for non-structural CRDs
+	crds map[schema.GroupVersionKind]interface{}
+}
+
+// loadOfflineSchema builds the schema from an OpenAPI v2 spec, as served by the API server
+// at /openapi/v2, and CustomResourceDefinition manifests matching the given glob patterns.
+func loadOfflineSchema(specFile string, crdFiles []string) (*offlineSchema, error) {
+	spec, err := os.ReadFile(specFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OpenAPI spec: %w", err)
+	}
+	spec, err = yaml.ToJSON(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI spec %q: %w", specFile, err)
+	}
+	foundry, err := openapi.NewFoundryFromSpecV2(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct OpenAPI foundry from %q: %w", specFile, err)
+	}
+	mappings, err := restMappingsFromSpecV2(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read resource types from %q: %w", specFile, err)
+	}
+
+	crds := make(map[schema.GroupVersionKind]interface{})
+	for _, pattern := range crdFiles {
+		files, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CRD file pattern %q: %w", pattern, err)
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no CRD files match %q", pattern)
+		}
+		for _, f := range files {
+			if err := readCRDFile(f, crds, mappings); err != nil {
+				return nil, fmt.Errorf("failed to read CRDs from %q: %w", f, err)
+			}
+		}
+	}
+
+	return &offlineSchema{
+		foundry: foundry,
+		mapper:  newOfflineRESTMapper(mappings),
+		crds:    crds,
+	}, nil
+}
+
+// offlineMapping is what the REST mapper needs to know about a resource type
+type offlineMapping struct {
+	resource   string
+	namespaced bool
+}
+
+// restMappingsFromSpecV2 finds the resource name and scope of each kind from the paths of an
+// OpenAPI v2 spec, using the path kinds are created at, e.g. "/apis/apps/v1/namespaces/{namespace}/deployments".
+func restMappingsFromSpecV2(spec []byte) (map[schema.GroupVersionKind]offlineMapping, error) {
+	var swagger struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(spec, &swagger); err != nil {
+		return nil, err
+	}
+	mappings := make(map[schema.GroupVersionKind]offlineMapping)
+	for path, ops := range swagger.Paths {
+		post, ok := ops["post"]
+		if !ok || strings.Contains(path, "{name}") {
+			continue
+		}
+		var op struct {
+			Action string                   `json:"x-kubernetes-action"`
+			GVK    *schema.GroupVersionKind `json:"x-kubernetes-group-version-kind"`
+		}
+		if err := json.Unmarshal(post, &op); err != nil || op.Action != "post" || op.GVK == nil {
+			continue
+		}
+		mappings[*op.GVK] = offlineMapping{
+			resource:   path[strings.LastIndex(path, "/")+1:],
+			namespaced: strings.Contains(path, "/namespaces/{namespace}/"),
+		}
+	}
+	return mappings, nil
+}
+
+// readCRDFile reads the CustomResourceDefinitions from a YAML or JSON file with one or more documents
+func readCRDFile(file string, crds map[schema.GroupVersionKind]interface{}, mappings map[schema.GroupVersionKind]offlineMapping) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	dec := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		var doc map[string]interface{}
+		if err := dec.Decode(&doc); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if doc == nil || doc["kind"] != "CustomResourceDefinition" {
+			continue
+		}
+		if doc["apiVersion"] != "apiextensions.k8s.io/v1" {
+			return fmt.Errorf("unsupported CRD version %v, only apiextensions.k8s.io/v1 is supported", doc["apiVersion"])
+		}
+		spec, _ := doc["spec"].(map[string]interface{})
+		names, _ := spec["names"].(map[string]interface{})
+		group, _ := spec["group"].(string)
+		kind, _ := names["kind"].(string)
+		plural, _ := names["plural"].(string)
+		scope, _ := spec["scope"].(string)
+		versions, _ := spec["versions"].([]interface{})
+		if kind == "" || plural == "" || len(versions) == 0 {
+			return fmt.Errorf("incomplete CustomResourceDefinition for %q", group)
+		}
+		for _, rv := range versions {
+			v, _ := rv.(map[string]interface{})
+			name, _ := v["name"].(string)
+			if name == "" {
+				continue
+			}
+			gvk := schema.GroupVersionKind{Group: group, Version: name, Kind: kind}
+			mappings[gvk] = offlineMapping{resource: plural, namespaced: scope == "Namespaced"}
+			crds[gvk] = nil
+			if s, ok := v["schema"].(map[string]interface{}); ok {
+				crds[gvk] = s["openAPIV3Schema"]
+			}
+		}
+	}
+}
+
+// newOfflineRESTMapper builds a REST mapper for the given resource types. Versions of a
+// group are preferred in the order of their Kubernetes version priority, as by discovery.
+func newOfflineRESTMapper(mappings map[schema.GroupVersionKind]offlineMapping) meta.RESTMapper {
+	gvs := make(map[schema.GroupVersion]bool)
+	for gvk := range mappings {
+		gvs[gvk.GroupVersion()] = true
+	}
+	ordered := make([]schema.GroupVersion, 0, len(gvs))
+	for gv := range gvs {
+		ordered = append(ordered, gv)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].Group != ordered[j].Group {
+			return ordered[i].Group < ordered[j].Group
+		}
+		return version.CompareKubeAwareVersionStrings(ordered[i].Version, ordered[j].Version) > 0
+	})
+
+	rm := meta.NewDefaultRESTMapper(ordered)
+	for gvk, m := range mappings {
+		scope := meta.RESTScopeRoot
+		if m.namespaced {
+			scope = meta.RESTScopeNamespace
+		}
+		rm.AddSpecific(gvk,
+			gvk.GroupVersion().WithResource(m.resource),
+			gvk.GroupVersion().WithResource(strings.ToLower(gvk.Kind)),
+			scope)
+	}
+	return rm
+}
+
+// lookUpGVK returns the schema of a CRD, like lookUpGVKinCRDs does against the API
+func (o *offlineSchema) lookUpGVK(gvk schema.GroupVersionKind) interface{} {
+	return o.crds[gvk]
+}
+
+// canExecuteOnline returns an error diagnostic when the provider runs in offline mode and
+// the given operation needs to make changes to, or read from, the cluster.
+func (s *RawProviderServer) canExecuteOnline(operation string) (resp []*tfprotov5.Diagnostic) {
+	if s.offline != nil {
+		resp = append(resp, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  fmt.Sprintf("Cannot %s resources in offline mode", operation),
+			Detail:   "The provider is configured with an 'offline' block, which only supports planning resources. Remove the block to run against a cluster.",
+		})
+	}
+	return
+}
@@ -0,0 +1,241 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const testOfflineSpec = `
+swagger: "2.0"
+info:
+  title: Kubernetes
+  version: v1.25.0
+paths:
+  /api/v1/namespaces/{namespace}/configmaps:
+    post:
+      x-kubernetes-action: post
+      x-kubernetes-group-version-kind:
+        group: ""
+        version: v1
+        kind: ConfigMap
+  /api/v1/namespaces/{namespace}/configmaps/{name}:
+    put:
+      x-kubernetes-action: put
+      x-kubernetes-group-version-kind:
+        group: ""
+        version: v1
+        kind: ConfigMap
+  /api/v1/namespaces:
+    post:
+      x-kubernetes-action: post
+      x-kubernetes-group-version-kind:
+        group: ""
+        version: v1
+        kind: Namespace
+definitions:
+  io.k8s.api.core.v1.ConfigMap:
+    type: object
+    properties:
+      apiVersion:
+        type: string
+      kind:
+        type: string
+      data:
+        type: object
+        additionalProperties:
+          type: string
+    x-kubernetes-group-version-kind:
+    - group: ""
+      version: v1
+      kind: ConfigMap
+  io.k8s.api.core.v1.Namespace:
+    type: object
+    properties:
+      apiVersion:
+        type: string
+      kind:
+        type: string
+    x-kubernetes-group-version-kind:
+    - group: ""
+      version: v1
+      kind: Namespace
+`
+
+const testOfflineCRDs = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+spec:
+  group: example.com
+  scope: Namespaced
+  names:
+    kind: Widget
+    plural: widgets
+  versions:
+  - name: v1beta1
+    served: true
+    storage: false
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            properties:
+              size:
+                type: integer
+  - name: v1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            properties:
+              size:
+                type: integer
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: gadgets.example.com
+spec:
+  group: example.com
+  scope: Cluster
+  names:
+    kind: Gadget
+    plural: gadgets
+  versions:
+  - name: v1
+    served: true
+    storage: true
+`
+
+func writeTestOfflineFiles(t *testing.T) (string, string) {
+	dir := t.TempDir()
+	spec := filepath.Join(dir, "openapi.yaml")
+	if err := os.WriteFile(spec, []byte(testOfflineSpec), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	crdDir := filepath.Join(dir, "crds")
+	if err := os.Mkdir(crdDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(crdDir, "example.yaml"), []byte(testOfflineCRDs), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return spec, filepath.Join(crdDir, "*.yaml")
+}
+
+func TestLoadOfflineSchema(t *testing.T) {
+	spec, crds := writeTestOfflineFiles(t)
+
+	o, err := loadOfflineSchema(spec, []string{crds})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tp, _, err := o.foundry.GetTypeByGVK(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"})
+	if err != nil {
+		t.Fatalf("failed to get type of ConfigMap: %v", err)
+	}
+	if !tp.(tftypes.Object).AttributeTypes["data"].Is(tftypes.Map{ElementType: tftypes.String}) {
+		t.Fatalf("unexpected type of ConfigMap: %s", tp)
+	}
+
+	if o.lookUpGVK(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}) == nil {
+		t.Error("expected a schema for Widget")
+	}
+	if o.lookUpGVK(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Gadget"}) != nil {
+		t.Error("did not expect a schema for the non-structural Gadget")
+	}
+
+	_, err = loadOfflineSchema(spec, []string{filepath.Join(filepath.Dir(crds), "*.json")})
+	if err == nil {
+		t.Error("expected an error for a pattern without matches")
+	}
+	_, err = loadOfflineSchema(filepath.Join(t.TempDir(), "missing.json"), nil)
+	if err == nil {
+		t.Error("expected an error for a missing spec")
+	}
+}
+
+func TestOfflineRESTMapper(t *testing.T) {
+	spec, crds := writeTestOfflineFiles(t)
+
+	o, err := loadOfflineSchema(spec, []string{crds})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	samples := map[schema.GroupVersionKind]struct {
+		Resource string
+		Scope    meta.RESTScopeName
+	}{
+		{Version: "v1", Kind: "ConfigMap"}:                         {"configmaps", meta.RESTScopeNameNamespace},
+		{Version: "v1", Kind: "Namespace"}:                         {"namespaces", meta.RESTScopeNameRoot},
+		{Group: "example.com", Version: "v1beta1", Kind: "Widget"}: {"widgets", meta.RESTScopeNameNamespace},
+		{Group: "example.com", Version: "v1", Kind: "Gadget"}:      {"gadgets", meta.RESTScopeNameRoot},
+	}
+	for gvk, s := range samples {
+		t.Run(gvk.String(), func(t *testing.T) {
+			m, err := o.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if m.Resource.Resource != s.Resource {
+				t.Errorf("expected resource %q, got %q", s.Resource, m.Resource.Resource)
+			}
+			if m.Scope.Name() != s.Scope {
+				t.Errorf("expected scope %q, got %q", s.Scope, m.Scope.Name())
+			}
+		})
+	}
+
+	// the stable version is preferred when no version is given
+	m, err := o.mapper.RESTMapping(schema.GroupKind{Group: "example.com", Kind: "Widget"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.GroupVersionKind.Version != "v1" {
+		t.Errorf("expected version v1 to be preferred, got %q", m.GroupVersionKind.Version)
+	}
+
+	if _, err := o.mapper.RESTMapping(schema.GroupKind{Kind: "Pod"}, "v1"); err == nil {
+		t.Error("expected no mapping for an unknown kind")
+	}
+}
+
+func TestOfflineClients(t *testing.T) {
+	spec, _ := writeTestOfflineFiles(t)
+
+	o, err := loadOfflineSchema(spec, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s := &RawProviderServer{offline: o}
+	if _, err := s.getDynamicClient(); err != errOffline {
+		t.Errorf("expected errOffline from the dynamic client, got: %v", err)
+	}
+	if _, err := s.getRestMapper(); err != nil {
+		t.Errorf("unexpected error from the REST mapper: %v", err)
+	}
+	if d := s.checkValidCredentials(context.Background()); len(d) > 0 {
+		t.Errorf("unexpected diagnostics: %v", d)
+	}
+	if d := s.canExecuteOnline("apply"); len(d) != 1 {
+		t.Errorf("expected a diagnostic for apply in offline mode, got %d", len(d))
+	}
+}
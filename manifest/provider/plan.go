@@ -5,33 +5,40 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-provider-kubernetes/manifest"
 	"github.com/hashicorp/terraform-provider-kubernetes/manifest/morph"
 	"github.com/hashicorp/terraform-provider-kubernetes/manifest/payload"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/dynamic"
 )
 
-func (s *RawProviderServer) dryRun(ctx context.Context, obj tftypes.Value, fieldManager string, forceConflicts bool, isNamespaced bool) error {
+// dryRun performs a server-side apply of the manifest in dry-run mode and returns the object the API server would persist
+func (s *RawProviderServer) dryRun(ctx context.Context, obj tftypes.Value, fieldManager string, forceConflicts bool, isNamespaced bool) (*unstructured.Unstructured, error) {
 	c, err := s.getDynamicClient()
 	if err != nil {
-		return fmt.Errorf("failed to retrieve Kubernetes dynamic client during apply: %v", err)
+		return nil, fmt.Errorf("failed to retrieve Kubernetes dynamic client during apply: %v", err)
 	}
 	m, err := s.getRestMapper()
 	if err != nil {
-		return fmt.Errorf("failed to retrieve Kubernetes RESTMapper client during apply: %v", err)
+		return nil, fmt.Errorf("failed to retrieve Kubernetes RESTMapper client during apply: %v", err)
 	}
 
 	minObj := morph.UnknownToNull(obj)
 	pu, err := payload.FromTFValue(minObj, nil, tftypes.NewAttributePath())
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	rqObj := mapRemoveNulls(pu.(map[string]interface{}))
@@ -43,7 +50,7 @@ func (s *RawProviderServer) dryRun(ctx context.Context, obj tftypes.Value, field
 
 	gvr, err := GVRFromUnstructured(&uo, m)
 	if err != nil {
-		return fmt.Errorf("failed to determine resource GVR: %s", err)
+		return nil, fmt.Errorf("failed to determine resource GVR: %s", err)
 	}
 
 	var rs dynamic.ResourceInterface
@@ -53,19 +60,95 @@ func (s *RawProviderServer) dryRun(ctx context.Context, obj tftypes.Value, field
 		rs = c.Resource(gvr)
 	}
 
+	if rname == "" && uo.GetGenerateName() != "" {
+		// server-side apply needs a name, new resources named by the API server are created instead
+		return rs.Create(ctx, &uo, metav1.CreateOptions{
+			FieldManager: fieldManager,
+			DryRun:       []string{"All"},
+		})
+	}
+
 	jsonManifest, err := uo.MarshalJSON()
 	if err != nil {
-		return fmt.Errorf("failed to marshall resource %q to JSON: %v", rnn, err)
+		return nil, fmt.Errorf("failed to marshall resource %q to JSON: %v", rnn, err)
 	}
-	_, err = rs.Patch(ctx, rname, types.ApplyPatchType, jsonManifest,
+	return rs.Patch(ctx, rname, types.ApplyPatchType, jsonManifest,
 		metav1.PatchOptions{
 			FieldManager: fieldManager,
 			Force:        &forceConflicts,
 			DryRun:       []string{"All"},
 		},
 	)
+}
+
+// dryRunObject returns the "object" value resulting from a dry-run apply of the manifest.
+// It is built the same way as the one resulting from the actual apply, so that both agree.
+func (s *RawProviderServer) dryRunObject(ctx context.Context, manifest tftypes.Value, objectType tftypes.Type, hints map[string]string, fieldManager string, forceConflicts bool, isNamespaced bool) (tftypes.Value, error) {
+	result, err := s.dryRun(ctx, manifest, fieldManager, forceConflicts, isNamespaced)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	content, err := objectContent(result.Object, fieldManager, true, nil)
+	if err != nil {
+		return tftypes.Value{}, fmt.Errorf("failed to filter fields by field manager: %s", err)
+	}
+	obj, err := payload.ToTFValue(RemoveServerSideFields(content), objectType, hints, tftypes.NewAttributePath())
+	if err != nil {
+		return tftypes.Value{}, fmt.Errorf("conversion from Unstructured to tftypes.Value failed: %s", err)
+	}
+	obj, err = morph.DeepUnknown(objectType, obj, tftypes.NewAttributePath())
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	return morph.UnknownToNull(obj), nil
+}
+
+// getDryRunOnPlan tells if the "object" of a resource should be planned with a dry-run apply
+func getDryRunOnPlan(v map[string]tftypes.Value) (bool, error) {
+	var dryRun bool
+	if d, ok := v["dry_run_on_plan"]; ok && !d.IsNull() && d.IsKnown() {
+		if err := d.As(&dryRun); err != nil {
+			return false, err
+		}
+	}
+	return dryRun, nil
+}
 
-	return err
+// getWaitFieldPaths returns the paths of the fields waited for with the "fields" attribute
+// of the "wait" block or of the deprecated "wait_for" attribute
+func getWaitFieldPaths(v map[string]tftypes.Value) ([]*tftypes.AttributePath, error) {
+	var waitConfigs []tftypes.Value
+	if w, ok := v["wait"]; ok && !w.IsNull() && w.IsKnown() {
+		if err := w.As(&waitConfigs); err != nil {
+			return nil, err
+		}
+	}
+	if wf, ok := v["wait_for"]; ok && !wf.IsNull() && wf.IsKnown() {
+		waitConfigs = append(waitConfigs, wf)
+	}
+	var paths []*tftypes.AttributePath
+	for _, wc := range waitConfigs {
+		var w map[string]tftypes.Value
+		if err := wc.As(&w); err != nil {
+			return nil, err
+		}
+		fields, ok := w["fields"]
+		if !ok || fields.IsNull() || !fields.IsKnown() {
+			continue
+		}
+		var fm map[string]tftypes.Value
+		if err := fields.As(&fm); err != nil {
+			return nil, err
+		}
+		for k := range fm {
+			p, err := FieldPathToTftypesPath(k)
+			if err != nil {
+				return nil, err
+			}
+			paths = append(paths, p)
+		}
+	}
+	return paths, nil
 }
 
 const defaultFieldManagerName = "Terraform"
@@ -127,6 +210,10 @@ func isImportedFlagFromPrivate(p []byte) (f bool, d []*tfprotov5.Diagnostic) {
 
 // PlanResourceChange function
 func (s *RawProviderServer) PlanResourceChange(ctx context.Context, req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	if req.TypeName == "kubernetes_manifest_set" {
+		return s.PlanManifestSetChange(ctx, req)
+	}
+
 	resp := &tfprotov5.PlanResourceChangeResponse{}
 
 	isImported, d := isImportedFlagFromPrivate(req.PriorPrivate)
@@ -137,6 +224,7 @@ func (s *RawProviderServer) PlanResourceChange(ctx context.Context, req *tfproto
 			tftypes.NewAttributePath().WithAttributeName("manifest").WithAttributeName("kind"),
 			tftypes.NewAttributePath().WithAttributeName("manifest").WithAttributeName("metadata").WithAttributeName("name"),
 		)
+		resp.RequiresReplace = append(resp.RequiresReplace, clusterRequiresReplace...)
 	} else {
 		resp.PlannedPrivate = req.PriorPrivate
 	}
@@ -147,11 +235,19 @@ func (s *RawProviderServer) PlanResourceChange(ctx context.Context, req *tfproto
 		return resp, nil
 	}
 
+	// the resource is planned with the clients of the cluster it targets, as its schema is read from that cluster
+	s, d = s.forResourceCluster(req.TypeName, req.ProposedNewState, req.PriorState)
+	if len(d) > 0 {
+		resp.Diagnostics = append(resp.Diagnostics, d...)
+		return resp, nil
+	}
+
 	// test if credentials are valid - we're going to need them further down
 	resp.Diagnostics = append(resp.Diagnostics, s.checkValidCredentials(ctx)...)
 	if len(resp.Diagnostics) > 0 {
 		return resp, nil
 	}
+	resp.Diagnostics = append(resp.Diagnostics, s.schemaCacheDiagnostics(ctx)...)
 
 	rt, err := GetResourceType(req.TypeName)
 	if err != nil {
@@ -172,7 +268,6 @@ func (s *RawProviderServer) PlanResourceChange(ctx context.Context, req *tfproto
 		})
 		return resp, nil
 	}
-	s.logger.Trace("[PlanResourceChange]", "[ProposedState]", dump(proposedState))
 
 	proposedVal := make(map[string]tftypes.Value)
 	err = proposedState.As(&proposedVal)
@@ -219,6 +314,27 @@ func (s *RawProviderServer) PlanResourceChange(ctx context.Context, req *tfproto
 		computedFields[atp.String()] = atp
 	}
 
+	sensitiveFields, err := getSensitiveFields(proposedVal)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Invalid sensitive_fields",
+			Detail:    err.Error(),
+			Attribute: tftypes.NewAttributePath().WithAttributeName("sensitive_fields"),
+		})
+		return resp, nil
+	}
+	ignoreFields, err := getIgnoreFields(proposedVal)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Invalid ignore_fields",
+			Detail:    err.Error(),
+			Attribute: tftypes.NewAttributePath().WithAttributeName("ignore_fields"),
+		})
+		return resp, nil
+	}
+
 	// Decode prior resource state
 	priorState, err := req.PriorState.Unmarshal(rt)
 	if err != nil {
@@ -229,7 +345,9 @@ func (s *RawProviderServer) PlanResourceChange(ctx context.Context, req *tfproto
 		})
 		return resp, nil
 	}
-	s.logger.Trace("[PlanResourceChange]", "[PriorState]", dump(priorState))
+	logFields := sensitiveFieldsOfStates(proposedState, priorState)
+	s.logger.Trace("[PlanResourceChange]", "[ProposedState]", dump(redactState(proposedState, logFields)))
+	s.logger.Trace("[PlanResourceChange]", "[PriorState]", dump(redactState(priorState, logFields)))
 
 	priorVal := make(map[string]tftypes.Value)
 	err = priorState.As(&priorVal)
@@ -277,6 +395,29 @@ func (s *RawProviderServer) PlanResourceChange(ctx context.Context, req *tfproto
 		return resp, nil
 	}
 	gvk, err := GVKFromTftypesObject(&ppMan, rm)
+	if meta.IsNoMatchError(err) && proposedVal["object"].IsNull() {
+		// The resource type is not known to the cluster yet, most likely because its CRD
+		// is created in the same apply. Defer planning the object until apply time.
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityWarning,
+			Summary:  "Resource type not found on the cluster, planning deferred to apply",
+			Detail: fmt.Sprintf("%s\n\nThis is expected when the CustomResourceDefinition for this resource is created in the same apply. "+
+				"The \"object\" attribute will only be known once the resource is created.", err),
+		})
+		proposedVal["object"] = tftypes.NewValue(tftypes.DynamicPseudoType, tftypes.UnknownValue)
+		propStateVal := tftypes.NewValue(proposedState.Type(), proposedVal)
+		plannedState, err := tfprotov5.NewDynamicValue(propStateVal.Type(), propStateVal)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Failed to assemble proposed state during plan",
+				Detail:   err.Error(),
+			})
+			return resp, nil
+		}
+		resp.PlannedState = &plannedState
+		return resp, nil
+	}
 	if err != nil {
 		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
 			Severity: tfprotov5.DiagnosticSeverityError,
@@ -314,14 +455,27 @@ func (s *RawProviderServer) PlanResourceChange(ctx context.Context, req *tfproto
 	}
 
 	if !objectType.Is(tftypes.Object{}) {
-		// non-structural resources have no schema so we just use the
-		// type information we can get from the config
+		// non-structural resources have no schema so we use the type information
+		// we can get from the config, and from the prior state on update
 		objectType = ppMan.Type()
+		var replace error
+		if po, ok := priorVal["object"]; ok && !proposedVal["object"].IsNull() && !po.IsNull() && po.IsKnown() {
+			objectType, replace = inferObjectType(ppMan.Type(), po.Type(), tftypes.NewAttributePath())
+			if replace != nil {
+				objectType = ppMan.Type()
+			}
+		}
 
+		detail := "We could not find an OpenAPI schema for this custom resource. Its type is inferred from the configuration and the prior state. " +
+			"Changes to the type of a field will cause a forced replacement."
+		if replace != nil {
+			detail = fmt.Sprintf("We could not find an OpenAPI schema for this custom resource. "+
+				"It will be replaced, as the type of its configuration is incompatible with the prior state: %s", replace)
+		}
 		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
 			Severity: tfprotov5.DiagnosticSeverityWarning,
 			Summary:  "This custom resource does not have an associated OpenAPI schema.",
-			Detail:   "We could not find an OpenAPI schema for this custom resource. Updates to this resource will cause a forced replacement.",
+			Detail:   detail,
 		})
 
 		fieldManagerName, forceConflicts, err := s.getFieldManagerConfig(proposedVal)
@@ -334,7 +488,10 @@ func (s *RawProviderServer) PlanResourceChange(ctx context.Context, req *tfproto
 			return resp, nil
 		}
 
-		err = s.dryRun(ctx, ppMan, fieldManagerName, forceConflicts, ns)
+		// in offline mode there is no API server to validate the resource against
+		if s.offline == nil {
+			_, err = s.dryRun(ctx, ppMan, fieldManagerName, forceConflicts, ns)
+		}
 		if err != nil {
 			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
 				Severity: tfprotov5.DiagnosticSeverityError,
@@ -344,9 +501,11 @@ func (s *RawProviderServer) PlanResourceChange(ctx context.Context, req *tfproto
 			return resp, nil
 		}
 
-		resp.RequiresReplace = []*tftypes.AttributePath{
-			tftypes.NewAttributePath().WithAttributeName("manifest"),
-			tftypes.NewAttributePath().WithAttributeName("object"),
+		if replace != nil {
+			resp.RequiresReplace = []*tftypes.AttributePath{
+				tftypes.NewAttributePath().WithAttributeName("manifest"),
+				tftypes.NewAttributePath().WithAttributeName("object"),
+			}
 		}
 	}
 
@@ -364,7 +523,7 @@ func (s *RawProviderServer) PlanResourceChange(ctx context.Context, req *tfproto
 		resp.Diagnostics = append(resp.Diagnostics, d...)
 		return resp, nil
 	}
-	s.logger.Debug("[PlanResourceChange]", "morphed manifest", dump(morphedManifest))
+	s.logger.Debug("[PlanResourceChange]", "morphed manifest", dump(redactValue(morphedManifest, sensitiveFields)))
 
 	completePropMan, err := morph.DeepUnknown(objectType, morphedManifest, tftypes.NewAttributePath().WithAttributeName("object"))
 	if err != nil {
@@ -376,11 +535,11 @@ func (s *RawProviderServer) PlanResourceChange(ctx context.Context, req *tfproto
 		})
 		return resp, nil
 	}
-	s.logger.Debug("[PlanResourceChange]", "backfilled manifest", dump(completePropMan))
+	s.logger.Debug("[PlanResourceChange]", "backfilled manifest", dump(redactValue(completePropMan, sensitiveFields)))
 
 	if proposedVal["object"].IsNull() {
 		// plan for Create
-		s.logger.Debug("[PlanResourceChange]", "creating object", dump(completePropMan))
+		s.logger.Debug("[PlanResourceChange]", "creating object", dump(redactValue(completePropMan, sensitiveFields)))
 		newObj, err := tftypes.Transform(completePropMan, func(ap *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
 			_, ok := computedFields[ap.String()]
 			if ok {
@@ -470,6 +629,10 @@ func (s *RawProviderServer) PlanResourceChange(ctx context.Context, req *tfproto
 			if len(restPath.Steps()) > 0 {
 				s.logger.Warn("[PlanResourceChange]", "Unexpected missing attribute from state at", ap.String(), " + ", restPath.String())
 			}
+			if !priorAtrVal.(tftypes.Value).Type().UsableAs(v.Type()) {
+				// the type of the attribute has changed, e.g. a list of a different length in a resource without a schema
+				return v, nil
+			}
 			return priorAtrVal.(tftypes.Value), nil
 		})
 		if err != nil {
@@ -485,8 +648,53 @@ func (s *RawProviderServer) PlanResourceChange(ctx context.Context, req *tfproto
 		proposedVal["object"] = updatedObj
 	}
 
+	dryRunOnPlan, err := getDryRunOnPlan(proposedVal)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Invalid dry_run_on_plan",
+			Detail:    err.Error(),
+			Attribute: tftypes.NewAttributePath().WithAttributeName("dry_run_on_plan"),
+		})
+		return resp, nil
+	}
+	if dryRunOnPlan && s.offline == nil {
+		var d []*tfprotov5.Diagnostic
+		proposedVal["object"], d = s.planObjectWithDryRun(ctx, proposedVal, objectType, hints, ns, computedFields, ignoreFields)
+		resp.Diagnostics = append(resp.Diagnostics, d...)
+		for _, dd := range d {
+			if dd.Severity == tfprotov5.DiagnosticSeverityError {
+				return resp, nil
+			}
+		}
+	}
+
+	// Ignored fields are not applied, so they are not part of the planned object
+	proposedVal["object"], err = removeIgnoredFields(proposedVal["object"], ignoreFields)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Failed to remove ignored fields from planned state",
+			Detail:    err.Error(),
+			Attribute: tftypes.NewAttributePath().WithAttributeName("object"),
+		})
+		return resp, nil
+	}
+
+	// Values of sensitive fields are only kept in "manifest", so they don't show in the plan of "object"
+	proposedVal["object"], err = redactSensitiveFields(proposedVal["object"], sensitiveFields)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Failed to redact sensitive fields in planned state",
+			Detail:    err.Error(),
+			Attribute: tftypes.NewAttributePath().WithAttributeName("object"),
+		})
+		return resp, nil
+	}
+
 	propStateVal := tftypes.NewValue(proposedState.Type(), proposedVal)
-	s.logger.Trace("[PlanResourceChange]", "new planned state", dump(propStateVal))
+	s.logger.Trace("[PlanResourceChange]", "new planned state", dump(redactState(propStateVal, sensitiveFields)))
 
 	plannedState, err := tfprotov5.NewDynamicValue(propStateVal.Type(), propStateVal)
 	if err != nil {
@@ -502,6 +710,162 @@ func (s *RawProviderServer) PlanResourceChange(ctx context.Context, req *tfproto
 	return resp, nil
 }
 
+// planObjectWithDryRun replaces the planned "object" of a resource with the result of a dry-run apply of its manifest,
+// so that the plan shows the changes made by the API server, and errors from admission surface during plan.
+// The planned value is kept as is when the dry-run cannot be performed.
+func (s *RawProviderServer) planObjectWithDryRun(ctx context.Context, proposedVal map[string]tftypes.Value, objectType tftypes.Type, hints map[string]string, isNamespaced bool, computedFields map[string]*tftypes.AttributePath, ignoreFields map[string]*tftypes.AttributePath) (tftypes.Value, []*tfprotov5.Diagnostic) {
+	var diags []*tfprotov5.Diagnostic
+	plannedObj := proposedVal["object"]
+	manifest := proposedVal["manifest"]
+	if !manifest.IsFullyKnown() {
+		// unknown values would be sent as nulls, which the API server may well reject
+		s.logger.Debug("[PlanResourceChange]", "skipping dry-run", "manifest has unknown values")
+		return plannedObj, diags
+	}
+	if _, restPath, err := tftypes.WalkAttributePath(manifest, tftypes.NewAttributePath().WithAttributeName("metadata").WithAttributeName("name")); err != nil || len(restPath.Steps()) > 0 {
+		// the name is generated by the API server, so it would differ between the dry-run and the apply
+		s.logger.Debug("[PlanResourceChange]", "skipping dry-run", "manifest has no name")
+		return plannedObj, diags
+	}
+	// the dry-run must not claim the ignored fields, or it would conflict with their managers
+	manifest, err := removeIgnoredFields(manifest, ignoreFields)
+	if err != nil {
+		diags = append(diags, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Failed to remove ignored fields from manifest",
+			Detail:    err.Error(),
+			Attribute: tftypes.NewAttributePath().WithAttributeName("manifest"),
+		})
+		return plannedObj, diags
+	}
+
+	fieldManagerName, forceConflicts, err := s.getFieldManagerConfig(proposedVal)
+	if err != nil {
+		diags = append(diags, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Could not extract field_manager config",
+			Detail:   err.Error(),
+		})
+		return plannedObj, diags
+	}
+	dryObj, err := s.dryRunObject(ctx, manifest, objectType, hints, fieldManagerName, forceConflicts, isNamespaced)
+	if err != nil {
+		s.logger.Debug("[PlanResourceChange]", "dry-run error", dump(err))
+		if status := apierrors.APIStatus(nil); apierrors.IsNotFound(err) {
+			// most likely the namespace of the resource is created in the same apply
+			diags = append(diags, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityWarning,
+				Summary:  "Dry-run skipped during plan",
+				Detail:   fmt.Sprintf("The dry-run apply of this resource could not be performed, the changes made by the API server will only be known after apply.\nError: %s", err),
+			})
+		} else if errors.As(err, &status) {
+			diags = append(diags, APIStatusErrorToManifestDiagnostics(status.Status(), manifest)...)
+		} else {
+			diags = append(diags, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Dry-run failed during plan",
+				Detail:   err.Error(),
+			})
+		}
+		return plannedObj, diags
+	}
+
+	// computed fields keep their planned values, the API server can still change them during apply
+	obj, err := tftypes.Transform(dryObj, func(ap *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
+		if _, ok := computedFields[ap.String()]; !ok {
+			return v, nil
+		}
+		pv, restPath, err := tftypes.WalkAttributePath(plannedObj, ap)
+		if err != nil || len(restPath.Steps()) > 0 {
+			return v, nil
+		}
+		return pv.(tftypes.Value), nil
+	})
+	if err != nil {
+		diags = append(diags, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Failed to plan object from dry-run result",
+			Detail:    err.Error(),
+			Attribute: tftypes.NewAttributePath().WithAttributeName("object"),
+		})
+		return plannedObj, diags
+	}
+	return obj, diags
+}
+
+// planDeferredObject builds the planned "object" value of a resource for which planning was deferred
+// because its type was not known to the cluster at plan time. It waits for the type to become
+// available, resetting the cached RESTMapper, until the context expires.
+func (s *RawProviderServer) planDeferredObject(ctx context.Context, manifest tftypes.Value) (tftypes.Value, []*tfprotov5.Diagnostic) {
+	var diags []*tfprotov5.Diagnostic
+	rm, err := s.getRestMapper()
+	if err != nil {
+		diags = append(diags, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to create K8s RESTMapper client",
+			Detail:   err.Error(),
+		})
+		return manifest, diags
+	}
+	var gvk schema.GroupVersionKind
+	err = wait.PollImmediateUntilWithContext(ctx, time.Second, func(ctx context.Context) (bool, error) {
+		s.resetRestMapper()
+		var err error
+		gvk, err = GVKFromTftypesObject(&manifest, rm)
+		if meta.IsNoMatchError(err) {
+			return false, nil
+		}
+		return err == nil, err
+	})
+	if err != nil {
+		diags = append(diags, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to determine GroupVersionResource for manifest",
+			Detail:   fmt.Sprintf("The resource type could still not be found on the cluster: %s", err),
+		})
+		return manifest, diags
+	}
+
+	diags = append(diags, s.validateResourceOnline(&manifest)...)
+	if len(diags) > 0 {
+		return manifest, diags
+	}
+
+	objectType, _, err := s.TFTypeFromOpenAPI(ctx, gvk, false)
+	if err != nil {
+		diags = append(diags, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  fmt.Sprintf("Failed to determine resource type from GVK: %s", gvk),
+			Detail:   err.Error(),
+		})
+		return manifest, diags
+	}
+	if !objectType.Is(tftypes.Object{}) {
+		// non-structural resources have no schema so we just use the
+		// type information we can get from the config
+		objectType = manifest.Type()
+	}
+	morphedManifest, d := morph.ValueToType(manifest, objectType, tftypes.NewAttributePath().WithAttributeName("object"))
+	if len(d) > 0 {
+		diags = append(diags, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Manifest configuration incompatible with resource schema",
+			Detail:   "Detailed descriptions of errors will follow below.",
+		})
+		return manifest, append(diags, d...)
+	}
+	obj, err := morph.DeepUnknown(objectType, morphedManifest, tftypes.NewAttributePath().WithAttributeName("object"))
+	if err != nil {
+		diags = append(diags, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to backfill manifest from OpenAPI type",
+			Detail:   err.Error(),
+		})
+		return manifest, diags
+	}
+	return obj, diags
+}
+
 func getAttributeValue(v tftypes.Value, path string) (tftypes.Value, error) {
 	p, err := FieldPathToTftypesPath(path)
 	if err != nil {
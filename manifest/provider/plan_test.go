@@ -0,0 +1,231 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var testConfigMapMetadataType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+	"name":      tftypes.String,
+	"namespace": tftypes.String,
+	"labels":    tftypes.Map{ElementType: tftypes.String},
+}}
+
+var testConfigMapType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+	"apiVersion": tftypes.String,
+	"kind":       tftypes.String,
+	"metadata":   testConfigMapMetadataType,
+	"data":       tftypes.Map{ElementType: tftypes.String},
+}}
+
+func newTestConfigMapValue(labels tftypes.Value, data map[string]tftypes.Value) tftypes.Value {
+	return tftypes.NewValue(testConfigMapType, map[string]tftypes.Value{
+		"apiVersion": tftypes.NewValue(tftypes.String, "v1"),
+		"kind":       tftypes.NewValue(tftypes.String, "ConfigMap"),
+		"metadata": tftypes.NewValue(testConfigMapMetadataType, map[string]tftypes.Value{
+			"name":      tftypes.NewValue(tftypes.String, "test"),
+			"namespace": tftypes.NewValue(tftypes.String, "default"),
+			"labels":    labels,
+		}),
+		"data": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, data),
+	})
+}
+
+func newTestDryRunServer(reaction k8stesting.ReactionFunc) (*RawProviderServer, *fake.FakeDynamicClient) {
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme())
+	client.PrependReactor("patch", "configmaps", reaction)
+	rm := meta.NewDefaultRESTMapper([]schema.GroupVersion{{Version: "v1"}})
+	rm.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	return &RawProviderServer{
+		logger:        hclog.NewNullLogger(),
+		dynamicClient: client,
+		restMapper:    rm,
+	}, client
+}
+
+func TestPlanObjectWithDryRun(t *testing.T) {
+	labelsType := tftypes.Map{ElementType: tftypes.String}
+	manifest := newTestConfigMapValue(tftypes.NewValue(labelsType, nil), map[string]tftypes.Value{
+		"a": tftypes.NewValue(tftypes.String, "1"),
+		"b": tftypes.NewValue(tftypes.String, "2"),
+	})
+	planned := newTestConfigMapValue(tftypes.NewValue(labelsType, tftypes.UnknownValue), map[string]tftypes.Value{
+		"a": tftypes.NewValue(tftypes.String, "1"),
+		"b": tftypes.NewValue(tftypes.String, "2"),
+	})
+	labelsPath := tftypes.NewAttributePath().WithAttributeName("metadata").WithAttributeName("labels")
+	computedFields := map[string]*tftypes.AttributePath{labelsPath.String(): labelsPath}
+	proposedVal := map[string]tftypes.Value{
+		"manifest": manifest,
+		"object":   planned,
+	}
+
+	t.Run("mutated", func(t *testing.T) {
+		s, client := newTestDryRunServer(func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]interface{}{
+					"name":      "test",
+					"namespace": "default",
+					"uid":       "1234",
+					"labels": map[string]interface{}{
+						"injected": "yes",
+					},
+				},
+				"data": map[string]interface{}{
+					"a": "1",
+					"b": "mutated",
+				},
+			}}, nil
+		})
+		obj, diags := s.planObjectWithDryRun(context.Background(), proposedVal, testConfigMapType, nil, true, computedFields, nil)
+		if len(diags) > 0 {
+			t.Fatalf("unexpected diagnostics: %v", diags[0])
+		}
+		if len(client.Actions()) != 1 {
+			t.Fatalf("expected a single API call, got %d", len(client.Actions()))
+		}
+		// computed fields keep their planned value
+		expected := newTestConfigMapValue(tftypes.NewValue(labelsType, tftypes.UnknownValue), map[string]tftypes.Value{
+			"a": tftypes.NewValue(tftypes.String, "1"),
+			"b": tftypes.NewValue(tftypes.String, "mutated"),
+		})
+		if !obj.Equal(expected) {
+			t.Fatalf("unexpected planned object:\n%s", obj)
+		}
+	})
+
+	t.Run("server defaults", func(t *testing.T) {
+		s, _ := newTestDryRunServer(func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]interface{}{
+					"name":      "test",
+					"namespace": "default",
+					"managedFields": []interface{}{
+						map[string]interface{}{
+							"manager":    "Terraform",
+							"operation":  "Apply",
+							"apiVersion": "v1",
+							"fieldsType": "FieldsV1",
+							"fieldsV1": map[string]interface{}{
+								"f:data": map[string]interface{}{
+									"f:a": map[string]interface{}{},
+									"f:b": map[string]interface{}{},
+								},
+							},
+						},
+					},
+				},
+				"data": map[string]interface{}{
+					"a":       "1",
+					"b":       "2",
+					"default": "set by the API server",
+				},
+			}}, nil
+		})
+		obj, diags := s.planObjectWithDryRun(context.Background(), proposedVal, testConfigMapType, nil, true, computedFields, nil)
+		if len(diags) > 0 {
+			t.Fatalf("unexpected diagnostics: %v", diags[0])
+		}
+		// fields not owned by the field manager are planned too
+		expected := newTestConfigMapValue(tftypes.NewValue(labelsType, tftypes.UnknownValue), map[string]tftypes.Value{
+			"a":       tftypes.NewValue(tftypes.String, "1"),
+			"b":       tftypes.NewValue(tftypes.String, "2"),
+			"default": tftypes.NewValue(tftypes.String, "set by the API server"),
+		})
+		if !obj.Equal(expected) {
+			t.Fatalf("unexpected planned object:\n%s", obj)
+		}
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		s, _ := newTestDryRunServer(func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewInvalid(schema.GroupKind{Kind: "ConfigMap"}, "test", field.ErrorList{
+				field.Invalid(field.NewPath("metadata", "name"), "test", "denied by policy"),
+			})
+		})
+		_, diags := s.planObjectWithDryRun(context.Background(), proposedVal, testConfigMapType, nil, true, computedFields, nil)
+		if len(diags) == 0 {
+			t.Fatal("expected diagnostics")
+		}
+		expected := tftypes.NewAttributePath().WithAttributeName("manifest").WithAttributeName("metadata").WithAttributeName("name")
+		last := diags[len(diags)-1]
+		if last.Attribute == nil || !last.Attribute.Equal(expected) {
+			t.Fatalf("expected the cause to point at %s, got %s", expected, last.Attribute)
+		}
+	})
+
+	t.Run("namespace not found", func(t *testing.T) {
+		s, _ := newTestDryRunServer(func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, "default")
+		})
+		obj, diags := s.planObjectWithDryRun(context.Background(), proposedVal, testConfigMapType, nil, true, computedFields, nil)
+		if len(diags) != 1 || diags[0].Severity != tfprotov5.DiagnosticSeverityWarning {
+			t.Fatalf("expected a single warning, got: %v", diags)
+		}
+		if !obj.Equal(planned) {
+			t.Fatalf("expected the planned object to be kept:\n%s", obj)
+		}
+	})
+
+	t.Run("unknown manifest", func(t *testing.T) {
+		s, client := newTestDryRunServer(func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, nil
+		})
+		unknown := map[string]tftypes.Value{
+			"manifest": newTestConfigMapValue(tftypes.NewValue(labelsType, nil), map[string]tftypes.Value{
+				"a": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			}),
+			"object": planned,
+		}
+		obj, diags := s.planObjectWithDryRun(context.Background(), unknown, testConfigMapType, nil, true, computedFields, nil)
+		if len(diags) > 0 {
+			t.Fatalf("unexpected diagnostics: %v", diags[0])
+		}
+		if len(client.Actions()) > 0 {
+			t.Fatal("did not expect a dry-run with unknown values in the manifest")
+		}
+		if !obj.Equal(planned) {
+			t.Fatalf("expected the planned object to be kept:\n%s", obj)
+		}
+	})
+}
+
+func TestGetDryRunOnPlan(t *testing.T) {
+	samples := []struct {
+		In  map[string]tftypes.Value
+		Out bool
+	}{
+		{map[string]tftypes.Value{}, false},
+		{map[string]tftypes.Value{"dry_run_on_plan": tftypes.NewValue(tftypes.Bool, nil)}, false},
+		{map[string]tftypes.Value{"dry_run_on_plan": tftypes.NewValue(tftypes.Bool, tftypes.UnknownValue)}, false},
+		{map[string]tftypes.Value{"dry_run_on_plan": tftypes.NewValue(tftypes.Bool, true)}, true},
+	}
+	for _, s := range samples {
+		out, err := getDryRunOnPlan(s.In)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out != s.Out {
+			t.Errorf("expected %v for %v, got %v", s.Out, s.In, out)
+		}
+	}
+}
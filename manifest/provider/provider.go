@@ -70,6 +70,7 @@ func GetProviderResourceSchema() map[string]*tfprotov5.Schema {
 			Version: 1,
 			Block: &tfprotov5.SchemaBlock{
 				BlockTypes: []*tfprotov5.SchemaNestedBlock{
+					clusterBlockSchema(),
 					{
 						TypeName: "timeouts",
 						Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
@@ -131,13 +132,185 @@ func GetProviderResourceSchema() map[string]*tfprotov5.Schema {
 							},
 						},
 					},
+					{
+						TypeName: "delete",
+						Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+						MinItems: 0,
+						MaxItems: 1,
+						Block: &tfprotov5.SchemaBlock{
+							Description: "Configure how the resource is deleted.",
+							Attributes: []*tfprotov5.SchemaAttribute{
+								{
+									Name:            "propagation_policy",
+									Type:            tftypes.String,
+									Required:        false,
+									Optional:        true,
+									Computed:        false,
+									Sensitive:       false,
+									Description:     "Whether and how garbage collection is performed for dependents of the resource. One of `Foreground`, `Background` or `Orphan`.",
+									DescriptionKind: 0,
+									Deprecated:      false,
+								},
+								{
+									Name:            "grace_period_seconds",
+									Type:            tftypes.Number,
+									Required:        false,
+									Optional:        true,
+									Computed:        false,
+									Sensitive:       false,
+									Description:     "The duration in seconds before the resource should be deleted. Zero means delete immediately.",
+									DescriptionKind: 0,
+									Deprecated:      false,
+								},
+								{
+									Name:            "abandon",
+									Type:            tftypes.Bool,
+									Required:        false,
+									Optional:        true,
+									Computed:        false,
+									Sensitive:       false,
+									Description:     "Remove the resource from the Terraform state without deleting it from the cluster.",
+									DescriptionKind: 0,
+									Deprecated:      false,
+								},
+								{
+									Name:            "remove_finalizers_after",
+									Type:            tftypes.String,
+									Required:        false,
+									Optional:        true,
+									Computed:        false,
+									Sensitive:       false,
+									Description:     "Remove the finalizers of the resource when it has not been deleted after this duration, e.g. `5m`.",
+									DescriptionKind: 0,
+									Deprecated:      false,
+								},
+							},
+						},
+					},
 					{
 						TypeName: "wait",
 						Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
 						MinItems: 0,
 						MaxItems: 1,
+						Block:    waitBlockSchema("Configure waiter options."),
+					},
+				},
+				Attributes: []*tfprotov5.SchemaAttribute{
+					{
+						Name:        "manifest",
+						Type:        tftypes.DynamicPseudoType,
+						Required:    true,
+						Description: "A Kubernetes manifest describing the desired state of the resource in HCL format.",
+					},
+					{
+						Name:        "object",
+						Type:        tftypes.DynamicPseudoType,
+						Optional:    true,
+						Computed:    true,
+						Description: "The resulting resource state, as returned by the API server after applying the desired state from `manifest`.",
+					},
+					{
+						Name: "wait_for",
+						Type: tftypes.Object{
+							AttributeTypes: map[string]tftypes.Type{
+								"fields": tftypes.Map{
+									ElementType: tftypes.String,
+								},
+							},
+						},
+						Optional:    true,
+						Deprecated:  true,
+						Description: "A map of attribute paths and desired patterns to be matched. After each apply the provider will wait for all attributes listed here to reach a value that matches the desired pattern.",
+					},
+					{
+						Name:        "computed_fields",
+						Type:        tftypes.List{ElementType: tftypes.String},
+						Description: "List of manifest fields whose values can be altered by the API server during 'apply'. Defaults to: [\"metadata.annotations\", \"metadata.labels\"]",
+						Optional:    true,
+					},
+					{
+						Name:        "sensitive_fields",
+						Type:        tftypes.List{ElementType: tftypes.String},
+						Description: "List of manifest fields whose values are sensitive. Their values are redacted in 'object' and in logs. The 'data' and 'stringData' fields of Secrets are always sensitive.",
+						Optional:    true,
+					},
+					{
+						Name:        "ignore_fields",
+						Type:        tftypes.List{ElementType: tftypes.String},
+						Description: "List of manifest fields which are managed outside of Terraform, e.g. 'spec.replicas' when scaled by a HorizontalPodAutoscaler. They are left out of the apply requests and of 'object', so changes to them are not detected.",
+						Optional:    true,
+					},
+					{
+						Name:        "dry_run_on_plan",
+						Type:        tftypes.Bool,
+						Description: "Run a server-side apply dry-run during plan and use its result for 'object', so that the plan shows the changes made by the API server and admission webhooks, and rejected changes fail at plan time.",
+						Optional:    true,
+					},
+				},
+			},
+		},
+		"kubernetes_manifest_set": {
+			Version: 0,
+			Block: &tfprotov5.SchemaBlock{
+				BlockTypes: []*tfprotov5.SchemaNestedBlock{
+					clusterBlockSchema(),
+					{
+						TypeName: "timeouts",
+						Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+						MinItems: 0,
+						MaxItems: 1,
+						Block: &tfprotov5.SchemaBlock{
+							Attributes: []*tfprotov5.SchemaAttribute{
+								{
+									Name:        "create",
+									Type:        tftypes.String,
+									Description: "Timeout for the create operation.",
+									Optional:    true,
+								},
+								{
+									Name:        "update",
+									Type:        tftypes.String,
+									Description: "Timeout for the update operation.",
+									Optional:    true,
+								},
+								{
+									Name:        "delete",
+									Type:        tftypes.String,
+									Description: "Timeout for the delete operation.",
+									Optional:    true,
+								},
+							},
+						},
+					},
+					{
+						TypeName: "field_manager",
+						Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+						MinItems: 0,
+						MaxItems: 1,
+						Block: &tfprotov5.SchemaBlock{
+							Description: "Configure field manager options.",
+							Attributes: []*tfprotov5.SchemaAttribute{
+								{
+									Name:        "name",
+									Type:        tftypes.String,
+									Optional:    true,
+									Description: "The name to use for the field manager when creating and updating the resources.",
+								},
+								{
+									Name:        "force_conflicts",
+									Type:        tftypes.Bool,
+									Optional:    true,
+									Description: "Force changes against conflicts.",
+								},
+							},
+						},
+					},
+					{
+						TypeName: "wait",
+						Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+						MinItems: 0,
 						Block: &tfprotov5.SchemaBlock{
-							Description: "Configure waiter options.",
+							Description: "Configure waiter options for the resources matching \"kind\" and \"name\".",
 							BlockTypes: []*tfprotov5.SchemaNestedBlock{
 								{
 									TypeName: "condition",
@@ -155,12 +328,65 @@ func GetProviderResourceSchema() map[string]*tfprotov5.Schema {
 												Type:        tftypes.String,
 												Optional:    true,
 												Description: "The type of condition.",
+											}, {
+												Name:        "reason",
+												Type:        tftypes.String,
+												Optional:    true,
+												Description: "A regular expression the condition reason must match.",
+											}, {
+												Name:        "message",
+												Type:        tftypes.String,
+												Optional:    true,
+												Description: "A regular expression the condition message must match.",
+											},
+										},
+									},
+								},
+								{
+									TypeName: "fail_condition",
+									Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+									MinItems: 0,
+									Block: &tfprotov5.SchemaBlock{
+										Description: "A condition which, when met, fails the wait immediately.",
+										Attributes: []*tfprotov5.SchemaAttribute{
+											{
+												Name:        "status",
+												Type:        tftypes.String,
+												Optional:    true,
+												Description: "The condition status.",
+											}, {
+												Name:        "type",
+												Type:        tftypes.String,
+												Optional:    true,
+												Description: "The type of condition.",
+											}, {
+												Name:        "reason",
+												Type:        tftypes.String,
+												Optional:    true,
+												Description: "A regular expression the condition reason must match.",
+											}, {
+												Name:        "message",
+												Type:        tftypes.String,
+												Optional:    true,
+												Description: "A regular expression the condition message must match.",
 											},
 										},
 									},
 								},
 							},
 							Attributes: []*tfprotov5.SchemaAttribute{
+								{
+									Name:        "kind",
+									Type:        tftypes.String,
+									Optional:    true,
+									Description: "Only wait on resources of this kind. Defaults to all resources.",
+								},
+								{
+									Name:        "name",
+									Type:        tftypes.String,
+									Optional:    true,
+									Description: "Only wait on resources with this name. Defaults to all resources.",
+								},
 								{
 									Name:        "rollout",
 									Type:        tftypes.Bool,
@@ -173,41 +399,33 @@ func GetProviderResourceSchema() map[string]*tfprotov5.Schema {
 									Optional:    true,
 									Description: "A map of paths to fields to wait for a specific field value.",
 								},
+								{
+									Name:        "expression",
+									Type:        tftypes.String,
+									Optional:    true,
+									Description: "A CEL expression evaluated against the resource, available as `object`, to wait for to return true.",
+								},
 							},
 						},
 					},
 				},
 				Attributes: []*tfprotov5.SchemaAttribute{
 					{
-						Name:        "manifest",
-						Type:        tftypes.DynamicPseudoType,
+						Name:        "content",
+						Type:        tftypes.String,
 						Required:    true,
-						Description: "A Kubernetes manifest describing the desired state of the resource in HCL format.",
+						Description: "Multi-document YAML or JSON describing the Kubernetes resources to manage.",
 					},
 					{
-						Name:        "object",
+						Name:        "objects",
 						Type:        tftypes.DynamicPseudoType,
-						Optional:    true,
 						Computed:    true,
-						Description: "The resulting resource state, as returned by the API server after applying the desired state from `manifest`.",
+						Description: "The resources as returned by the API server after apply, keyed by \"apiVersion/kind/namespace/name\".",
 					},
 					{
-						Name: "wait_for",
-						Type: tftypes.Object{
-							AttributeTypes: map[string]tftypes.Type{
-								"fields": tftypes.Map{
-									ElementType: tftypes.String,
-								},
-							},
-						},
-						Optional:    true,
-						Deprecated:  true,
-						Description: "A map of attribute paths and desired patterns to be matched. After each apply the provider will wait for all attributes listed here to reach a value that matches the desired pattern.",
-					},
-					{
-						Name:        "computed_fields",
+						Name:        "sensitive_fields",
 						Type:        tftypes.List{ElementType: tftypes.String},
-						Description: "List of manifest fields whose values can be altered by the API server during 'apply'. Defaults to: [\"metadata.annotations\", \"metadata.labels\"]",
+						Description: "List of fields of the resources whose values are sensitive. Their values are redacted in 'objects' and in logs. The 'data' and 'stringData' fields of Secrets are always sensitive.",
 						Optional:    true,
 					},
 				},
@@ -242,8 +460,38 @@ func GetProviderDataSourceSchema() map[string]*tfprotov5.Schema {
 						Computed:    true,
 						Description: "The response from the API server.",
 					},
+					{
+						Name:        "wait_for_exists",
+						Type:        tftypes.Bool,
+						Optional:    true,
+						Description: "Wait for the resource to be created, instead of returning a null object when it does not exist.",
+					},
 				},
 				BlockTypes: []*tfprotov5.SchemaNestedBlock{
+					clusterBlockSchema(),
+					{
+						TypeName: "wait",
+						Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+						MinItems: 0,
+						MaxItems: 1,
+						Block:    waitBlockSchema("Wait for the resource to reach the given state before reading it."),
+					},
+					{
+						TypeName: "timeouts",
+						Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+						MinItems: 0,
+						MaxItems: 1,
+						Block: &tfprotov5.SchemaBlock{
+							Attributes: []*tfprotov5.SchemaAttribute{
+								{
+									Name:        "read",
+									Type:        tftypes.String,
+									Description: "Timeout for waiting for the resource to exist and to reach the state configured in the wait block.",
+									Optional:    true,
+								},
+							},
+						},
+					},
 					{
 						TypeName: "metadata",
 						Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
@@ -315,10 +563,121 @@ func GetProviderDataSourceSchema() map[string]*tfprotov5.Schema {
 						Name:        "limit",
 						Type:        tftypes.Number,
 						Optional:    true,
-						Description: "Limit is a maximum number of responses to return for a list call.",
+						Description: "The maximum number of objects to return. Objects are listed page by page until the limit is reached. Defaults to 10000.",
 					},
+					{
+						Name:        "all_namespaces",
+						Type:        tftypes.Bool,
+						Optional:    true,
+						Description: "List objects across all namespaces. Conflicts with 'namespace'.",
+					},
+					{
+						Name:        "sort_by",
+						Type:        tftypes.String,
+						Optional:    true,
+						Description: "Sort the objects by 'name' or 'creationTimestamp'. By default objects are in the order returned by the API server.",
+					},
+					{
+						Name:        "fields",
+						Type:        tftypes.List{ElementType: tftypes.String},
+						Optional:    true,
+						Description: "List of paths of fields to return for each object, e.g. 'metadata.name'. By default all fields are returned.",
+					},
+				},
+				BlockTypes: []*tfprotov5.SchemaNestedBlock{
+					clusterBlockSchema(),
 				},
 			},
 		},
 	}
 }
+
+// waitBlockSchema returns the schema of the "wait" block, shared by kubernetes_manifest and the kubernetes_resource data source
+func waitBlockSchema(description string) *tfprotov5.SchemaBlock {
+	return &tfprotov5.SchemaBlock{
+		Description: description,
+		BlockTypes: []*tfprotov5.SchemaNestedBlock{
+			{
+				TypeName: "condition",
+				Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+				MinItems: 0,
+				Block: &tfprotov5.SchemaBlock{
+					Attributes: []*tfprotov5.SchemaAttribute{
+						{
+							Name:        "status",
+							Type:        tftypes.String,
+							Optional:    true,
+							Description: "The condition status.",
+						}, {
+							Name:        "type",
+							Type:        tftypes.String,
+							Optional:    true,
+							Description: "The type of condition.",
+						}, {
+							Name:        "reason",
+							Type:        tftypes.String,
+							Optional:    true,
+							Description: "A regular expression the condition reason must match.",
+						}, {
+							Name:        "message",
+							Type:        tftypes.String,
+							Optional:    true,
+							Description: "A regular expression the condition message must match.",
+						},
+					},
+				},
+			},
+			{
+				TypeName: "fail_condition",
+				Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+				MinItems: 0,
+				Block: &tfprotov5.SchemaBlock{
+					Description: "A condition which, when met, fails the wait immediately.",
+					Attributes: []*tfprotov5.SchemaAttribute{
+						{
+							Name:        "status",
+							Type:        tftypes.String,
+							Optional:    true,
+							Description: "The condition status.",
+						}, {
+							Name:        "type",
+							Type:        tftypes.String,
+							Optional:    true,
+							Description: "The type of condition.",
+						}, {
+							Name:        "reason",
+							Type:        tftypes.String,
+							Optional:    true,
+							Description: "A regular expression the condition reason must match.",
+						}, {
+							Name:        "message",
+							Type:        tftypes.String,
+							Optional:    true,
+							Description: "A regular expression the condition message must match.",
+						},
+					},
+				},
+			},
+		},
+		Attributes: []*tfprotov5.SchemaAttribute{
+			{
+				Name:        "rollout",
+				Type:        tftypes.Bool,
+				Optional:    true,
+				Description: "Wait for rollout to complete on resources that support `kubectl rollout status`.",
+			},
+			{
+				Name:        "fields",
+				Type:        tftypes.Map{ElementType: tftypes.String},
+				Optional:    true,
+				Description: "A map of paths to fields to wait for a specific field value.",
+			},
+			{
+				Name:        "expression",
+				Type:        tftypes.String,
+				Optional:    true,
+				Description: "A CEL expression evaluated against the resource, available as `object`, to wait for to return true.",
+			},
+		},
+	}
+}
@@ -112,6 +112,61 @@ func GetProviderConfigSchema() *tfprotov5.Schema {
 				DescriptionKind: 0,
 				Deprecated:      false,
 			},
+			{
+				Name:            "config_raw",
+				Type:            tftypes.String,
+				Description:     "Content of a kube config file, e.g. read from a secret manager. Takes precedence over `config_path` and `config_paths`.",
+				Required:        false,
+				Optional:        true,
+				Computed:        false,
+				Sensitive:       true,
+				DescriptionKind: 0,
+				Deprecated:      false,
+			},
+			{
+				Name:            "token_file",
+				Type:            tftypes.String,
+				Description:     "Path to a file with a token to authenticate a service account, re-read periodically to pick up rotated tokens.",
+				Required:        false,
+				Optional:        true,
+				Computed:        false,
+				Sensitive:       false,
+				DescriptionKind: 0,
+				Deprecated:      false,
+			},
+			{
+				Name:            "client_certificate_file",
+				Type:            tftypes.String,
+				Description:     "Path to a PEM-encoded client certificate for TLS authentication, re-read when it changes.",
+				Required:        false,
+				Optional:        true,
+				Computed:        false,
+				Sensitive:       false,
+				DescriptionKind: 0,
+				Deprecated:      false,
+			},
+			{
+				Name:            "client_key_file",
+				Type:            tftypes.String,
+				Description:     "Path to a PEM-encoded client certificate key for TLS authentication, re-read when it changes.",
+				Required:        false,
+				Optional:        true,
+				Computed:        false,
+				Sensitive:       false,
+				DescriptionKind: 0,
+				Deprecated:      false,
+			},
+			{
+				Name:            "cluster_ca_certificate_file",
+				Type:            tftypes.String,
+				Description:     "Path to a PEM-encoded root certificates bundle for TLS authentication.",
+				Required:        false,
+				Optional:        true,
+				Computed:        false,
+				Sensitive:       false,
+				DescriptionKind: 0,
+				Deprecated:      false,
+			},
 			{
 				Name:            "config_path",
 				Type:            tftypes.String,
@@ -178,6 +233,39 @@ func GetProviderConfigSchema() *tfprotov5.Schema {
 				DescriptionKind: 0,
 				Deprecated:      false,
 			},
+			{
+				Name:            "qps",
+				Type:            tftypes.Number,
+				Description:     "Maximum number of queries per second sent to the Kubernetes API by each client of the provider. Defaults to 5.",
+				Required:        false,
+				Optional:        true,
+				Computed:        false,
+				Sensitive:       false,
+				DescriptionKind: 0,
+				Deprecated:      false,
+			},
+			{
+				Name:            "burst",
+				Type:            tftypes.Number,
+				Description:     "Maximum burst of queries sent to the Kubernetes API above the `qps` rate. Defaults to 10.",
+				Required:        false,
+				Optional:        true,
+				Computed:        false,
+				Sensitive:       false,
+				DescriptionKind: 0,
+				Deprecated:      false,
+			},
+			{
+				Name:            "request_timeout",
+				Type:            tftypes.String,
+				Description:     "Maximum duration of a single request to the Kubernetes API, e.g. `30s`. Watch requests used to wait for resources are not limited. No timeout is set by default.",
+				Required:        false,
+				Optional:        true,
+				Computed:        false,
+				Sensitive:       false,
+				DescriptionKind: 0,
+				Deprecated:      false,
+			},
 			{
 				Name:            "ignore_annotations",
 				Type:            tftypes.List{ElementType: tftypes.String},
@@ -252,6 +340,217 @@ func GetProviderConfigSchema() *tfprotov5.Schema {
 					},
 				},
 			},
+			{
+				TypeName: "impersonate",
+				Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+				MinItems: 0,
+				MaxItems: 1,
+				Block: &tfprotov5.SchemaBlock{
+					Description: "Impersonate a user, and optionally groups, in all requests to the Kubernetes API, as with the `--as` and `--as-group` flags of `kubectl`.",
+					Attributes: []*tfprotov5.SchemaAttribute{
+						{
+							Name:            "user",
+							Type:            tftypes.String,
+							Required:        true,
+							Optional:        false,
+							Computed:        false,
+							Sensitive:       false,
+							Description:     "Name of the user to impersonate, e.g. `system:serviceaccount:tenant:deployer`.",
+							DescriptionKind: 0,
+							Deprecated:      false,
+						},
+						{
+							Name:            "uid",
+							Type:            tftypes.String,
+							Required:        false,
+							Optional:        true,
+							Computed:        false,
+							Sensitive:       false,
+							Description:     "UID of the user to impersonate.",
+							DescriptionKind: 0,
+							Deprecated:      false,
+						},
+						{
+							Name:            "groups",
+							Type:            tftypes.List{ElementType: tftypes.String},
+							Required:        false,
+							Optional:        true,
+							Computed:        false,
+							Sensitive:       false,
+							Description:     "Groups to impersonate.",
+							DescriptionKind: 0,
+							Deprecated:      false,
+						},
+					},
+					BlockTypes: []*tfprotov5.SchemaNestedBlock{
+						{
+							TypeName: "extra",
+							Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+							MinItems: 0,
+							MaxItems: 0,
+							Block: &tfprotov5.SchemaBlock{
+								Description: "Extra fields of the user to impersonate.",
+								Attributes: []*tfprotov5.SchemaAttribute{
+									{
+										Name:            "key",
+										Type:            tftypes.String,
+										Required:        true,
+										Optional:        false,
+										Computed:        false,
+										Sensitive:       false,
+										Description:     "Name of the extra field, e.g. `scopes`.",
+										DescriptionKind: 0,
+										Deprecated:      false,
+									},
+									{
+										Name:            "values",
+										Type:            tftypes.List{ElementType: tftypes.String},
+										Required:        true,
+										Optional:        false,
+										Computed:        false,
+										Sensitive:       false,
+										Description:     "Values of the extra field.",
+										DescriptionKind: 0,
+										Deprecated:      false,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			{
+				TypeName: "retry",
+				Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+				MinItems: 0,
+				MaxItems: 1,
+				Block: &tfprotov5.SchemaBlock{
+					Description: "Retry requests to the Kubernetes API which fail with a transient error, such as throttling or an etcd leader change.",
+					Attributes: []*tfprotov5.SchemaAttribute{
+						{
+							Name:            "max_attempts",
+							Type:            tftypes.Number,
+							Required:        false,
+							Optional:        true,
+							Computed:        false,
+							Sensitive:       false,
+							Description:     "Maximum number of attempts of a request, including the first one. Defaults to 5.",
+							DescriptionKind: 0,
+							Deprecated:      false,
+						},
+						{
+							Name:            "min_backoff",
+							Type:            tftypes.String,
+							Required:        false,
+							Optional:        true,
+							Computed:        false,
+							Sensitive:       false,
+							Description:     "Delay before the first retry, doubled on each following one, e.g. `500ms`. Defaults to `500ms`.",
+							DescriptionKind: 0,
+							Deprecated:      false,
+						},
+						{
+							Name:            "max_backoff",
+							Type:            tftypes.String,
+							Required:        false,
+							Optional:        true,
+							Computed:        false,
+							Sensitive:       false,
+							Description:     "Maximum delay between retries, including delays requested by the server with a `Retry-After` header. Defaults to `30s`.",
+							DescriptionKind: 0,
+							Deprecated:      false,
+						},
+						{
+							Name:            "retryable_status_codes",
+							Type:            tftypes.List{ElementType: tftypes.Number},
+							Required:        false,
+							Optional:        true,
+							Computed:        false,
+							Sensitive:       false,
+							Description:     "HTTP status codes of the responses to retry. Creations and patches are only retried on 429, and on 503 with a `Retry-After` header. Defaults to 429, 500, 502, 503 and 504.",
+							DescriptionKind: 0,
+							Deprecated:      false,
+						},
+						{
+							Name:            "retry_on_conflict",
+							Type:            tftypes.Bool,
+							Required:        false,
+							Optional:        true,
+							Computed:        false,
+							Sensitive:       false,
+							Description:     "Retry requests which conflict with a concurrent change of the resource, unless they set a `resourceVersion`. Conflicts with other field managers are never retried.",
+							DescriptionKind: 0,
+							Deprecated:      false,
+						},
+					},
+				},
+			},
+			{
+				TypeName: "cache",
+				Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+				MinItems: 0,
+				MaxItems: 1,
+				Block: &tfprotov5.SchemaBlock{
+					Description: "Cache the OpenAPI schema and API discovery data of the cluster on disk, for use by the `kubernetes_manifest` resources.",
+					Attributes: []*tfprotov5.SchemaAttribute{
+						{
+							Name:            "directory",
+							Type:            tftypes.String,
+							Required:        true,
+							Optional:        false,
+							Computed:        false,
+							Sensitive:       false,
+							Description:     "Path to the directory where cached data is stored.",
+							DescriptionKind: 0,
+							Deprecated:      false,
+						},
+						{
+							Name:            "ttl",
+							Type:            tftypes.String,
+							Required:        false,
+							Optional:        true,
+							Computed:        false,
+							Sensitive:       false,
+							Description:     "How long cached data is used before being refreshed from the cluster, e.g. `12h`. Defaults to `24h`.",
+							DescriptionKind: 0,
+							Deprecated:      false,
+						},
+					},
+				},
+			},
+			{
+				TypeName: "offline",
+				Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+				MinItems: 0,
+				MaxItems: 1,
+				Block: &tfprotov5.SchemaBlock{
+					Description: "Plan `kubernetes_manifest` resources against a local OpenAPI spec and CRD files, without connecting to the cluster. Resources cannot be applied, read or imported in offline mode.",
+					Attributes: []*tfprotov5.SchemaAttribute{
+						{
+							Name:            "openapi_spec_file",
+							Type:            tftypes.String,
+							Required:        true,
+							Optional:        false,
+							Computed:        false,
+							Sensitive:       false,
+							Description:     "Path to an OpenAPI v2 spec of the Kubernetes API, in JSON or YAML, as served by the API server at `/openapi/v2`.",
+							DescriptionKind: 0,
+							Deprecated:      false,
+						},
+						{
+							Name:            "crd_files",
+							Type:            tftypes.List{ElementType: tftypes.String},
+							Required:        false,
+							Optional:        true,
+							Computed:        false,
+							Sensitive:       false,
+							Description:     "Paths or glob patterns of YAML or JSON files with the CustomResourceDefinitions to plan custom resources against.",
+							DescriptionKind: 0,
+							Deprecated:      false,
+						},
+					},
+				},
+			},
 			{
 				TypeName: "experiments",
 				Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
@@ -18,6 +18,16 @@ import (
 
 // ReadResource function
 func (s *RawProviderServer) ReadResource(ctx context.Context, req *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+	// the resource is read with the clients of the cluster it is managed in
+	s, diags := s.forResourceCluster(req.TypeName, req.CurrentState)
+	if len(diags) > 0 {
+		return &tfprotov5.ReadResourceResponse{Diagnostics: diags}, nil
+	}
+
+	if req.TypeName == "kubernetes_manifest_set" {
+		return s.ReadManifestSet(ctx, req)
+	}
+
 	resp := &tfprotov5.ReadResourceResponse{}
 
 	// loop private state back in - ATM it's not needed here
@@ -28,6 +38,11 @@ func (s *RawProviderServer) ReadResource(ctx context.Context, req *tfprotov5.Rea
 		resp.Diagnostics = append(resp.Diagnostics, execDiag...)
 		return resp, nil
 	}
+	// there is no cluster to refresh from in offline mode
+	if s.offline != nil {
+		resp.NewState = req.CurrentState
+		return resp, nil
+	}
 
 	var resState map[string]tftypes.Value
 	var err error
@@ -115,7 +130,27 @@ func (s *RawProviderServer) ReadResource(ctx context.Context, req *tfprotov5.Rea
 		})
 		return resp, nil
 	}
-	s.logger.Trace("[ReadResource]", "[unstructured.FromTFValue]", dump(cu))
+	sensitiveFields, err := getSensitiveFields(resState)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Invalid sensitive_fields",
+			Detail:    err.Error(),
+			Attribute: tftypes.NewAttributePath().WithAttributeName("sensitive_fields"),
+		})
+		return resp, nil
+	}
+	ignoreFields, err := getIgnoreFields(resState)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Invalid ignore_fields",
+			Detail:    err.Error(),
+			Attribute: tftypes.NewAttributePath().WithAttributeName("ignore_fields"),
+		})
+		return resp, nil
+	}
+	s.logger.Trace("[ReadResource]", "[unstructured.FromTFValue]", dump(redactUnstructured(cu, sensitiveFields)))
 
 	client, err := s.getDynamicClient()
 	if err != nil {
@@ -160,7 +195,43 @@ func (s *RawProviderServer) ReadResource(ctx context.Context, req *tfprotov5.Rea
 		return resp, nil
 	}
 
-	fo := RemoveServerSideFields(ro.Object)
+	fieldManagerName, _, err := s.getFieldManagerConfig(resState)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Could not extract field_manager config",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	dryRunOnPlan, err := getDryRunOnPlan(resState)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Invalid dry_run_on_plan",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	waitFields, err := getWaitFieldPaths(resState)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Invalid wait fields",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	content, err := objectContent(ro.Object, fieldManagerName, dryRunOnPlan, waitFields)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to filter fields by field manager",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	fo := RemoveServerSideFields(content)
 	nobj, err := payload.ToTFValue(fo, objectType, th, tftypes.NewAttributePath())
 	if err != nil {
 		return resp, err
@@ -171,12 +242,22 @@ func (s *RawProviderServer) ReadResource(ctx context.Context, req *tfprotov5.Rea
 		return resp, err
 	}
 
+	// changes to ignored fields are not drift
+	nobj, err = removeIgnoredFields(nobj, ignoreFields)
+	if err != nil {
+		return resp, err
+	}
+
 	rawState := make(map[string]tftypes.Value)
 	err = currentState.As(&rawState)
 	if err != nil {
 		return resp, err
 	}
-	rawState["object"] = morph.UnknownToNull(nobj)
+	// sensitive values changed outside of Terraform get another placeholder, so that the drift is planned
+	rawState["object"], err = redactChangedSensitiveFields(morph.UnknownToNull(nobj), rawState["manifest"], sensitiveFields)
+	if err != nil {
+		return resp, err
+	}
 
 	nsVal := tftypes.NewValue(currentState.Type(), rawState)
 	newState, err := tfprotov5.NewDynamicValue(nsVal.Type(), nsVal)
@@ -4,6 +4,7 @@
 package provider
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -15,6 +16,8 @@ import (
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
 )
 
 // GVRFromUnstructured extracts a canonical schema.GroupVersionResource out of the resource's
@@ -86,7 +89,7 @@ func (ps *RawProviderServer) TFTypeFromOpenAPI(ctx context.Context, gvk schema.G
 	var tsch tftypes.Type
 	var hints map[string]string
 
-	oapi, err := ps.getOAPIv2Foundry()
+	oapi, err := ps.getOAPIFoundry()
 	if err != nil {
 		return nil, hints, fmt.Errorf("cannot get OpenAPI foundry: %s", err)
 	}
@@ -194,14 +197,16 @@ func RemoveServerSideFields(in map[string]interface{}) map[string]interface{} {
 	delete(meta, "generation")
 	delete(meta, "selfLink")
 
-	// TODO: we should be filtering API responses based on the contents of 'managedFields'
-	// and only retain the attributes for which the manager is Terraform
+	// ownership is applied separately by FilterManagedFields
 	delete(meta, "managedFields")
 
 	return in
 }
 
 func (ps *RawProviderServer) lookUpGVKinCRDs(ctx context.Context, gvk schema.GroupVersionKind) (interface{}, error) {
+	if ps.offline != nil {
+		return ps.offline.lookUpGVK(gvk), nil
+	}
 	c, err := ps.getDynamicClient()
 	if err != nil {
 		return nil, err
@@ -280,3 +285,165 @@ func getPrivateStateValue(p []byte) (ps map[string]tftypes.Value, err error) {
 	err = pv.As(&ps)
 	return
 }
+
+// objectContent returns the content of a resource returned by the API which makes up its "object" attribute.
+// Resources planned with a dry-run keep all of their fields, including the defaults applied by the API server,
+// as the plan holds the resource the API server would persist. Other resources only keep the fields owned by the field manager,
+// so switching dry_run_on_plan adds or removes the other fields once. The fields at the keep paths, which are the ones
+// waited for with "wait.fields", are kept whoever owns them, so that they can be read from "object".
+func objectContent(in map[string]interface{}, fieldManager string, dryRunOnPlan bool, keep []*tftypes.AttributePath) (map[string]interface{}, error) {
+	if dryRunOnPlan {
+		return in, nil
+	}
+	out, err := FilterManagedFields(in, fieldManager)
+	if err != nil {
+		return out, err
+	}
+	for _, p := range keep {
+		out = keepFieldPath(out, in, p.Steps()).(map[string]interface{})
+	}
+	return out, nil
+}
+
+// keepFieldPath copies the value at the path from the unfiltered content of a resource into the filtered one.
+// Elements of a filtered list cannot be matched by index with the ones of the unfiltered list, so a path
+// through a list keeps the whole list.
+func keepFieldPath(out, in interface{}, steps []tftypes.AttributePathStep) interface{} {
+	if len(steps) == 0 {
+		return in
+	}
+	m, ok := in.(map[string]interface{})
+	if !ok {
+		return in
+	}
+	var key string
+	switch s := steps[0].(type) {
+	case tftypes.AttributeName:
+		key = string(s)
+	case tftypes.ElementKeyString:
+		key = string(s)
+	default:
+		return out
+	}
+	e, ok := m[key]
+	if !ok {
+		return out
+	}
+	o, ok := out.(map[string]interface{})
+	if !ok {
+		o = make(map[string]interface{})
+	}
+	o[key] = keepFieldPath(o[key], e, steps[1:])
+	return o
+}
+
+// FilterManagedFields removes the fields of the resource which are not owned by
+// the given field manager, as recorded in 'managedFields'. This keeps fields set
+// by controllers and mutating webhooks from showing up as drift.
+// The resource is returned unchanged when the manager owns no fields.
+func FilterManagedFields(in map[string]interface{}, manager string) (map[string]interface{}, error) {
+	u := unstructured.Unstructured{Object: in}
+	owned := &fieldpath.Set{}
+	found := false
+	for _, mf := range u.GetManagedFields() {
+		if mf.Manager != manager || mf.FieldsV1 == nil {
+			continue
+		}
+		fs := &fieldpath.Set{}
+		if err := fs.FromJSON(bytes.NewReader(mf.FieldsV1.Raw)); err != nil {
+			return in, fmt.Errorf("failed to decode fields owned by %q: %w", manager, err)
+		}
+		owned = owned.Union(fs)
+		found = true
+	}
+	if !found {
+		return in, nil
+	}
+
+	out := filterOwnedFields(in, owned).(map[string]interface{})
+
+	// the identity of the resource is not tracked in 'managedFields'
+	out["apiVersion"] = u.GetAPIVersion()
+	out["kind"] = u.GetKind()
+	meta, ok := out["metadata"].(map[string]interface{})
+	if !ok {
+		meta = map[string]interface{}{}
+		out["metadata"] = meta
+	}
+	meta["name"] = u.GetName()
+	if ns := u.GetNamespace(); ns != "" {
+		meta["namespace"] = ns
+	}
+	return out, nil
+}
+
+// filterOwnedFields returns the parts of a value which are members of the field set.
+func filterOwnedFields(in interface{}, owned *fieldpath.Set) interface{} {
+	switch v := in.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			name := k
+			pe := fieldpath.PathElement{FieldName: &name}
+			if children, ok := owned.Children.Get(pe); ok {
+				out[k] = filterOwnedFields(e, children)
+			} else if owned.Members.Has(pe) {
+				out[k] = e
+			}
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, 0, len(v))
+		for i, e := range v {
+			children, member := ownedListElement(owned, i, e)
+			if children != nil {
+				out = append(out, filterOwnedFields(e, children))
+			} else if member {
+				out = append(out, e)
+			}
+		}
+		return out
+	}
+	return in
+}
+
+// ownedListElement looks up a list element in the field set, either by key,
+// by value or by index, depending on how the list is tracked.
+func ownedListElement(owned *fieldpath.Set, index int, elem interface{}) (*fieldpath.Set, bool) {
+	var children *fieldpath.Set
+	owned.Children.Iterate(func(pe fieldpath.PathElement) {
+		if children == nil && listElementMatches(pe, index, elem) {
+			children, _ = owned.Children.Get(pe)
+		}
+	})
+	if children != nil {
+		return children, true
+	}
+	member := false
+	owned.Members.Iterate(func(pe fieldpath.PathElement) {
+		member = member || listElementMatches(pe, index, elem)
+	})
+	return nil, member
+}
+
+func listElementMatches(pe fieldpath.PathElement, index int, elem interface{}) bool {
+	switch {
+	case pe.Index != nil:
+		return *pe.Index == index
+	case pe.Value != nil:
+		return value.Equals(*pe.Value, value.NewValueInterface(elem))
+	case pe.Key != nil:
+		m, ok := elem.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for _, f := range *pe.Key {
+			kv, ok := m[f.Name]
+			if !ok || !value.Equals(f.Value, value.NewValueInterface(kv)) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -4,9 +4,13 @@
 package provider
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
 func TestRemoveNulls(t *testing.T) {
@@ -69,3 +73,176 @@ func TestRemoveNulls(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterManagedFields(t *testing.T) {
+	newDeployment := func(managedFields ...interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name":          "test",
+				"namespace":     "default",
+				"uid":           "1234",
+				"managedFields": managedFields,
+				"annotations": map[string]interface{}{
+					"deployment.kubernetes.io/revision": "1",
+					"owned":                             "yes",
+				},
+			},
+			"spec": map[string]interface{}{
+				"replicas": int64(3),
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"dnsPolicy": "ClusterFirst",
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name":            "nginx",
+								"image":           "nginx:1.23",
+								"imagePullPolicy": "IfNotPresent",
+							},
+							map[string]interface{}{
+								"name":  "sidecar",
+								"image": "envoy",
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	managedFields := func(manager, fields string) interface{} {
+		var fieldsV1 map[string]interface{}
+		if err := json.Unmarshal([]byte(fields), &fieldsV1); err != nil {
+			t.Fatalf("invalid fields: %v", err)
+		}
+		return map[string]interface{}{
+			"manager":    manager,
+			"operation":  "Apply",
+			"fieldsType": "FieldsV1",
+			"fieldsV1":   fieldsV1,
+		}
+	}
+	terraformFields := `{"f:metadata":{"f:annotations":{"f:owned":{}}},"f:spec":{"f:template":{"f:spec":{"f:containers":{"k:{\"name\":\"nginx\"}":{".":{},"f:name":{},"f:image":{}}}}}}}`
+	webhookFields := `{"f:spec":{"f:template":{"f:spec":{"f:containers":{"k:{\"name\":\"sidecar\"}":{".":{},"f:name":{},"f:image":{}}}}}}}`
+
+	samples := map[string]struct {
+		in      map[string]interface{}
+		manager string
+		out     map[string]interface{}
+	}{
+		"owned fields only": {
+			in:      newDeployment(managedFields("Terraform", terraformFields), managedFields("webhook", webhookFields)),
+			manager: "Terraform",
+			out: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata": map[string]interface{}{
+					"name":      "test",
+					"namespace": "default",
+					"annotations": map[string]interface{}{
+						"owned": "yes",
+					},
+				},
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"containers": []interface{}{
+								map[string]interface{}{
+									"name":  "nginx",
+									"image": "nginx:1.23",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"manager without fields": {
+			in:      newDeployment(managedFields("webhook", webhookFields)),
+			manager: "Terraform",
+			out:     newDeployment(managedFields("webhook", webhookFields)),
+		},
+	}
+
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			o, err := FilterManagedFields(s.in, s.manager)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			o = RemoveServerSideFields(o)
+			out := RemoveServerSideFields(s.out)
+			if !reflect.DeepEqual(out, o) {
+				t.Fatalf("unexpected output: %s", cmp.Diff(out, o))
+			}
+		})
+	}
+}
+
+func TestObjectContent(t *testing.T) {
+	in := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":      "test",
+			"namespace": "default",
+			"managedFields": []interface{}{
+				map[string]interface{}{
+					"manager":    "Terraform",
+					"operation":  "Apply",
+					"fieldsType": "FieldsV1",
+					"fieldsV1": map[string]interface{}{
+						"f:data": map[string]interface{}{"f:a": map[string]interface{}{}},
+					},
+				},
+			},
+		},
+		"data": map[string]interface{}{
+			"a":       "1",
+			"default": "set by the API server",
+		},
+	}
+
+	// switching dry_run_on_plan adds or removes the fields not owned by the field manager from "object"
+	samples := map[string]struct {
+		dryRunOnPlan bool
+		data         map[string]interface{}
+	}{
+		"with dry-run": {
+			dryRunOnPlan: true,
+			data:         map[string]interface{}{"a": "1", "default": "set by the API server"},
+		},
+		"without dry-run": {
+			dryRunOnPlan: false,
+			data:         map[string]interface{}{"a": "1"},
+		},
+	}
+	for name, s := range samples {
+		t.Run(name, func(t *testing.T) {
+			o, err := objectContent(in, "Terraform", s.dryRunOnPlan, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(o["data"], s.data) {
+				t.Fatalf("unexpected data: %s", cmp.Diff(s.data, o["data"]))
+			}
+		})
+	}
+
+	// the fields waited for are kept whoever owns them
+	keep := []*tftypes.AttributePath{
+		tftypes.NewAttributePath().WithAttributeName("data").WithElementKeyString("default"),
+		tftypes.NewAttributePath().WithAttributeName("status").WithAttributeName("phase"),
+	}
+	o, err := objectContent(in, "Terraform", false, keep)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data := map[string]interface{}{"a": "1", "default": "set by the API server"}
+	if !reflect.DeepEqual(o["data"], data) {
+		t.Fatalf("unexpected data: %s", cmp.Diff(data, o["data"]))
+	}
+	if _, ok := o["status"]; ok {
+		t.Fatalf("expected a missing field not to be added, got %v", o["status"])
+	}
+}
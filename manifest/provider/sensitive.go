@@ -0,0 +1,318 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-provider-kubernetes/manifest/morph"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// sensitiveValuePlaceholder replaces the values of sensitive fields in "object" and in logs
+const sensitiveValuePlaceholder = "(sensitive value)"
+
+// changedValuePlaceholder replaces the values of sensitive fields in "object" which have been changed
+// outside of Terraform, so that the change shows up in the next plan without showing the values
+const changedValuePlaceholder = "(sensitive value changed outside of Terraform)"
+
+// defaultSensitiveFields lists the fields which are always sensitive for a resource type
+var defaultSensitiveFields = map[schema.GroupVersionKind][]string{
+	{Version: "v1", Kind: "Secret"}: {"data", "stringData"},
+}
+
+// getSensitiveFields returns the paths of the sensitive fields of a kubernetes_manifest resource,
+// made of the "sensitive_fields" attribute and the defaults for the type of the resource in "manifest",
+// or in "object" when there is no manifest (e.g. on import).
+func getSensitiveFields(v map[string]tftypes.Value) (map[string]*tftypes.AttributePath, error) {
+	fields := make(map[string]*tftypes.AttributePath)
+
+	obj := v["manifest"]
+	if obj.IsNull() || !obj.IsKnown() {
+		obj = v["object"]
+	}
+	for _, f := range defaultSensitiveFields[gvkFromTFValue(obj)] {
+		atp := tftypes.NewAttributePath().WithAttributeName(f)
+		fields[fieldPathKey(atp.Steps())] = atp
+	}
+
+	return fields, addFieldPaths(fields, v["sensitive_fields"])
+}
+
+// addFieldPaths parses a list of field paths, as used by the sensitive_fields and ignore_fields
+// attributes, and adds them to fields. Null and unknown lists and elements are skipped.
+func addFieldPaths(fields map[string]*tftypes.AttributePath, v tftypes.Value) error {
+	if v.Type() == nil || v.IsNull() || !v.IsKnown() {
+		return nil
+	}
+	var l []tftypes.Value
+	if err := v.As(&l); err != nil {
+		return err
+	}
+	for _, e := range l {
+		if !e.IsKnown() || e.IsNull() {
+			continue
+		}
+		var fs string
+		if err := e.As(&fs); err != nil {
+			return err
+		}
+		atp, err := FieldPathToTftypesPath(fs)
+		if err != nil {
+			return fmt.Errorf("cannot parse field path element %q: %s", fs, err)
+		}
+		fields[fieldPathKey(atp.Steps())] = atp
+	}
+	return nil
+}
+
+// gvkFromTFValue reads apiVersion and kind from a manifest without consulting the API
+func gvkFromTFValue(v tftypes.Value) schema.GroupVersionKind {
+	if v.IsNull() || !v.IsKnown() || !v.Type().Is(tftypes.Object{}) {
+		return schema.GroupVersionKind{}
+	}
+	var m map[string]tftypes.Value
+	if err := v.As(&m); err != nil {
+		return schema.GroupVersionKind{}
+	}
+	var apiVersion, kind string
+	if av, ok := m["apiVersion"]; ok && av.IsKnown() && av.Type().Is(tftypes.String) {
+		av.As(&apiVersion)
+	}
+	if k, ok := m["kind"]; ok && k.IsKnown() && k.Type().Is(tftypes.String) {
+		k.As(&kind)
+	}
+	return schema.FromAPIVersionAndKind(apiVersion, kind)
+}
+
+// fieldPathKey identifies a field path regardless of whether the keys along it are object
+// attributes or map keys, as that depends on the type the value has been morphed into.
+func fieldPathKey(steps []tftypes.AttributePathStep) string {
+	ns := make([]tftypes.AttributePathStep, len(steps))
+	for i, s := range steps {
+		if an, ok := s.(tftypes.AttributeName); ok {
+			s = tftypes.ElementKeyString(an)
+		}
+		ns[i] = s
+	}
+	return tftypes.NewAttributePathWithSteps(ns).String()
+}
+
+// isWithinFieldPaths tells if the path is one of the given fields or nested within one of them
+func isWithinFieldPaths(ap *tftypes.AttributePath, fields map[string]*tftypes.AttributePath) bool {
+	steps := ap.Steps()
+	for i := len(steps); i > 0; i-- {
+		if _, ok := fields[fieldPathKey(steps[:i])]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// redactSensitiveFields replaces all known values within the sensitive fields of a resource object with a placeholder.
+// Strings are replaced with sensitiveValuePlaceholder and all other primitive values with null, keeping the
+// structure of the value, so that changes to the set of keys are still visible.
+func redactSensitiveFields(v tftypes.Value, fields map[string]*tftypes.AttributePath) (tftypes.Value, error) {
+	if len(fields) == 0 {
+		return v, nil
+	}
+	return tftypes.Transform(v, func(ap *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
+		if !v.IsKnown() || v.IsNull() || !isWithinFieldPaths(ap, fields) {
+			return v, nil
+		}
+		return redactPrimitive(v, sensitiveValuePlaceholder), nil
+	})
+}
+
+// redactChangedSensitiveFields redacts the sensitive fields of an object read from the cluster. The string values
+// which differ from the ones configured in the manifest are replaced with changedValuePlaceholder instead of
+// sensitiveValuePlaceholder, so that the plan of "object" shows that they changed and the manifest is applied again.
+// Values which are not configured in the manifest, such as the keys of a Secret set with stringData, can't be compared.
+func redactChangedSensitiveFields(obj tftypes.Value, manifest tftypes.Value, fields map[string]*tftypes.AttributePath) (tftypes.Value, error) {
+	if len(fields) == 0 {
+		return obj, nil
+	}
+	if manifest.IsNull() || !manifest.IsKnown() || obj.IsNull() || !obj.IsKnown() {
+		return redactSensitiveFields(obj, fields)
+	}
+	// walking the manifest with paths into the object requires it to have the same type,
+	// the values can't be compared otherwise
+	mm, d := morph.ValueToType(manifest, obj.Type(), tftypes.NewAttributePath())
+	if len(d) > 0 {
+		return redactSensitiveFields(obj, fields)
+	}
+	return tftypes.Transform(obj, func(ap *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
+		if !v.IsKnown() || v.IsNull() || !isWithinFieldPaths(ap, fields) {
+			return v, nil
+		}
+		placeholder := sensitiveValuePlaceholder
+		if mv, _, err := tftypes.WalkAttributePath(mm, ap); err == nil {
+			if mv, ok := mv.(tftypes.Value); ok && mv.IsKnown() && !mv.IsNull() && !mv.Equal(v) {
+				placeholder = changedValuePlaceholder
+			}
+		}
+		return redactPrimitive(v, placeholder), nil
+	})
+}
+
+// redactPrimitive replaces a string with the placeholder and other primitive values with null
+func redactPrimitive(v tftypes.Value, placeholder string) tftypes.Value {
+	switch {
+	case v.Type().Is(tftypes.String):
+		return tftypes.NewValue(tftypes.String, placeholder)
+	case v.Type().Is(tftypes.Number) || v.Type().Is(tftypes.Bool):
+		return tftypes.NewValue(v.Type(), nil)
+	}
+	return v
+}
+
+// redactState redacts the sensitive fields in the "manifest" and "object" attributes of a resource state,
+// so that it can be logged.
+func redactState(v tftypes.Value, fields map[string]*tftypes.AttributePath) tftypes.Value {
+	if len(fields) == 0 || v.IsNull() || !v.IsKnown() || !v.Type().Is(tftypes.Object{}) {
+		return v
+	}
+	var m map[string]tftypes.Value
+	if err := v.As(&m); err != nil {
+		return v
+	}
+	for _, k := range []string{"manifest", "object"} {
+		if a, ok := m[k]; ok {
+			m[k] = redactValue(a, fields)
+		}
+	}
+	return tftypes.NewValue(v.Type(), m)
+}
+
+// redactUnstructured returns a copy of an API payload with the values of the sensitive fields replaced,
+// so that it can be logged.
+func redactUnstructured(in interface{}, fields map[string]*tftypes.AttributePath) interface{} {
+	if len(fields) == 0 {
+		return in
+	}
+	return redactUnstructuredPath(in, nil, tftypes.NewAttributePath(), fields)
+}
+
+// redactChangedUnstructured is redactUnstructured for a resource read from the cluster. Like redactChangedSensitiveFields,
+// the values which differ from the ones set in the desired payload are replaced with changedValuePlaceholder.
+func redactChangedUnstructured(in interface{}, desired interface{}, fields map[string]*tftypes.AttributePath) interface{} {
+	if len(fields) == 0 {
+		return in
+	}
+	return redactUnstructuredPath(in, desired, tftypes.NewAttributePath(), fields)
+}
+
+func redactUnstructuredPath(in interface{}, desired interface{}, ap *tftypes.AttributePath, fields map[string]*tftypes.AttributePath) interface{} {
+	switch v := in.(type) {
+	case map[string]interface{}:
+		dm, _ := desired.(map[string]interface{})
+		out := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			out[k] = redactUnstructuredPath(e, dm[k], ap.WithAttributeName(k), fields)
+		}
+		return out
+	case []interface{}:
+		dl, _ := desired.([]interface{})
+		out := make([]interface{}, len(v))
+		for i, e := range v {
+			var de interface{}
+			if i < len(dl) {
+				de = dl[i]
+			}
+			out[i] = redactUnstructuredPath(e, de, ap.WithElementKeyInt(i), fields)
+		}
+		return out
+	case nil:
+		return nil
+	}
+	if !isWithinFieldPaths(ap, fields) {
+		return in
+	}
+	if desired != nil && !unstructuredValueEqual(in, desired) {
+		return changedValuePlaceholder
+	}
+	return sensitiveValuePlaceholder
+}
+
+// unstructuredValueEqual compares primitive values of API payloads. Numbers are compared by value,
+// as they are decoded into int64 or float64 depending on where they come from.
+func unstructuredValueEqual(a, b interface{}) bool {
+	if fa, ok := unstructuredNumber(a); ok {
+		fb, ok := unstructuredNumber(b)
+		return ok && fa == fb
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func unstructuredNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// restoreSensitiveFields puts the values configured in the manifest back into the sensitive fields
+// of a planned object, which only holds placeholders for them.
+func restoreSensitiveFields(obj tftypes.Value, manifest tftypes.Value, fields map[string]*tftypes.AttributePath) (tftypes.Value, error) {
+	if len(fields) == 0 {
+		return obj, nil
+	}
+	// walking the manifest with paths into the object requires it to have the same type
+	mm, d := morph.ValueToType(manifest, obj.Type(), tftypes.NewAttributePath())
+	if len(d) > 0 {
+		return obj, fmt.Errorf("failed to morph manifest into the type of the object: %s", d[0].Detail)
+	}
+	return tftypes.Transform(obj, func(ap *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
+		if _, ok := fields[fieldPathKey(ap.Steps())]; !ok {
+			return v, nil
+		}
+		mv, _, err := tftypes.WalkAttributePath(mm, ap)
+		if err != nil {
+			// not configured, so not part of the request
+			return tftypes.NewValue(v.Type(), nil), nil
+		}
+		return mv.(tftypes.Value), nil
+	})
+}
+
+// sensitiveFieldsOfStates returns the union of the sensitive fields of the given resource states,
+// so that they can be redacted from logs before the states are processed any further.
+func sensitiveFieldsOfStates(states ...tftypes.Value) map[string]*tftypes.AttributePath {
+	fields := make(map[string]*tftypes.AttributePath)
+	for _, st := range states {
+		if st.IsNull() || !st.IsKnown() {
+			continue
+		}
+		var v map[string]tftypes.Value
+		if err := st.As(&v); err != nil {
+			continue
+		}
+		// invalid paths are reported by validation, the defaults still apply
+		sf, _ := getSensitiveFields(v)
+		for k, p := range sf {
+			fields[k] = p
+		}
+	}
+	return fields
+}
+
+// redactValue redacts the sensitive fields of a resource object so that it can be logged
+func redactValue(v tftypes.Value, fields map[string]*tftypes.AttributePath) tftypes.Value {
+	rv, err := redactSensitiveFields(v, fields)
+	if err != nil {
+		return tftypes.NewValue(v.Type(), tftypes.UnknownValue)
+	}
+	return rv
+}
@@ -0,0 +1,280 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var testSecretType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+	"apiVersion": tftypes.String,
+	"kind":       tftypes.String,
+	"metadata": tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"name": tftypes.String,
+	}},
+	"data": tftypes.Map{ElementType: tftypes.String},
+	"type": tftypes.String,
+}}
+
+func newTestSecret(data map[string]tftypes.Value) tftypes.Value {
+	return tftypes.NewValue(testSecretType, map[string]tftypes.Value{
+		"apiVersion": tftypes.NewValue(tftypes.String, "v1"),
+		"kind":       tftypes.NewValue(tftypes.String, "Secret"),
+		"metadata": tftypes.NewValue(testSecretType.AttributeTypes["metadata"], map[string]tftypes.Value{
+			"name": tftypes.NewValue(tftypes.String, "test"),
+		}),
+		"data": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, data),
+		"type": tftypes.NewValue(tftypes.String, "Opaque"),
+	})
+}
+
+func newTestSensitiveFields(t *testing.T, paths ...string) map[string]*tftypes.AttributePath {
+	elems := make([]tftypes.Value, len(paths))
+	for i, p := range paths {
+		elems[i] = tftypes.NewValue(tftypes.String, p)
+	}
+	fields, err := getSensitiveFields(map[string]tftypes.Value{
+		"manifest":         tftypes.NewValue(tftypes.Object{}, nil),
+		"object":           tftypes.NewValue(tftypes.Object{}, nil),
+		"sensitive_fields": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, elems),
+	})
+	if err != nil {
+		t.Fatalf("failed to get sensitive fields: %v", err)
+	}
+	return fields
+}
+
+func TestGetSensitiveFields(t *testing.T) {
+	secret := newTestSecret(map[string]tftypes.Value{})
+
+	fields, err := getSensitiveFields(map[string]tftypes.Value{
+		"manifest":         secret,
+		"sensitive_fields": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, p := range []*tftypes.AttributePath{
+		tftypes.NewAttributePath().WithAttributeName("data"),
+		tftypes.NewAttributePath().WithAttributeName("stringData"),
+		tftypes.NewAttributePath().WithAttributeName("data").WithElementKeyString("password"),
+	} {
+		if !isWithinFieldPaths(p, fields) {
+			t.Errorf("expected %s to be sensitive for a Secret", p)
+		}
+	}
+	if isWithinFieldPaths(tftypes.NewAttributePath().WithAttributeName("metadata"), fields) {
+		t.Error("did not expect metadata to be sensitive")
+	}
+
+	// the defaults are taken from the object on import
+	fields, err = getSensitiveFields(map[string]tftypes.Value{
+		"manifest": tftypes.NewValue(tftypes.Object{}, nil),
+		"object":   secret,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !isWithinFieldPaths(tftypes.NewAttributePath().WithAttributeName("data"), fields) {
+		t.Error("expected data to be sensitive for an imported Secret")
+	}
+
+	fields = newTestSensitiveFields(t, `spec.password`, `spec.users[0]`, `metadata.annotations["example.com/token"]`)
+	for _, p := range []*tftypes.AttributePath{
+		tftypes.NewAttributePath().WithAttributeName("spec").WithAttributeName("password"),
+		tftypes.NewAttributePath().WithAttributeName("spec").WithElementKeyString("password"),
+		tftypes.NewAttributePath().WithAttributeName("spec").WithAttributeName("users").WithElementKeyInt(0).WithAttributeName("name"),
+		tftypes.NewAttributePath().WithAttributeName("metadata").WithAttributeName("annotations").WithAttributeName("example.com/token"),
+	} {
+		if !isWithinFieldPaths(p, fields) {
+			t.Errorf("expected %s to be sensitive", p)
+		}
+	}
+	if isWithinFieldPaths(tftypes.NewAttributePath().WithAttributeName("spec").WithAttributeName("users").WithElementKeyInt(1), fields) {
+		t.Error("did not expect spec.users[1] to be sensitive")
+	}
+
+	_, err = getSensitiveFields(map[string]tftypes.Value{
+		"sensitive_fields": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "spec..password"),
+		}),
+	})
+	if err == nil {
+		t.Error("expected an error for an invalid path")
+	}
+}
+
+func TestRedactSensitiveFields(t *testing.T) {
+	fields := newTestSensitiveFields(t, "data")
+	in := newTestSecret(map[string]tftypes.Value{
+		"password": tftypes.NewValue(tftypes.String, "hunter2"),
+		"token":    tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+	})
+	expected := newTestSecret(map[string]tftypes.Value{
+		"password": tftypes.NewValue(tftypes.String, sensitiveValuePlaceholder),
+		"token":    tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+	})
+
+	out, err := redactSensitiveFields(in, fields)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !out.Equal(expected) {
+		t.Fatalf("unexpected redacted value:\n%s", out)
+	}
+
+	// redacting is idempotent, so plan and apply agree on the value
+	again, err := redactSensitiveFields(out, fields)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !again.Equal(out) {
+		t.Fatalf("expected redacting to be idempotent:\n%s", again)
+	}
+}
+
+func TestRedactChangedSensitiveFields(t *testing.T) {
+	fields := newTestSensitiveFields(t, "data")
+	dataType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"password": tftypes.String, "username": tftypes.String}}
+	manifest := tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"apiVersion": tftypes.String,
+		"kind":       tftypes.String,
+		"data":       dataType,
+	}}, map[string]tftypes.Value{
+		"apiVersion": tftypes.NewValue(tftypes.String, "v1"),
+		"kind":       tftypes.NewValue(tftypes.String, "Secret"),
+		"data": tftypes.NewValue(dataType, map[string]tftypes.Value{
+			"password": tftypes.NewValue(tftypes.String, "hunter2"),
+			"username": tftypes.NewValue(tftypes.String, "admin"),
+		}),
+	})
+	read := newTestSecret(map[string]tftypes.Value{
+		"password": tftypes.NewValue(tftypes.String, "changed"),
+		"username": tftypes.NewValue(tftypes.String, "admin"),
+		"token":    tftypes.NewValue(tftypes.String, "not configured"),
+	})
+
+	out, err := redactChangedSensitiveFields(read, manifest, fields)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := newTestSecret(map[string]tftypes.Value{
+		"password": tftypes.NewValue(tftypes.String, changedValuePlaceholder),
+		"username": tftypes.NewValue(tftypes.String, sensitiveValuePlaceholder),
+		"token":    tftypes.NewValue(tftypes.String, sensitiveValuePlaceholder),
+	})
+	if !out.Equal(expected) {
+		t.Fatalf("unexpected redacted value:\n%s", out)
+	}
+
+	// without a manifest, e.g. after import, changes can't be told
+	out, err = redactChangedSensitiveFields(read, tftypes.NewValue(tftypes.Object{}, nil), fields)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected = newTestSecret(map[string]tftypes.Value{
+		"password": tftypes.NewValue(tftypes.String, sensitiveValuePlaceholder),
+		"username": tftypes.NewValue(tftypes.String, sensitiveValuePlaceholder),
+		"token":    tftypes.NewValue(tftypes.String, sensitiveValuePlaceholder),
+	})
+	if !out.Equal(expected) {
+		t.Fatalf("unexpected redacted value:\n%s", out)
+	}
+}
+
+func TestRestoreSensitiveFields(t *testing.T) {
+	fields := newTestSensitiveFields(t, "data")
+	manifest := tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"apiVersion": tftypes.String,
+		"kind":       tftypes.String,
+		"data":       tftypes.Object{AttributeTypes: map[string]tftypes.Type{"password": tftypes.String}},
+	}}, map[string]tftypes.Value{
+		"apiVersion": tftypes.NewValue(tftypes.String, "v1"),
+		"kind":       tftypes.NewValue(tftypes.String, "Secret"),
+		"data": tftypes.NewValue(tftypes.Object{AttributeTypes: map[string]tftypes.Type{"password": tftypes.String}}, map[string]tftypes.Value{
+			"password": tftypes.NewValue(tftypes.String, "hunter2"),
+		}),
+	})
+	planned := newTestSecret(map[string]tftypes.Value{
+		"password": tftypes.NewValue(tftypes.String, sensitiveValuePlaceholder),
+	})
+
+	out, err := restoreSensitiveFields(planned, manifest, fields)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := newTestSecret(map[string]tftypes.Value{
+		"password": tftypes.NewValue(tftypes.String, "hunter2"),
+	})
+	if !out.Equal(expected) {
+		t.Fatalf("unexpected restored value:\n%s", out)
+	}
+}
+
+func TestRedactUnstructured(t *testing.T) {
+	fields := newTestSensitiveFields(t, "data", "spec.items[1]")
+	in := map[string]interface{}{
+		"kind": "Secret",
+		"data": map[string]interface{}{
+			"password": "aHVudGVyMg==",
+		},
+		"spec": map[string]interface{}{
+			"items": []interface{}{"a", map[string]interface{}{"key": "b"}},
+		},
+	}
+	expected := map[string]interface{}{
+		"kind": "Secret",
+		"data": map[string]interface{}{
+			"password": sensitiveValuePlaceholder,
+		},
+		"spec": map[string]interface{}{
+			"items": []interface{}{"a", map[string]interface{}{"key": sensitiveValuePlaceholder}},
+		},
+	}
+	if diff := cmp.Diff(expected, redactUnstructured(in, fields)); diff != "" {
+		t.Fatalf("unexpected redacted payload (-want +got):\n%s", diff)
+	}
+	if in["data"].(map[string]interface{})["password"] != "aHVudGVyMg==" {
+		t.Fatal("expected the input to be left unchanged")
+	}
+}
+
+func TestRedactChangedUnstructured(t *testing.T) {
+	fields := newTestSensitiveFields(t, "data", "spec.port")
+	in := map[string]interface{}{
+		"data": map[string]interface{}{
+			"password": "Y2hhbmdlZA==",
+			"username": "YWRtaW4=",
+			"token":    "dG9rZW4=",
+		},
+		"spec": map[string]interface{}{
+			"port": int64(8080),
+		},
+	}
+	desired := map[string]interface{}{
+		"data": map[string]interface{}{
+			"password": "aHVudGVyMg==",
+			"username": "YWRtaW4=",
+		},
+		"spec": map[string]interface{}{
+			"port": float64(8080),
+		},
+	}
+	expected := map[string]interface{}{
+		"data": map[string]interface{}{
+			"password": changedValuePlaceholder,
+			"username": sensitiveValuePlaceholder,
+			"token":    sensitiveValuePlaceholder,
+		},
+		"spec": map[string]interface{}{
+			"port": sensitiveValuePlaceholder,
+		},
+	}
+	if diff := cmp.Diff(expected, redactChangedUnstructured(in, desired, fields)); diff != "" {
+		t.Fatalf("unexpected redacted payload (-want +got):\n%s", diff)
+	}
+}
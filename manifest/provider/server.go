@@ -5,10 +5,13 @@ package provider
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-provider-kubernetes/manifest/openapi"
+	"github.com/hashicorp/terraform-provider-kubernetes/util"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/install"
@@ -17,6 +20,8 @@ import (
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 )
 
 func init() {
@@ -35,6 +40,23 @@ type RawProviderServer struct {
 	restMapper      meta.RESTMapper
 	restClient      rest.Interface
 	OAPIFoundry     openapi.Foundry
+	OAPIv3Foundry   openapi.Foundry
+	schemaCache     *schemaCache
+	offline         *offlineSchema
+
+	cacheDir string
+	cacheTTL time.Duration
+	// the reason the cache directory could not be used, reported once as a warning
+	schemaCacheErr    error
+	schemaCacheWarned sync.Once
+
+	// the kube config and client settings of the provider configuration, from which the
+	// servers handling the resources targeting the cluster of their "cluster" block are created
+	kubeconfigLoader *clientcmd.ClientConfigLoadingRules
+	kubeconfigRaw    *clientcmdapi.Config
+	clientOptions    util.ClientOptions
+	impersonate      clientcmdapi.AuthInfo
+	clusters         clusterServers
 
 	providerEnabled bool
 	hostTFVersion   string
@@ -55,9 +77,29 @@ func (s *RawProviderServer) PrepareProviderConfig(ctx context.Context, req *tfpr
 func (s *RawProviderServer) ValidateDataSourceConfig(ctx context.Context, req *tfprotov5.ValidateDataSourceConfigRequest) (*tfprotov5.ValidateDataSourceConfigResponse, error) {
 	s.logger.Trace("[ValidateDataSourceConfig][Request]\n%s\n", dump(*req))
 	resp := &tfprotov5.ValidateDataSourceConfigResponse{}
+	switch req.TypeName {
+	case "kubernetes_resource":
+		resp.Diagnostics = append(resp.Diagnostics, s.validateSingularDataSourceConfig(req.Config)...)
+	case "kubernetes_resources":
+		resp.Diagnostics = append(resp.Diagnostics, validatePluralDataSourceConfig(req.Config)...)
+	}
 	return resp, nil
 }
 
+// GetFunctions function
+func (s *RawProviderServer) GetFunctions(ctx context.Context, req *tfprotov5.GetFunctionsRequest) (*tfprotov5.GetFunctionsResponse, error) {
+	s.logger.Trace("[GetFunctions][Request]\n%s\n", dump(*req))
+	// the provider doesn't implement any function
+	return &tfprotov5.GetFunctionsResponse{Functions: map[string]*tfprotov5.Function{}}, nil
+}
+
+// CallFunction function
+func (s *RawProviderServer) CallFunction(ctx context.Context, req *tfprotov5.CallFunctionRequest) (*tfprotov5.CallFunctionResponse, error) {
+	s.logger.Trace("[CallFunction][Request]\n%s\n", dump(*req))
+
+	return nil, status.Errorf(codes.Unimplemented, "method CallFunction not implemented")
+}
+
 // StopProvider function
 func (s *RawProviderServer) StopProvider(ctx context.Context, req *tfprotov5.StopProviderRequest) (*tfprotov5.StopProviderResponse, error) {
 	s.logger.Trace("[StopProvider][Request]\n%s\n", dump(*req))
@@ -30,6 +30,28 @@ func (s *RawProviderServer) UpgradeResourceState(ctx context.Context, req *tfpro
 		return resp, nil
 	}
 
+	// kubernetes_manifest_set stores its resources as plain values which don't need morphing
+	if req.TypeName == "kubernetes_manifest_set" {
+		us, err := tfprotov5.NewDynamicValue(rt, rv)
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+				Severity: tfprotov5.DiagnosticSeverityError,
+				Summary:  "Failed to encode new state during upgrade",
+				Detail:   err.Error(),
+			})
+		}
+		resp.UpgradedState = &us
+
+		return resp, nil
+	}
+
+	// the resource is upgraded with the clients of the cluster it is managed in
+	s, diags := s.forClusterOfValue(rv)
+	if len(diags) > 0 {
+		resp.Diagnostics = append(resp.Diagnostics, diags...)
+		return resp, nil
+	}
+
 	// test if credentials are valid - we're going to need them further down
 	// if no credentials found, just loop the current state back in
 	// we do this to work around https://github.com/hashicorp/terraform/issues/30460
@@ -98,7 +120,10 @@ func (s *RawProviderServer) UpgradeResourceState(ctx context.Context, req *tfpro
 			}
 		}
 	}
-	s.logger.Debug("[UpgradeResourceState]", "morphed object", dump(morphedObject))
+	// the stored state is left as it is, sensitive fields written by earlier versions
+	// of the provider are redacted when the resource is next refreshed
+	sensitiveFields, _ := getSensitiveFields(cs)
+	s.logger.Debug("[UpgradeResourceState]", "morphed object", dump(redactValue(morphedObject, sensitiveFields)))
 
 	cs["object"] = obj
 
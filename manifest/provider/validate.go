@@ -6,6 +6,7 @@ package provider
 import (
 	"context"
 	"fmt"
+	"math/big"
 	"strings"
 	"time"
 
@@ -15,6 +16,10 @@ import (
 
 // ValidateResourceTypeConfig function
 func (s *RawProviderServer) ValidateResourceTypeConfig(ctx context.Context, req *tfprotov5.ValidateResourceTypeConfigRequest) (*tfprotov5.ValidateResourceTypeConfigResponse, error) {
+	if req.TypeName == "kubernetes_manifest_set" {
+		return s.ValidateManifestSetConfig(ctx, req)
+	}
+
 	resp := &tfprotov5.ValidateResourceTypeConfigResponse{}
 	requiredKeys := []string{"apiVersion", "kind", "metadata"}
 	forbiddenKeys := []string{"status"}
@@ -106,21 +111,25 @@ func (s *RawProviderServer) ValidateResourceTypeConfig(ctx context.Context, req
 		}
 	}
 
-	// validate timeouts block
-	timeouts := s.getTimeouts(configVal)
-	path := tftypes.NewAttributePath().WithAttributeName("timeouts")
-	for k, v := range timeouts {
-		_, err := time.ParseDuration(v)
-		if err != nil {
+	// the resource needs a name, either set in the manifest or generated by the API server
+	if md, ok := rawManifest["metadata"]; ok && md.IsKnown() && !md.IsNull() && md.Type().Is(tftypes.Object{}) {
+		var metadata map[string]tftypes.Value
+		md.As(&metadata)
+		_, hasName := metadata["name"]
+		_, hasGenerateName := metadata["generateName"]
+		if !hasName && !hasGenerateName {
 			resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
 				Severity:  tfprotov5.DiagnosticSeverityError,
-				Summary:   fmt.Sprintf("Error parsing timeout for %q", k),
-				Detail:    err.Error(),
-				Attribute: path.WithAttributeName(k),
+				Summary:   `Attribute key missing from "manifest" value`,
+				Detail:    "Either 'metadata.name' or 'metadata.generateName' must be set in manifest configuration",
+				Attribute: att.WithAttributeName("metadata"),
 			})
 		}
 	}
 
+	// validate timeouts block
+	resp.Diagnostics = append(resp.Diagnostics, s.validateTimeouts(configVal)...)
+
 	// validate wait block
 	if wait, ok := configVal["wait"]; ok && !wait.IsNull() {
 		var waitBlock []tftypes.Value
@@ -128,29 +137,40 @@ func (s *RawProviderServer) ValidateResourceTypeConfig(ctx context.Context, req
 		if len(waitBlock) > 0 {
 			var w map[string]tftypes.Value
 			waitBlock[0].As(&w)
-			waiters := []string{}
-			for k, ww := range w {
-				if !ww.IsNull() {
-					if k == "condition" {
-						var cb []tftypes.Value
-						ww.As(&cb)
-						if len(cb) == 0 {
-							continue
-						}
-					}
-					waiters = append(waiters, k)
-				}
+			resp.Diagnostics = append(resp.Diagnostics, validateWaitBlock(tftypes.NewAttributePath().WithAttributeName("wait").WithElementKeyInt(0), w)...)
+		}
+	}
+	// validate sensitive_fields
+	if sf, ok := configVal["sensitive_fields"]; ok && !sf.IsNull() && sf.IsKnown() {
+		var fields []tftypes.Value
+		sf.As(&fields)
+		for i, f := range fields {
+			if f.IsNull() || !f.IsKnown() {
+				continue
 			}
-			if len(waiters) > 1 {
+			var fp string
+			f.As(&fp)
+			if _, err := FieldPathToTftypesPath(fp); err != nil {
 				resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
 					Severity:  tfprotov5.DiagnosticSeverityError,
-					Summary:   "Invalid wait configuration",
-					Detail:    fmt.Sprintf(`You may only set one of "%s".`, strings.Join(waiters, "\", \"")),
-					Attribute: tftypes.NewAttributePath().WithAttributeName("wait"),
+					Summary:   "Invalid sensitive_fields",
+					Detail:    err.Error(),
+					Attribute: tftypes.NewAttributePath().WithAttributeName("sensitive_fields").WithElementKeyInt(i),
 				})
 			}
 		}
 	}
+
+	// validate delete block
+	if _, err := getDeleteConfig(configVal); err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Invalid delete configuration",
+			Detail:    err.Error(),
+			Attribute: tftypes.NewAttributePath().WithAttributeName("delete").WithElementKeyInt(0),
+		})
+	}
+
 	if waitFor, ok := configVal["wait_for"]; ok && !waitFor.IsNull() {
 		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
 			Severity:  tfprotov5.DiagnosticSeverityWarning,
@@ -239,3 +259,209 @@ func (s *RawProviderServer) validateResourceOnline(manifest *tftypes.Value) (dia
 	}
 	return
 }
+
+// validateTimeouts checks that the values of the timeouts block are valid durations
+func (s *RawProviderServer) validateTimeouts(v map[string]tftypes.Value) (diags []*tfprotov5.Diagnostic) {
+	timeouts := s.getTimeouts(v)
+	path := tftypes.NewAttributePath().WithAttributeName("timeouts")
+	for k, v := range timeouts {
+		_, err := time.ParseDuration(v)
+		if err != nil {
+			diags = append(diags, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   fmt.Sprintf("Error parsing timeout for %q", k),
+				Detail:    err.Error(),
+				Attribute: path.WithAttributeName(k),
+			})
+		}
+	}
+	return
+}
+
+// validateWaitBlock checks that a wait block configures a single waiter and that its
+// conditions and expression are valid
+func validateWaitBlock(waitPath *tftypes.AttributePath, w map[string]tftypes.Value) (diags []*tfprotov5.Diagnostic) {
+	waiters := []string{}
+	for _, k := range []string{"rollout", "condition", "fields", "expression"} {
+		ww, ok := w[k]
+		if !ok || ww.IsNull() {
+			continue
+		}
+		if k == "condition" {
+			var cb []tftypes.Value
+			ww.As(&cb)
+			if len(cb) == 0 {
+				continue
+			}
+		}
+		waiters = append(waiters, k)
+	}
+	if len(waiters) > 1 {
+		diags = append(diags, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Invalid wait configuration",
+			Detail:    fmt.Sprintf(`You may only set one of "%s".`, strings.Join(waiters, "\", \"")),
+			Attribute: waitPath,
+		})
+	}
+	diags = append(diags, validateWaitConditions(waitPath, w)...)
+	if v, ok := w["expression"]; ok && !v.IsNull() && v.IsKnown() {
+		var expression string
+		v.As(&expression)
+		if _, err := CompileWaitExpression(expression); err != nil {
+			diags = append(diags, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   "Invalid wait expression",
+				Detail:    err.Error(),
+				Attribute: waitPath.WithAttributeName("expression"),
+			})
+		}
+	}
+	return
+}
+
+// validateWaitConditions checks the "condition" and "fail_condition" blocks of a wait block
+func validateWaitConditions(waitPath *tftypes.AttributePath, w map[string]tftypes.Value) (diags []*tfprotov5.Diagnostic) {
+	var conditions, failConditions []tftypes.Value
+	if v, ok := w["condition"]; ok && !v.IsNull() && v.IsKnown() {
+		v.As(&conditions)
+	}
+	if v, ok := w["fail_condition"]; ok && !v.IsNull() && v.IsKnown() {
+		v.As(&failConditions)
+	}
+	if len(failConditions) > 0 && len(conditions) == 0 {
+		diags = append(diags, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Invalid wait configuration",
+			Detail:    `A "fail_condition" block requires at least one "condition" block to wait for.`,
+			Attribute: waitPath.WithAttributeName("fail_condition"),
+		})
+	}
+	for _, cb := range []struct {
+		name   string
+		blocks []tftypes.Value
+	}{{"condition", conditions}, {"fail_condition", failConditions}} {
+		for i, b := range cb.blocks {
+			if !b.IsKnown() {
+				continue
+			}
+			var c map[string]tftypes.Value
+			b.As(&c)
+			for _, k := range []string{"reason", "message"} {
+				if _, err := conditionRegexp(c, k); err != nil {
+					diags = append(diags, &tfprotov5.Diagnostic{
+						Severity:  tfprotov5.DiagnosticSeverityError,
+						Summary:   "Invalid wait configuration",
+						Detail:    err.Error(),
+						Attribute: waitPath.WithAttributeName(cb.name).WithElementKeyInt(i).WithAttributeName(k),
+					})
+				}
+			}
+		}
+	}
+	return
+}
+
+// validateSingularDataSourceConfig checks the wait block and timeouts of the kubernetes_resource data source
+func (s *RawProviderServer) validateSingularDataSourceConfig(cfg *tfprotov5.DynamicValue) (diags []*tfprotov5.Diagnostic) {
+	rt, err := GetDataSourceType("kubernetes_resource")
+	if err != nil {
+		return
+	}
+	config, err := cfg.Unmarshal(rt)
+	if err != nil {
+		diags = append(diags, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to unmarshal data source configuration",
+			Detail:   err.Error(),
+		})
+		return
+	}
+	var dsConfig map[string]tftypes.Value
+	if err := config.As(&dsConfig); err != nil {
+		return
+	}
+
+	diags = append(diags, s.validateTimeouts(dsConfig)...)
+
+	if wait := dsConfig["wait"]; !wait.IsNull() && wait.IsKnown() {
+		var waitBlock []tftypes.Value
+		wait.As(&waitBlock)
+		if len(waitBlock) > 0 {
+			var w map[string]tftypes.Value
+			waitBlock[0].As(&w)
+			diags = append(diags, validateWaitBlock(tftypes.NewAttributePath().WithAttributeName("wait").WithElementKeyInt(0), w)...)
+		}
+	}
+	return
+}
+
+// validatePluralDataSourceConfig checks the listing options of the kubernetes_resources data source
+func validatePluralDataSourceConfig(cfg *tfprotov5.DynamicValue) (diags []*tfprotov5.Diagnostic) {
+	rt, err := GetDataSourceType("kubernetes_resources")
+	if err != nil {
+		return
+	}
+	config, err := cfg.Unmarshal(rt)
+	if err != nil {
+		diags = append(diags, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Failed to unmarshal data source configuration",
+			Detail:   err.Error(),
+		})
+		return
+	}
+	var dsConfig map[string]tftypes.Value
+	if err := config.As(&dsConfig); err != nil {
+		return
+	}
+
+	var allNamespaces bool
+	if v := dsConfig["all_namespaces"]; !v.IsNull() && v.IsKnown() {
+		v.As(&allNamespaces)
+	}
+	if v := dsConfig["namespace"]; allNamespaces && !v.IsNull() {
+		diags = append(diags, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Conflicting configuration arguments",
+			Detail:    "'namespace' cannot be set when 'all_namespaces' is true",
+			Attribute: tftypes.NewAttributePath().WithAttributeName("namespace"),
+		})
+	}
+
+	if v := dsConfig["sort_by"]; !v.IsNull() && v.IsKnown() {
+		var sortBy string
+		v.As(&sortBy)
+		if err := sortObjects(nil, sortBy); err != nil {
+			diags = append(diags, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   "Invalid sort_by",
+				Detail:    err.Error(),
+				Attribute: tftypes.NewAttributePath().WithAttributeName("sort_by"),
+			})
+		}
+	}
+
+	if v := dsConfig["limit"]; !v.IsNull() && v.IsKnown() {
+		var limit big.Float
+		v.As(&limit)
+		if limit.Sign() < 0 || !limit.IsInt() {
+			diags = append(diags, &tfprotov5.Diagnostic{
+				Severity:  tfprotov5.DiagnosticSeverityError,
+				Summary:   "Invalid limit",
+				Detail:    "'limit' must be a non-negative whole number",
+				Attribute: tftypes.NewAttributePath().WithAttributeName("limit"),
+			})
+		}
+	}
+
+	if err := addFieldPaths(make(map[string]*tftypes.AttributePath), dsConfig["fields"]); err != nil {
+		diags = append(diags, &tfprotov5.Diagnostic{
+			Severity:  tfprotov5.DiagnosticSeverityError,
+			Summary:   "Invalid fields",
+			Detail:    err.Error(),
+			Attribute: tftypes.NewAttributePath().WithAttributeName("fields"),
+		})
+	}
+	return
+}
@@ -6,11 +6,15 @@ package provider
 import (
 	"context"
 	"fmt"
+	"math"
 	"math/big"
 	"regexp"
+	"strings"
 	"time"
 
+	"github.com/google/cel-go/cel"
 	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-provider-kubernetes/manifest/payload"
 	"github.com/zclconf/go-cty/cty"
@@ -19,11 +23,20 @@ import (
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/kubectl/pkg/polymorphichelpers"
 )
 
-const waiterSleepTime = 1 * time.Second
+const (
+	// waiterInitialBackoff is the delay before retrying a failed list or watch call.
+	waiterInitialBackoff = 500 * time.Millisecond
+	// waiterMaxBackoff caps the delay between consecutive retries.
+	waiterMaxBackoff = 30 * time.Second
+)
 
 func (s *RawProviderServer) waitForCompletion(ctx context.Context, waitForBlock tftypes.Value, rs dynamic.ResourceInterface, rname string, rtype tftypes.Type, th map[string]string) error {
 	if waitForBlock.IsNull() || !waitForBlock.IsKnown() {
@@ -37,6 +50,35 @@ func (s *RawProviderServer) waitForCompletion(ctx context.Context, waitForBlock
 	return waiter.Wait(ctx)
 }
 
+// waitForResource polls for a resource until it exists, backing off between attempts,
+// and returns it. It fails with a WaiterError when the context expires first.
+func waitForResource(ctx context.Context, rs dynamic.ResourceInterface, name string, logger hclog.Logger) (*unstructured.Unstructured, error) {
+	backoff := newWaiterBackoff()
+	for {
+		res, err := rs.Get(ctx, name, v1.GetOptions{})
+		if err == nil {
+			return res, nil
+		}
+		if ctx.Err() != nil {
+			return nil, WaiterError{Reason: fmt.Sprintf("resource %q to exist", name)}
+		}
+		switch {
+		case errors.IsNotFound(err):
+		case errors.IsForbidden(err) || errors.IsUnauthorized(err) || errors.IsMethodNotSupported(err):
+			return nil, err
+		default:
+			logger.Debug("[ReadDataSource][Wait]", "Get failed, backing off", err)
+		}
+		t := time.NewTimer(backoff.Step())
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, WaiterError{Reason: fmt.Sprintf("resource %q to exist", name)}
+		case <-t.C:
+		}
+	}
+}
+
 // Waiter is a simple interface to implement a blocking wait operation
 type Waiter interface {
 	Wait(context.Context) error
@@ -70,14 +112,44 @@ func NewResourceWaiter(resource dynamic.ResourceInterface, resourceName string,
 		}
 	}
 
+	if v, ok := waitForBlockVal["expression"]; ok && !v.IsNull() && v.IsKnown() {
+		var expression string
+		v.As(&expression)
+		program, err := CompileWaitExpression(expression)
+		if err != nil {
+			return nil, err
+		}
+		return &ExpressionWaiter{
+			resource,
+			resourceName,
+			expression,
+			program,
+			hl,
+		}, nil
+	}
+
 	if v, ok := waitForBlockVal["condition"]; ok {
 		var conditionsBlocks []tftypes.Value
 		v.As(&conditionsBlocks)
 		if len(conditionsBlocks) > 0 {
+			conditions, err := NewConditionMatchers(conditionsBlocks)
+			if err != nil {
+				return nil, err
+			}
+			var failConditions []ConditionMatcher
+			if fv, ok := waitForBlockVal["fail_condition"]; ok {
+				var failConditionsBlocks []tftypes.Value
+				fv.As(&failConditionsBlocks)
+				failConditions, err = NewConditionMatchers(failConditionsBlocks)
+				if err != nil {
+					return nil, err
+				}
+			}
 			return &ConditionsWaiter{
 				resource,
 				resourceName,
-				conditionsBlocks,
+				conditions,
+				failConditions,
 				hl,
 			}, nil
 		}
@@ -130,6 +202,42 @@ func NewResourceWaiter(resource dynamic.ResourceInterface, resourceName string,
 
 }
 
+// ConditionFailedError is returned when a resource reports one of the failure conditions configured in the waiter
+type ConditionFailedError struct {
+	Type    string
+	Status  string
+	Reason  string
+	Message string
+}
+
+func (e ConditionFailedError) Error() string {
+	return fmt.Sprintf("resource reported failure condition %s=%s (reason: %q, message: %q)", e.Type, e.Status, e.Reason, e.Message)
+}
+
+// WaiterErrorToDiagnostic converts an error returned by a Waiter on resource 'rnn' into a Diagnostic
+func WaiterErrorToDiagnostic(rnn string, err error) *tfprotov5.Diagnostic {
+	switch e := err.(type) {
+	case WaiterError:
+		return &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Operation timed out",
+			Detail:   e.Error(),
+		}
+	case ConditionFailedError:
+		return &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  fmt.Sprintf("Resource %q reported failure condition %q", rnn, e.Type),
+			Detail: fmt.Sprintf("The condition %s=%s matched a \"fail_condition\" block.\n\nReason: %s\nMessage: %s",
+				e.Type, e.Status, e.Reason, e.Message),
+		}
+	}
+	return &tfprotov5.Diagnostic{
+		Severity: tfprotov5.DiagnosticSeverityError,
+		Summary:  "Error waiting for operation to complete",
+		Detail:   err.Error(),
+	}
+}
+
 // FieldMatcher contains a tftypes.AttributePath to a field and a regexp to match on it
 type FieldMatcher struct {
 	path         *tftypes.AttributePath
@@ -150,23 +258,7 @@ type FieldWaiter struct {
 // Wait blocks until all of the FieldMatchers configured evaluate to true
 func (w *FieldWaiter) Wait(ctx context.Context) error {
 	w.logger.Info("[ApplyResourceChange][Wait] Waiting until ready...\n")
-	for {
-		if deadline, ok := ctx.Deadline(); ok {
-			if time.Now().After(deadline) {
-				return WaiterError{Reason: "field matchers"}
-			}
-		}
-
-		// NOTE The typed API resource is actually returned in the
-		// event object but I haven't yet figured out how to convert it
-		// to a cty.Value.
-		res, err := w.resource.Get(ctx, w.resourceName, v1.GetOptions{})
-		if err != nil {
-			return err
-		}
-		if errors.IsGone(err) {
-			return fmt.Errorf("resource was deleted")
-		}
+	err := watchResource(ctx, w.resource, w.resourceName, w.logger, func(res *unstructured.Unstructured) (bool, error) {
 		resObj := res.Object
 		meta := resObj["metadata"].(map[string]interface{})
 		delete(meta, "managedFields")
@@ -175,7 +267,7 @@ func (w *FieldWaiter) Wait(ctx context.Context) error {
 
 		obj, err := payload.ToTFValue(resObj, w.resourceType, w.typeHints, tftypes.NewAttributePath())
 		if err != nil {
-			return err
+			return false, err
 		}
 
 		done, err := func(obj tftypes.Value) (bool, error) {
@@ -220,13 +312,20 @@ func (w *FieldWaiter) Wait(ctx context.Context) error {
 		}(obj)
 
 		if done {
-			w.logger.Info("[ApplyResourceChange][Wait] Done waiting.\n")
-			return err
+			return true, err
 		}
-
-		// TODO: implement with exponential back-off.
-		time.Sleep(waiterSleepTime) // lintignore:R018
+		// matchers that fail to resolve are expected while the
+		// resource is still converging, so keep on watching
+		return false, nil
+	})
+	if err != nil {
+		if _, ok := err.(WaiterError); ok {
+			return WaiterError{Reason: "field matchers"}
+		}
+		return err
 	}
+	w.logger.Info("[ApplyResourceChange][Wait] Done waiting.\n")
+	return nil
 }
 
 // NoopWaiter is a placeholder for when there is nothing to wait on
@@ -288,98 +387,440 @@ type RolloutWaiter struct {
 // Wait uses StatusViewer to determine if the rollout is done
 func (w *RolloutWaiter) Wait(ctx context.Context) error {
 	w.logger.Info("[ApplyResourceChange][Wait] Waiting until rollout complete...\n")
-	for {
-		if deadline, ok := ctx.Deadline(); ok {
-			if time.Now().After(deadline) {
-				return WaiterError{Reason: "rollout to complete"}
-			}
+	err := watchResource(ctx, w.resource, w.resourceName, w.logger, func(res *unstructured.Unstructured) (bool, error) {
+		gk := res.GetObjectKind().GroupVersionKind().GroupKind()
+		statusViewer, err := polymorphichelpers.StatusViewerFor(gk)
+		if err != nil {
+			return false, fmt.Errorf("error getting resource status: %v", err)
 		}
 
-		res, err := w.resource.Get(ctx, w.resourceName, v1.GetOptions{})
+		_, done, err := statusViewer.Status(res, 0)
 		if err != nil {
-			return err
+			return false, fmt.Errorf("error getting resource status: %v", err)
 		}
-		if errors.IsGone(err) {
-			return fmt.Errorf("resource was deleted")
+		return done, nil
+	})
+	if err != nil {
+		if _, ok := err.(WaiterError); ok {
+			return WaiterError{Reason: "rollout to complete"}
 		}
+		return err
+	}
 
-		gk := res.GetObjectKind().GroupVersionKind().GroupKind()
-		statusViewer, err := polymorphichelpers.StatusViewerFor(gk)
+	w.logger.Info("[ApplyResourceChange][Wait] Rollout complete\n")
+	return nil
+}
+
+// ConditionMatcher describes a status condition by its type and status,
+// optionally narrowed down by regexps to match on its reason and message
+type ConditionMatcher struct {
+	conditionType string
+	status        string
+	reason        *regexp.Regexp
+	message       *regexp.Regexp
+}
+
+// NewConditionMatchers builds ConditionMatchers out of "condition" or "fail_condition" blocks
+func NewConditionMatchers(blocks []tftypes.Value) ([]ConditionMatcher, error) {
+	var matchers []ConditionMatcher
+	for _, b := range blocks {
+		var condition map[string]tftypes.Value
+		err := b.As(&condition)
 		if err != nil {
-			return fmt.Errorf("error getting resource status: %v", err)
+			return nil, err
 		}
-
-		_, done, err := statusViewer.Status(res, 0)
+		var m ConditionMatcher
+		condition["type"].As(&m.conditionType)
+		condition["status"].As(&m.status)
+		m.reason, err = conditionRegexp(condition, "reason")
 		if err != nil {
-			return fmt.Errorf("error getting resource status: %v", err)
+			return nil, err
 		}
-
-		if done {
-			break
+		m.message, err = conditionRegexp(condition, "message")
+		if err != nil {
+			return nil, err
 		}
+		matchers = append(matchers, m)
+	}
+	return matchers, nil
+}
 
-		time.Sleep(waiterSleepTime) // lintignore:R018
+// conditionRegexp compiles the optional regexp set on attribute 'key' of a condition block
+func conditionRegexp(condition map[string]tftypes.Value, key string) (*regexp.Regexp, error) {
+	v, ok := condition[key]
+	if !ok || v.IsNull() || !v.IsKnown() {
+		return nil, nil
 	}
+	var expr string
+	v.As(&expr)
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression for condition %s: %q", key, expr)
+	}
+	return re, nil
+}
 
-	w.logger.Info("[ApplyResourceChange][Wait] Rollout complete\n")
-	return nil
+// match looks up the condition of the matcher's type among the status conditions
+// of a resource and reports if its status, reason and message match
+func (m ConditionMatcher) match(conditions []interface{}) (map[string]interface{}, bool) {
+	for _, cc := range conditions {
+		ccc, ok := cc.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if t, _ := ccc["type"].(string); t != m.conditionType {
+			continue
+		}
+		if s, _ := ccc["status"].(string); s != m.status {
+			return ccc, false
+		}
+		if r, _ := ccc["reason"].(string); m.reason != nil && !m.reason.MatchString(r) {
+			return ccc, false
+		}
+		if msg, _ := ccc["message"].(string); m.message != nil && !m.message.MatchString(msg) {
+			return ccc, false
+		}
+		return ccc, true
+	}
+	return nil, false
 }
 
 // ConditionsWaiter will wait for the specified conditions on
-// the resource to be met
+// the resource to be met, or fail as soon as any of the failure conditions is met
 type ConditionsWaiter struct {
-	resource     dynamic.ResourceInterface
-	resourceName string
-	conditions   []tftypes.Value
-	logger       hclog.Logger
+	resource       dynamic.ResourceInterface
+	resourceName   string
+	conditions     []ConditionMatcher
+	failConditions []ConditionMatcher
+	logger         hclog.Logger
 }
 
 // Wait checks all the configured conditions have been met
 func (w *ConditionsWaiter) Wait(ctx context.Context) error {
 	w.logger.Info("[ApplyResourceChange][Wait] Waiting for conditions...\n")
 
+	err := watchResource(ctx, w.resource, w.resourceName, w.logger, func(res *unstructured.Unstructured) (bool, error) {
+		status, ok := res.Object["status"].(map[string]interface{})
+		if !ok {
+			return false, nil
+		}
+		conditions, ok := status["conditions"].([]interface{})
+		if !ok || len(conditions) == 0 {
+			return false, nil
+		}
+		for _, fc := range w.failConditions {
+			if c, failed := fc.match(conditions); failed {
+				e := ConditionFailedError{Type: fc.conditionType, Status: fc.status}
+				e.Reason, _ = c["reason"].(string)
+				e.Message, _ = c["message"].(string)
+				return false, e
+			}
+		}
+		for _, c := range w.conditions {
+			if _, met := c.match(conditions); !met {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		if _, ok := err.(WaiterError); ok {
+			return WaiterError{Reason: "conditions"}
+		}
+		return err
+	}
+
+	w.logger.Info("[ApplyResourceChange][Wait] All conditions met.\n")
+	return nil
+}
+
+// watchResource blocks until the check function reports the named resource as done,
+// the resource is deleted or the context expires.
+//
+// The current state of the resource is obtained with a List call scoped to its name,
+// after which changes are streamed with a Watch resumed from the last seen resourceVersion.
+// When the resourceVersion has expired the resource is listed again. Failed calls are
+// retried with a jittered exponential back-off so that many concurrent waiters do not
+// hammer the API server. When the credentials are not allowed to list or watch the resource,
+// it is polled with Get calls instead, and authentication errors are returned right away.
+func watchResource(ctx context.Context, rs dynamic.ResourceInterface, name string, logger hclog.Logger, check func(*unstructured.Unstructured) (bool, error)) error {
+	backoff := newWaiterBackoff()
+	selector := fields.OneTermEqualSelector("metadata.name", name).String()
+
+	// sleep waits out the next back-off step, unless the context expires first
+	sleep := func() error {
+		t := time.NewTimer(backoff.Step())
+		defer t.Stop()
+		select {
+		case <-ctx.Done():
+			return WaiterError{Reason: fmt.Sprintf("resource %q", name)}
+		case <-t.C:
+			return nil
+		}
+	}
+
+	var resourceVersion string
 	for {
-		if deadline, ok := ctx.Deadline(); ok {
-			if time.Now().After(deadline) {
-				return WaiterError{Reason: "conditions"}
+		if ctx.Err() != nil {
+			return WaiterError{Reason: fmt.Sprintf("resource %q", name)}
+		}
+
+		if resourceVersion == "" {
+			res, err := rs.List(ctx, v1.ListOptions{FieldSelector: selector})
+			if err != nil {
+				if isWatchForbidden(err) {
+					logger.Debug("[ApplyResourceChange][Wait]", "List not permitted, polling instead", err)
+					return pollResource(ctx, rs, name, logger, check)
+				}
+				if errors.IsUnauthorized(err) {
+					return err
+				}
+				logger.Debug("[ApplyResourceChange][Wait]", "List failed, backing off", err)
+				if err := sleep(); err != nil {
+					return err
+				}
+				continue
 			}
+			var found bool
+			for i := range res.Items {
+				if res.Items[i].GetName() != name {
+					continue
+				}
+				found = true
+				done, err := check(&res.Items[i])
+				if err != nil {
+					return err
+				}
+				if done {
+					return nil
+				}
+			}
+			if !found {
+				return fmt.Errorf("resource was deleted")
+			}
+			resourceVersion = res.GetResourceVersion()
+			backoff = newWaiterBackoff()
 		}
 
-		res, err := w.resource.Get(ctx, w.resourceName, v1.GetOptions{})
+		w, err := rs.Watch(ctx, v1.ListOptions{
+			FieldSelector:       selector,
+			ResourceVersion:     resourceVersion,
+			AllowWatchBookmarks: true,
+		})
 		if err != nil {
+			if errors.IsResourceExpired(err) || errors.IsGone(err) {
+				resourceVersion = ""
+				continue
+			}
+			if isWatchForbidden(err) {
+				logger.Debug("[ApplyResourceChange][Wait]", "Watch not permitted, polling instead", err)
+				return pollResource(ctx, rs, name, logger, check)
+			}
+			if errors.IsUnauthorized(err) {
+				return err
+			}
+			logger.Debug("[ApplyResourceChange][Wait]", "Watch failed, backing off", err)
+			if err := sleep(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		done, rv, err := consumeWatch(ctx, w, name, check)
+		w.Stop()
+		if done || (err != nil && !isWatchRetryable(err)) || errors.IsUnauthorized(err) {
 			return err
 		}
-		if errors.IsGone(err) {
+		if isWatchForbidden(err) {
+			logger.Debug("[ApplyResourceChange][Wait]", "Watch not permitted, polling instead", err)
+			return pollResource(ctx, rs, name, logger, check)
+		}
+		if err != nil {
+			logger.Debug("[ApplyResourceChange][Wait]", "Watch interrupted", err)
+			if errors.IsResourceExpired(err) || errors.IsGone(err) {
+				resourceVersion = ""
+				continue
+			}
+		}
+		if rv != "" {
+			resourceVersion = rv
+		}
+		if err != nil || rv == "" {
+			// avoid spinning on watches that fail or close without delivering any events
+			if err := sleep(); err != nil {
+				return err
+			}
+			continue
+		}
+		backoff = newWaiterBackoff()
+	}
+}
+
+// pollResource is the fallback of watchResource for credentials that may only get the resource:
+// the resource is read with a jittered exponential back-off until the check function reports it as done,
+// the resource is deleted or the context expires.
+func pollResource(ctx context.Context, rs dynamic.ResourceInterface, name string, logger hclog.Logger, check func(*unstructured.Unstructured) (bool, error)) error {
+	backoff := newWaiterBackoff()
+	for {
+		res, err := rs.Get(ctx, name, v1.GetOptions{})
+		switch {
+		case err == nil:
+			done, err := check(res)
+			if err != nil || done {
+				return err
+			}
+		case ctx.Err() != nil:
+			return WaiterError{Reason: fmt.Sprintf("resource %q", name)}
+		case errors.IsNotFound(err):
 			return fmt.Errorf("resource was deleted")
+		case errors.IsForbidden(err) || errors.IsUnauthorized(err) || errors.IsMethodNotSupported(err):
+			return err
+		default:
+			logger.Debug("[ApplyResourceChange][Wait]", "Get failed, backing off", err)
+		}
+		t := time.NewTimer(backoff.Step())
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return WaiterError{Reason: fmt.Sprintf("resource %q", name)}
+		case <-t.C:
 		}
+	}
+}
 
-		if status, ok := res.Object["status"].(map[string]interface{}); ok {
-			if conditions, ok := status["conditions"].([]interface{}); ok && len(conditions) > 0 {
-				conditionsMet := true
-				for _, c := range w.conditions {
-					var condition map[string]tftypes.Value
-					c.As(&condition)
-					var conditionType, conditionStatus string
-					condition["type"].As(&conditionType)
-					condition["status"].As(&conditionStatus)
-					conditionMet := false
-					for _, cc := range conditions {
-						ccc := cc.(map[string]interface{})
-						if ccc["type"].(string) == conditionType {
-							conditionMet = ccc["status"].(string) == conditionStatus
-							break
-						}
-					}
-					conditionsMet = conditionsMet && conditionMet
+// isWatchForbidden reports whether an error means that the resource may not be listed or watched
+func isWatchForbidden(err error) bool {
+	return errors.IsForbidden(err) || errors.IsMethodNotSupported(err)
+}
+
+// consumeWatch processes watch events until the check function reports the resource as done,
+// the watch is closed by the server or an error occurs. It returns the last observed resourceVersion.
+func consumeWatch(ctx context.Context, w watch.Interface, name string, check func(*unstructured.Unstructured) (bool, error)) (bool, string, error) {
+	var resourceVersion string
+	for {
+		select {
+		case <-ctx.Done():
+			return false, resourceVersion, WaiterError{Reason: fmt.Sprintf("resource %q", name)}
+		case ev, ok := <-w.ResultChan():
+			if !ok {
+				// the server closes watches periodically - resume from the last resourceVersion
+				return false, resourceVersion, nil
+			}
+			switch ev.Type {
+			case watch.Error:
+				return false, resourceVersion, errors.FromObject(ev.Object)
+			case watch.Bookmark:
+				if res, ok := ev.Object.(*unstructured.Unstructured); ok {
+					resourceVersion = res.GetResourceVersion()
+				}
+			case watch.Added, watch.Modified, watch.Deleted:
+				res, ok := ev.Object.(*unstructured.Unstructured)
+				if !ok || res.GetName() != name {
+					continue
 				}
-				if conditionsMet {
-					break
+				resourceVersion = res.GetResourceVersion()
+				if ev.Type == watch.Deleted {
+					return true, resourceVersion, fmt.Errorf("resource was deleted")
+				}
+				done, err := check(res)
+				if err != nil {
+					return true, resourceVersion, err
+				}
+				if done {
+					return true, resourceVersion, nil
 				}
 			}
 		}
-		time.Sleep(waiterSleepTime) // lintignore:R018
 	}
+}
 
-	w.logger.Info("[ApplyResourceChange][Wait] All conditions met.\n")
+// isWatchRetryable reports whether an error received while consuming
+// a watch should cause the watch to be re-established
+func isWatchRetryable(err error) bool {
+	if _, ok := err.(WaiterError); ok {
+		return false
+	}
+	_, ok := err.(errors.APIStatus)
+	return ok
+}
+
+// newWaiterBackoff returns the jittered exponential back-off used between failed list and watch calls
+func newWaiterBackoff() wait.Backoff {
+	return wait.Backoff{
+		Duration: waiterInitialBackoff,
+		Factor:   2.0,
+		Jitter:   0.5,
+		Steps:    math.MaxInt32,
+		Cap:      waiterMaxBackoff,
+	}
+}
+
+// waitExpressionVariable is the name under which the live resource is exposed to wait expressions
+const waitExpressionVariable = "object"
+
+// CompileWaitExpression parses and type-checks a CEL wait expression
+// and returns a program that can be evaluated against a resource
+func CompileWaitExpression(expression string) (cel.Program, error) {
+	env, err := cel.NewEnv(cel.Variable(waitExpressionVariable, cel.DynType))
+	if err != nil {
+		return nil, err
+	}
+	ast, iss := env.Compile(expression)
+	if iss.Err() != nil {
+		return nil, fmt.Errorf("invalid wait expression: %s", iss.Err())
+	}
+	if t := ast.OutputType(); t != cel.BoolType && t != cel.DynType {
+		return nil, fmt.Errorf("wait expression must evaluate to a bool, got %s", t)
+	}
+	return env.Program(ast)
+}
+
+// isMissingFieldError reports whether a CEL evaluation error is caused by the
+// expression selecting a field that is not set on the resource. cel-go reports
+// these errors with a plain message, so they are told apart by its prefix.
+func isMissingFieldError(err error) bool {
+	msg := err.Error()
+	return strings.HasPrefix(msg, "no such key") || strings.HasPrefix(msg, "no such field")
+}
+
+// ExpressionWaiter will wait for a CEL expression evaluated
+// against the resource to return true
+type ExpressionWaiter struct {
+	resource     dynamic.ResourceInterface
+	resourceName string
+	expression   string
+	program      cel.Program
+	logger       hclog.Logger
+}
+
+// Wait blocks until the expression evaluates to true
+func (w *ExpressionWaiter) Wait(ctx context.Context) error {
+	w.logger.Info("[ApplyResourceChange][Wait] Waiting until expression is true...\n", "expression", w.expression)
+	err := watchResource(ctx, w.resource, w.resourceName, w.logger, func(res *unstructured.Unstructured) (bool, error) {
+		out, _, err := w.program.Eval(map[string]interface{}{
+			waitExpressionVariable: res.Object,
+		})
+		if err != nil {
+			// fields referenced by the expression are expected to be
+			// missing while the resource is still converging
+			if isMissingFieldError(err) {
+				w.logger.Trace("[ApplyResourceChange][Wait] Expression could not be evaluated", "error", err)
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to evaluate wait expression: %s", err)
+		}
+		done, ok := out.Value().(bool)
+		if !ok {
+			return false, fmt.Errorf("wait expression must evaluate to a bool, got %s", out.Type().TypeName())
+		}
+		return done, nil
+	})
+	if err != nil {
+		if _, ok := err.(WaiterError); ok {
+			return WaiterError{Reason: "expression"}
+		}
+		return err
+	}
+	w.logger.Info("[ApplyResourceChange][Wait] Done waiting.\n")
 	return nil
 }
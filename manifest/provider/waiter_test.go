@@ -0,0 +1,368 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var testJobGVR = schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+
+func newTestJob(conditions ...interface{}) *unstructured.Unstructured {
+	job := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "batch/v1",
+		"kind":       "Job",
+		"metadata": map[string]interface{}{
+			"name":      "test",
+			"namespace": "default",
+		},
+	}}
+	if len(conditions) > 0 {
+		job.Object["status"] = map[string]interface{}{
+			"conditions": conditions,
+		}
+	}
+	return job
+}
+
+func newTestCondition(conditionType, status, reason string) tftypes.Value {
+	rv := tftypes.NewValue(tftypes.String, nil)
+	if reason != "" {
+		rv = tftypes.NewValue(tftypes.String, reason)
+	}
+	return tftypes.NewValue(
+		tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+			"type":    tftypes.String,
+			"status":  tftypes.String,
+			"reason":  tftypes.String,
+			"message": tftypes.String,
+		}},
+		map[string]tftypes.Value{
+			"type":    tftypes.NewValue(tftypes.String, conditionType),
+			"status":  tftypes.NewValue(tftypes.String, status),
+			"reason":  rv,
+			"message": tftypes.NewValue(tftypes.String, nil),
+		},
+	)
+}
+
+func newTestConditionMatchers(t *testing.T, conditions ...tftypes.Value) []ConditionMatcher {
+	m, err := NewConditionMatchers(conditions)
+	if err != nil {
+		t.Fatalf("failed to build condition matchers: %v", err)
+	}
+	return m
+}
+
+func newTestWaiterClient(obj runtime.Object) (*fake.FakeDynamicClient, *watch.FakeWatcher) {
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{testJobGVR: "JobList"}, obj)
+	fw := watch.NewFake()
+	client.PrependWatchReactor("jobs", k8stesting.DefaultWatchReactor(fw, nil))
+	return client, fw
+}
+
+func TestConditionsWaiter(t *testing.T) {
+	t.Run("already met", func(t *testing.T) {
+		client, _ := newTestWaiterClient(newTestJob(map[string]interface{}{"type": "Complete", "status": "True"}))
+		w := &ConditionsWaiter{
+			client.Resource(testJobGVR).Namespace("default"),
+			"test",
+			newTestConditionMatchers(t, newTestCondition("Complete", "True", "")),
+			nil,
+			hclog.NewNullLogger(),
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := w.Wait(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, a := range client.Actions() {
+			if a.GetVerb() == "watch" {
+				t.Fatal("did not expect a watch when the condition is already met")
+			}
+		}
+	})
+
+	t.Run("met by watch event", func(t *testing.T) {
+		client, fw := newTestWaiterClient(newTestJob())
+		w := &ConditionsWaiter{
+			client.Resource(testJobGVR).Namespace("default"),
+			"test",
+			newTestConditionMatchers(t, newTestCondition("Complete", "True", "")),
+			nil,
+			hclog.NewNullLogger(),
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		errCh := make(chan error)
+		go func() { errCh <- w.Wait(ctx) }()
+
+		fw.Modify(newTestJob(map[string]interface{}{"type": "Complete", "status": "False"}))
+		fw.Modify(newTestJob(map[string]interface{}{"type": "Complete", "status": "True"}))
+		if err := <-errCh; err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("deleted", func(t *testing.T) {
+		client, fw := newTestWaiterClient(newTestJob())
+		w := &ConditionsWaiter{
+			client.Resource(testJobGVR).Namespace("default"),
+			"test",
+			newTestConditionMatchers(t, newTestCondition("Complete", "True", "")),
+			nil,
+			hclog.NewNullLogger(),
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		errCh := make(chan error)
+		go func() { errCh <- w.Wait(ctx) }()
+
+		fw.Delete(newTestJob())
+		if err := <-errCh; err == nil || err.Error() != "resource was deleted" {
+			t.Fatalf("expected deletion error, got: %v", err)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		client, _ := newTestWaiterClient(newTestJob())
+		w := &ConditionsWaiter{
+			client.Resource(testJobGVR).Namespace("default"),
+			"test",
+			newTestConditionMatchers(t, newTestCondition("Complete", "True", "")),
+			nil,
+			hclog.NewNullLogger(),
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		err := w.Wait(ctx)
+		if _, ok := err.(WaiterError); !ok {
+			t.Fatalf("expected WaiterError, got: %v", err)
+		}
+	})
+
+	t.Run("failure condition", func(t *testing.T) {
+		client, fw := newTestWaiterClient(newTestJob())
+		w := &ConditionsWaiter{
+			client.Resource(testJobGVR).Namespace("default"),
+			"test",
+			newTestConditionMatchers(t, newTestCondition("Complete", "True", "")),
+			newTestConditionMatchers(t, newTestCondition("Failed", "True", "^Backoff")),
+			hclog.NewNullLogger(),
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		errCh := make(chan error)
+		go func() { errCh <- w.Wait(ctx) }()
+
+		fw.Modify(newTestJob(map[string]interface{}{
+			"type":    "Failed",
+			"status":  "True",
+			"reason":  "BackoffLimitExceeded",
+			"message": "Job has reached the specified backoff limit",
+		}))
+		err := <-errCh
+		failed, ok := err.(ConditionFailedError)
+		if !ok {
+			t.Fatalf("expected ConditionFailedError, got: %v", err)
+		}
+		if failed.Reason != "BackoffLimitExceeded" || failed.Message != "Job has reached the specified backoff limit" {
+			t.Fatalf("unexpected failure details: %#v", failed)
+		}
+	})
+
+	t.Run("list forbidden", func(t *testing.T) {
+		client, _ := newTestWaiterClient(newTestJob())
+		client.PrependReactor("list", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewForbidden(testJobGVR.GroupResource(), "", nil)
+		})
+		gets := 0
+		client.PrependReactor("get", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			gets++
+			if gets < 2 {
+				return true, newTestJob(), nil
+			}
+			return true, newTestJob(map[string]interface{}{"type": "Complete", "status": "True"}), nil
+		})
+		w := &ConditionsWaiter{
+			client.Resource(testJobGVR).Namespace("default"),
+			"test",
+			newTestConditionMatchers(t, newTestCondition("Complete", "True", "")),
+			nil,
+			hclog.NewNullLogger(),
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := w.Wait(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if gets != 2 {
+			t.Fatalf("expected the resource to be polled twice, got %d", gets)
+		}
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		client, _ := newTestWaiterClient(newTestJob())
+		client.PrependReactor("list", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewUnauthorized("test")
+		})
+		w := &ConditionsWaiter{
+			client.Resource(testJobGVR).Namespace("default"),
+			"test",
+			newTestConditionMatchers(t, newTestCondition("Complete", "True", "")),
+			nil,
+			hclog.NewNullLogger(),
+		}
+		if err := w.Wait(context.Background()); !apierrors.IsUnauthorized(err) {
+			t.Fatalf("expected the error to be returned, got: %v", err)
+		}
+	})
+}
+
+func TestCompileWaitExpression(t *testing.T) {
+	samples := map[string]bool{
+		`object.status.readyReplicas >= object.spec.replicas`:             true,
+		`object.status.conditions.all(c, c.status == "True")`:             true,
+		`has(object.status.succeeded) && object.status.succeeded > 0`:     true,
+		`object.status.readyReplicas >=`:                                  false,
+		`"ready"`:                                                         false,
+		`unknown.status.ready`:                                            false,
+		`object.status.conditions.exists(c, c.type == "Ready") || 1 == 1`: true,
+	}
+	for expr, valid := range samples {
+		_, err := CompileWaitExpression(expr)
+		if valid && err != nil {
+			t.Errorf("expected %q to compile, got: %v", expr, err)
+		}
+		if !valid && err == nil {
+			t.Errorf("expected %q to fail to compile", expr)
+		}
+	}
+}
+
+func TestExpressionWaiter(t *testing.T) {
+	// status is not set until the job makes progress, which is not an error
+	expression := `object.status.succeeded >= object.spec.completions`
+	program, err := CompileWaitExpression(expression)
+	if err != nil {
+		t.Fatalf("failed to compile expression: %v", err)
+	}
+	newJob := func(succeeded int64) *unstructured.Unstructured {
+		job := newTestJob()
+		job.Object["spec"] = map[string]interface{}{"completions": int64(2)}
+		if succeeded > 0 {
+			job.Object["status"] = map[string]interface{}{"succeeded": succeeded}
+		}
+		return job
+	}
+
+	client, fw := newTestWaiterClient(newJob(0))
+	w := &ExpressionWaiter{
+		client.Resource(testJobGVR).Namespace("default"),
+		"test",
+		expression,
+		program,
+		hclog.NewNullLogger(),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	errCh := make(chan error)
+	go func() { errCh <- w.Wait(ctx) }()
+
+	fw.Modify(newJob(1))
+	fw.Modify(newJob(2))
+	if err := <-errCh; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// evaluation errors other than missing fields are returned
+	expression = `object.status.succeeded / 0 > 0`
+	program, err = CompileWaitExpression(expression)
+	if err != nil {
+		t.Fatalf("failed to compile expression: %v", err)
+	}
+	client, _ = newTestWaiterClient(newJob(1))
+	w = &ExpressionWaiter{
+		client.Resource(testJobGVR).Namespace("default"),
+		"test",
+		expression,
+		program,
+		hclog.NewNullLogger(),
+	}
+	if err := w.Wait(ctx); err == nil {
+		t.Fatal("expected the evaluation error to be returned")
+	} else if _, ok := err.(WaiterError); ok {
+		t.Fatalf("expected the evaluation error to be returned, got: %v", err)
+	}
+
+	program, err = CompileWaitExpression(`object.status.succeeded > "1"`)
+	if err != nil {
+		t.Fatalf("failed to compile expression: %v", err)
+	}
+	client, _ = newTestWaiterClient(newJob(1))
+	w.resource = client.Resource(testJobGVR).Namespace("default")
+	w.program = program
+	if err := w.Wait(ctx); err == nil {
+		t.Fatal("expected the type error to be returned")
+	} else if _, ok := err.(WaiterError); ok {
+		t.Fatalf("expected the type error to be returned, got: %v", err)
+	}
+}
+
+func TestWaitForResource(t *testing.T) {
+	t.Run("created later", func(t *testing.T) {
+		client := fake.NewSimpleDynamicClient(runtime.NewScheme())
+		gets := 0
+		client.PrependReactor("get", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			gets++
+			if gets < 3 {
+				return true, nil, apierrors.NewNotFound(testJobGVR.GroupResource(), "test")
+			}
+			return true, newTestJob(), nil
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		res, err := waitForResource(ctx, client.Resource(testJobGVR).Namespace("default"), "test", hclog.NewNullLogger())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res.GetName() != "test" || gets != 3 {
+			t.Fatalf("expected the resource after 3 attempts, got %q after %d", res.GetName(), gets)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		client := fake.NewSimpleDynamicClient(runtime.NewScheme())
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		_, err := waitForResource(ctx, client.Resource(testJobGVR).Namespace("default"), "test", hclog.NewNullLogger())
+		if _, ok := err.(WaiterError); !ok {
+			t.Fatalf("expected a WaiterError, got: %v", err)
+		}
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		client := fake.NewSimpleDynamicClient(runtime.NewScheme())
+		client.PrependReactor("get", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewForbidden(testJobGVR.GroupResource(), "test", nil)
+		})
+		_, err := waitForResource(context.Background(), client.Resource(testJobGVR).Namespace("default"), "test", hclog.NewNullLogger())
+		if !apierrors.IsForbidden(err) {
+			t.Fatalf("expected the error to be returned, got: %v", err)
+		}
+	})
+}
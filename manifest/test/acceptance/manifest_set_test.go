@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build acceptance
+// +build acceptance
+
+package acceptance
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-provider-kubernetes/manifest/provider"
+	tfstatehelper "github.com/hashicorp/terraform-provider-kubernetes/manifest/test/helper/state"
+)
+
+func TestKubernetesManifestSet(t *testing.T) {
+	ctx := context.Background()
+
+	reattachInfo, err := provider.ServeTest(ctx, hclog.Default(), t)
+	if err != nil {
+		t.Errorf("Failed to create provider instance: %q", err)
+	}
+
+	name := randName()
+	namespace := randName()
+
+	tf := tfhelper.RequireNewWorkingDir(ctx, t)
+	tf.SetReattachInfo(ctx, reattachInfo)
+	defer func() {
+		tf.Destroy(ctx)
+		tf.Close()
+		k8shelper.AssertNamespacedResourceDoesNotExist(t, "v1", "configmaps", namespace, name)
+		k8shelper.AssertResourceDoesNotExist(t, "v1", "namespaces", namespace)
+	}()
+
+	tfvars := TFVARS{
+		"namespace": namespace,
+		"name":      name,
+	}
+	tfconfig := loadTerraformConfig(t, "ManifestSet/manifest_set.tf", tfvars)
+	tf.SetConfig(ctx, tfconfig)
+	tf.Init(ctx)
+	tf.Apply(ctx)
+
+	// the Namespace is listed last but has to be created first
+	k8shelper.AssertResourceExists(t, "v1", "namespaces", namespace)
+	k8shelper.AssertNamespacedResourceExists(t, "v1", "configmaps", namespace, name)
+	k8shelper.AssertNamespacedResourceExists(t, "v1", "secrets", namespace, name)
+
+	configMapKey := fmt.Sprintf("v1/ConfigMap/%s/%s", namespace, name)
+	s, err := tf.State(ctx)
+	if err != nil {
+		t.Fatalf("Failed to retrieve terraform state: %q", err)
+	}
+	tfstate := tfstatehelper.NewHelper(s)
+	tfstate.AssertAttributeValues(t, tfstatehelper.AttributeValues{
+		"kubernetes_manifest_set.test.objects." + configMapKey + ".metadata.name": name,
+		"kubernetes_manifest_set.test.objects." + configMapKey + ".data.foo":      "bar",
+	})
+	tfstate.AssertAttributeLen(t, "kubernetes_manifest_set.test.objects", 3)
+
+	// the Secret is removed from the set and should be pruned
+	tfconfigModified := loadTerraformConfig(t, "ManifestSet/manifest_set_modified.tf", tfvars)
+	tf.SetConfig(ctx, tfconfigModified)
+	tf.Apply(ctx)
+
+	k8shelper.AssertNamespacedResourceDoesNotExist(t, "v1", "secrets", namespace, name)
+
+	s, err = tf.State(ctx)
+	if err != nil {
+		t.Fatalf("Failed to retrieve terraform state: %q", err)
+	}
+	tfstate = tfstatehelper.NewHelper(s)
+	tfstate.AssertAttributeValues(t, tfstatehelper.AttributeValues{
+		"kubernetes_manifest_set.test.objects." + configMapKey + ".data.foo": "baz",
+	})
+	tfstate.AssertAttributeLen(t, "kubernetes_manifest_set.test.objects", 2)
+}
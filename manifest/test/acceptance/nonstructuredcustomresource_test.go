@@ -115,9 +115,9 @@ func TestKubernetesManifest_NonStructuredCustomResource(t *testing.T) {
 	step2.Init(ctx)
 	step2.Apply(ctx)
 
-	// updating a non-structured custom resource should force a replacement
-	// so the generation should be 1
-	k8shelper.AssertResourceGeneration(t, groupVersion, plural, namespace, name, 1)
+	// updating a non-structured custom resource should happen in place
+	// so the generation should be 2
+	k8shelper.AssertResourceGeneration(t, groupVersion, plural, namespace, name, 2)
 
 	s2, err = step2.State(ctx)
 	if err != nil {
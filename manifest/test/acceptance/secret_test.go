@@ -58,7 +58,9 @@ func TestKubernetesManifest_Secret(t *testing.T) {
 		"kubernetes_manifest.test.object.metadata.namespace": namespace,
 		"kubernetes_manifest.test.object.metadata.name":      name,
 
-		"kubernetes_manifest.test.object.data.PGUSER":     "username",
-		"kubernetes_manifest.test.object.data.PGPASSWORD": "password",
+		// Secret data is sensitive and only kept in "manifest"
+		"kubernetes_manifest.test.object.data.PGUSER":     "(sensitive value)",
+		"kubernetes_manifest.test.object.data.PGPASSWORD": "(sensitive value)",
+		"kubernetes_manifest.test.manifest.data.PGUSER":   "username",
 	})
 }
@@ -25,3 +25,15 @@ variable "group_version" {
 variable "kind" {
   type = string
 }
+
+variable "cr_version" {
+  type = string
+}
+
+variable "group" {
+  type = string
+}
+
+variable "plural" {
+  type = string
+}
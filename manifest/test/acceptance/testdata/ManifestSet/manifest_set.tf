@@ -0,0 +1,27 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+resource "kubernetes_manifest_set" "test" {
+  content = <<-EOT
+    apiVersion: v1
+    kind: ConfigMap
+    metadata:
+      name: ${var.name}
+      namespace: ${var.namespace}
+    data:
+      foo: bar
+    ---
+    apiVersion: v1
+    kind: Secret
+    metadata:
+      name: ${var.name}
+      namespace: ${var.namespace}
+    stringData:
+      password: secret
+    ---
+    apiVersion: v1
+    kind: Namespace
+    metadata:
+      name: ${var.namespace}
+  EOT
+}
@@ -0,0 +1,24 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+resource "kubernetes_manifest_set" "test" {
+  content = <<-EOT
+    apiVersion: v1
+    kind: ConfigMap
+    metadata:
+      name: ${var.name}
+      namespace: ${var.namespace}
+    data:
+      foo: baz
+    ---
+    apiVersion: v1
+    kind: Namespace
+    metadata:
+      name: ${var.namespace}
+  EOT
+
+  wait {
+    kind       = "ConfigMap"
+    expression = "object.data.foo == 'baz'"
+  }
+}
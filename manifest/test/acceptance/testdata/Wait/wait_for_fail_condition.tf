@@ -0,0 +1,47 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+resource "kubernetes_manifest" "test" {
+  manifest = {
+    apiVersion = "batch/v1"
+    kind       = "Job"
+
+    metadata = {
+      name      = var.name
+      namespace = var.namespace
+    }
+
+    spec = {
+      backoffLimit = 0
+      template = {
+        spec = {
+          restartPolicy = "Never"
+          containers = [
+            {
+              name    = "fail"
+              image   = "busybox"
+              command = ["false"]
+            }
+          ]
+        }
+      }
+    }
+  }
+
+  wait {
+    condition {
+      type   = "Complete"
+      status = "True"
+    }
+
+    fail_condition {
+      type   = "Failed"
+      status = "True"
+      reason = "BackoffLimitExceeded"
+    }
+  }
+
+  timeouts {
+    create = "5m"
+  }
+}
@@ -0,0 +1,320 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+// DefaultRetryableStatusCodes are the HTTP status codes of the responses which are retried
+// when the "retry" block of the provider configuration doesn't list any. These include
+// the 500 errors returned by the API server while etcd elects a new leader.
+var DefaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+const (
+	DefaultRetryMaxAttempts = 5
+	DefaultRetryMinBackoff  = 500 * time.Millisecond
+	DefaultRetryMaxBackoff  = 30 * time.Second
+)
+
+// ClientOptions holds the rate limiting, timeout and retry settings of the provider configuration,
+// which are applied to the client configuration of both the manifest provider and the main provider.
+type ClientOptions struct {
+	// QPS and Burst configure the client-side rate limiter, client-go defaults are used when zero
+	QPS   float32
+	Burst int
+	// Timeout is the maximum duration of a single request, no timeout is set when zero.
+	// Watch requests, which stay open while waiting for resources, are not limited.
+	Timeout time.Duration
+	// Retry is nil when failed requests should not be retried by the provider. Otherwise
+	// client-go doesn't retry the responses with a Retry-After header which have a status
+	// retried by the policy, so that they are not retried twice.
+	Retry *RetryPolicy
+}
+
+// RetryPolicy describes which failed requests are retried and how often
+type RetryPolicy struct {
+	MaxAttempts          int
+	MinBackoff           time.Duration
+	MaxBackoff           time.Duration
+	RetryableStatusCodes []int
+	RetryOnConflict      bool
+}
+
+// NewRetryPolicy returns a policy with the default settings, to be overridden by the provider configuration
+func NewRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:          DefaultRetryMaxAttempts,
+		MinBackoff:           DefaultRetryMinBackoff,
+		MaxBackoff:           DefaultRetryMaxBackoff,
+		RetryableStatusCodes: DefaultRetryableStatusCodes,
+	}
+}
+
+// Apply sets the options on a client configuration. Clients created from the configuration afterwards,
+// whether typed, dynamic or discovery clients, share the same rate limits, timeout and retry policy.
+// The timeout is set on each request by a transport rather than with the Timeout of the configuration,
+// which client-go also applies to watch requests. The retry transport wraps any transport already set,
+// including the timeout one, so that each attempt is logged separately and has its own deadline.
+func (o ClientOptions) Apply(cfg *rest.Config) {
+	if o.QPS > 0 {
+		cfg.QPS = o.QPS
+	}
+	if o.Burst > 0 {
+		cfg.Burst = o.Burst
+	}
+	if o.Timeout > 0 {
+		timeout := o.Timeout
+		cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			return &timeoutRoundTripper{rt: rt, timeout: timeout}
+		})
+	}
+	if o.Retry != nil {
+		p := *o.Retry
+		cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			return &retryRoundTripper{rt: rt, policy: p}
+		})
+	}
+}
+
+type timeoutRoundTripper struct {
+	rt      http.RoundTripper
+	timeout time.Duration
+}
+
+func (t *timeoutRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if isWatch(req) {
+		return t.rt.RoundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.rt.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// the deadline also applies to reading the body of the response
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// isWatch checks whether a request opens a watch, which stays open until the client closes it
+func isWatch(req *http.Request) bool {
+	switch req.URL.Query().Get("watch") {
+	case "true", "1":
+		return true
+	}
+	return false
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	defer c.cancel()
+	return c.ReadCloser.Close()
+}
+
+type retryRoundTripper struct {
+	rt     http.RoundTripper
+	policy RetryPolicy
+	// sleep waits between attempts, it is replaced in tests
+	sleep func(req *http.Request, d time.Duration) error
+}
+
+func (t *retryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// requests with a body can only be retried when the body can be read again
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		resp, err := t.rt.RoundTrip(req)
+		if err == nil {
+			t.stripRetryAfter(resp)
+		}
+		return resp, err
+	}
+	backoff := t.policy.MinBackoff
+	for attempt := 1; ; attempt++ {
+		r := req
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r = req.Clone(req.Context())
+			r.Body = body
+		}
+		resp, err := t.rt.RoundTrip(r)
+		if err != nil {
+			return resp, err
+		}
+		if attempt >= t.policy.MaxAttempts || !t.shouldRetry(req, resp) {
+			t.stripRetryAfter(resp)
+			return resp, nil
+		}
+
+		delay := backoff
+		if ra, ok := retryAfter(resp); ok {
+			delay = ra
+		}
+		if t.policy.MaxBackoff > 0 && delay > t.policy.MaxBackoff {
+			delay = t.policy.MaxBackoff
+		}
+		// the response of the failed attempt is discarded
+		io.Copy(io.Discard, resp.Body) //nolint:errcheck
+		resp.Body.Close()
+
+		sleep := t.sleep
+		if sleep == nil {
+			sleep = sleepContext
+		}
+		if err := sleep(req, delay); err != nil {
+			return nil, err
+		}
+		backoff *= 2
+	}
+}
+
+// shouldRetry checks the status of a response against the policy. Requests which are not idempotent,
+// like creations and patches, are only retried when the server asked to come back later, since the
+// other errors may be returned after the request was processed.
+// Conflicts are only retried when caused by a concurrent change of the resource and the request doesn't
+// require a resourceVersion, which would still be stale when retried. Neither are conflicts caused by
+// fields owned by another field manager or by a resource that already exists, since retrying cannot
+// resolve these.
+func (t *retryRoundTripper) shouldRetry(req *http.Request, resp *http.Response) bool {
+	if resp.StatusCode == http.StatusConflict {
+		return t.policy.RetryOnConflict && !hasResourceVersion(req) && isOptimisticLockConflict(resp)
+	}
+	retryable := t.isRetryableStatus(resp.StatusCode)
+	if !retryable || isIdempotent(req.Method) {
+		return retryable
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	_, ok := retryAfter(resp)
+	return resp.StatusCode == http.StatusServiceUnavailable && ok
+}
+
+func (t *retryRoundTripper) isRetryableStatus(code int) bool {
+	for _, c := range t.policy.RetryableStatusCodes {
+		if code == c {
+			return true
+		}
+	}
+	return false
+}
+
+// stripRetryAfter removes the Retry-After header of a response with a status retried by the policy.
+// client-go retries responses with a Retry-After header by itself, up to 10 times, which would multiply
+// the attempts of the policy. The header is kept for the other statuses, which are left to client-go.
+func (t *retryRoundTripper) stripRetryAfter(resp *http.Response) {
+	if t.isRetryableStatus(resp.StatusCode) {
+		resp.Header.Del("Retry-After")
+	}
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// hasResourceVersion checks whether the body of a request sets the resourceVersion of the object
+// or of the preconditions of a deletion. Bodies which cannot be decoded are assumed to set one.
+func hasResourceVersion(req *http.Request) bool {
+	if req.GetBody == nil {
+		return false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return true
+	}
+	defer body.Close()
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return true
+	}
+	if len(bytes.TrimSpace(b)) == 0 {
+		return false
+	}
+	var obj struct {
+		Metadata struct {
+			ResourceVersion string `json:"resourceVersion"`
+		} `json:"metadata"`
+		Preconditions struct {
+			ResourceVersion *string `json:"resourceVersion"`
+		} `json:"preconditions"`
+	}
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return true
+	}
+	return obj.Metadata.ResourceVersion != "" || obj.Preconditions.ResourceVersion != nil
+}
+
+func isOptimisticLockConflict(resp *http.Response) bool {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	// the body is restored for the caller, in case the response isn't retried
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	var status metav1.Status
+	if err := json.Unmarshal(body, &status); err != nil {
+		return false
+	}
+	if status.Reason != metav1.StatusReasonConflict {
+		return false
+	}
+	if status.Details != nil {
+		for _, c := range status.Details.Causes {
+			if c.Type == metav1.CauseTypeFieldManagerConflict {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// retryAfter returns the delay requested by the server in the Retry-After header, in seconds
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	s, err := strconv.Atoi(v)
+	if err != nil || s < 0 {
+		return 0, false
+	}
+	return time.Duration(s) * time.Second, true
+}
+
+func sleepContext(req *http.Request, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-req.Context().Done():
+		return req.Context().Err()
+	case <-timer.C:
+		return nil
+	}
+}
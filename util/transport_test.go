@@ -0,0 +1,334 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func newTestResponse(code int, header http.Header, status *metav1.Status) *http.Response {
+	body := []byte("{}")
+	if status != nil {
+		body, _ = json.Marshal(status)
+	}
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{StatusCode: code, Header: header, Body: io.NopCloser(bytes.NewReader(body))}
+}
+
+func TestRetryRoundTripper(t *testing.T) {
+	conflict := &metav1.Status{Reason: metav1.StatusReasonConflict, Code: http.StatusConflict}
+	fieldManagerConflict := &metav1.Status{Reason: metav1.StatusReasonConflict, Code: http.StatusConflict, Details: &metav1.StatusDetails{
+		Causes: []metav1.StatusCause{{Type: metav1.CauseTypeFieldManagerConflict}},
+	}}
+	alreadyExists := &metav1.Status{Reason: metav1.StatusReasonAlreadyExists, Code: http.StatusConflict}
+
+	cases := map[string]struct {
+		method           string
+		body             string
+		responses        []*http.Response
+		retryOnConflict  bool
+		expectedAttempts int
+		expectedCode     int
+		expectedDelays   []time.Duration
+	}{
+		"success": {
+			responses:        []*http.Response{newTestResponse(200, nil, nil)},
+			expectedAttempts: 1,
+			expectedCode:     200,
+		},
+		"retried until success with exponential backoff": {
+			responses: []*http.Response{
+				newTestResponse(503, nil, nil),
+				newTestResponse(500, nil, nil),
+				newTestResponse(200, nil, nil),
+			},
+			expectedAttempts: 3,
+			expectedCode:     200,
+			expectedDelays:   []time.Duration{time.Second, 2 * time.Second},
+		},
+		"retry-after header": {
+			responses: []*http.Response{
+				newTestResponse(429, http.Header{"Retry-After": []string{"3"}}, nil),
+				newTestResponse(200, nil, nil),
+			},
+			expectedAttempts: 2,
+			expectedCode:     200,
+			expectedDelays:   []time.Duration{3 * time.Second},
+		},
+		"retry-after capped to max backoff": {
+			responses: []*http.Response{
+				newTestResponse(429, http.Header{"Retry-After": []string{"60"}}, nil),
+				newTestResponse(200, nil, nil),
+			},
+			expectedAttempts: 2,
+			expectedCode:     200,
+			expectedDelays:   []time.Duration{5 * time.Second},
+		},
+		"max attempts": {
+			responses: []*http.Response{
+				newTestResponse(502, nil, nil),
+				newTestResponse(502, nil, nil),
+				newTestResponse(502, nil, nil),
+				newTestResponse(502, nil, nil),
+			},
+			expectedAttempts: 4,
+			expectedCode:     502,
+			expectedDelays:   []time.Duration{time.Second, 2 * time.Second, 4 * time.Second},
+		},
+		"not retryable": {
+			responses:        []*http.Response{newTestResponse(404, nil, nil)},
+			expectedAttempts: 1,
+			expectedCode:     404,
+		},
+		"conflict not retried by default": {
+			responses:        []*http.Response{newTestResponse(409, nil, conflict)},
+			expectedAttempts: 1,
+			expectedCode:     409,
+		},
+		"conflict retried": {
+			responses: []*http.Response{
+				newTestResponse(409, nil, conflict),
+				newTestResponse(200, nil, nil),
+			},
+			retryOnConflict:  true,
+			expectedAttempts: 2,
+			expectedCode:     200,
+			expectedDelays:   []time.Duration{time.Second},
+		},
+		"conflict with a resource version": {
+			body:             `{"metadata":{"resourceVersion":"1"},"data":{}}`,
+			responses:        []*http.Response{newTestResponse(409, nil, conflict)},
+			retryOnConflict:  true,
+			expectedAttempts: 1,
+			expectedCode:     409,
+		},
+		"conflict with a resource version precondition": {
+			method:           http.MethodDelete,
+			body:             `{"preconditions":{"resourceVersion":"1"}}`,
+			responses:        []*http.Response{newTestResponse(409, nil, conflict)},
+			retryOnConflict:  true,
+			expectedAttempts: 1,
+			expectedCode:     409,
+		},
+		"conflict with an undecodable body": {
+			body:             `[{"op":"replace","path":"/data","value":{}}]`,
+			responses:        []*http.Response{newTestResponse(409, nil, conflict)},
+			retryOnConflict:  true,
+			expectedAttempts: 1,
+			expectedCode:     409,
+		},
+		"patch not retried on server error": {
+			method:           http.MethodPatch,
+			responses:        []*http.Response{newTestResponse(500, nil, nil)},
+			expectedAttempts: 1,
+			expectedCode:     500,
+		},
+		"post not retried without retry-after": {
+			method:           http.MethodPost,
+			responses:        []*http.Response{newTestResponse(503, nil, nil)},
+			expectedAttempts: 1,
+			expectedCode:     503,
+		},
+		"post retried with retry-after": {
+			method: http.MethodPost,
+			responses: []*http.Response{
+				newTestResponse(503, http.Header{"Retry-After": []string{"1"}}, nil),
+				newTestResponse(429, nil, nil),
+				newTestResponse(201, nil, nil),
+			},
+			expectedAttempts: 3,
+			expectedCode:     201,
+			expectedDelays:   []time.Duration{time.Second, 2 * time.Second},
+		},
+		"field manager conflict": {
+			responses:        []*http.Response{newTestResponse(409, nil, fieldManagerConflict)},
+			retryOnConflict:  true,
+			expectedAttempts: 1,
+			expectedCode:     409,
+		},
+		"already exists": {
+			responses:        []*http.Response{newTestResponse(409, nil, alreadyExists)},
+			retryOnConflict:  true,
+			expectedAttempts: 1,
+			expectedCode:     409,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var bodies []string
+			var delays []time.Duration
+			rt := &retryRoundTripper{
+				rt: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+					b, _ := io.ReadAll(req.Body)
+					bodies = append(bodies, string(b))
+					return tc.responses[len(bodies)-1], nil
+				}),
+				policy: RetryPolicy{
+					MaxAttempts:          4,
+					MinBackoff:           time.Second,
+					MaxBackoff:           5 * time.Second,
+					RetryableStatusCodes: DefaultRetryableStatusCodes,
+					RetryOnConflict:      tc.retryOnConflict,
+				},
+				sleep: func(req *http.Request, d time.Duration) error {
+					delays = append(delays, d)
+					return nil
+				},
+			}
+			method, body := tc.method, tc.body
+			if method == "" {
+				method = http.MethodPut
+			}
+			if body == "" {
+				body = `{"data":{}}`
+			}
+			req, err := http.NewRequest(method, "https://example.com/api/v1/namespaces/default/configmaps/test", strings.NewReader(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := rt.RoundTrip(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.StatusCode != tc.expectedCode {
+				t.Fatalf("expected status %d, got %d", tc.expectedCode, resp.StatusCode)
+			}
+			if len(bodies) != tc.expectedAttempts {
+				t.Fatalf("expected %d attempts, got %d", tc.expectedAttempts, len(bodies))
+			}
+			for _, b := range bodies {
+				if b != body {
+					t.Fatalf("expected the request body to be sent on each attempt, got %q", b)
+				}
+			}
+			if len(delays) != len(tc.expectedDelays) {
+				t.Fatalf("expected delays %v, got %v", tc.expectedDelays, delays)
+			}
+			for i := range delays {
+				if delays[i] != tc.expectedDelays[i] {
+					t.Fatalf("expected delays %v, got %v", tc.expectedDelays, delays)
+				}
+			}
+			if resp.StatusCode == http.StatusConflict {
+				if b, _ := io.ReadAll(resp.Body); len(b) == 0 {
+					t.Fatal("expected the body of the conflict response to be returned")
+				}
+			}
+		})
+	}
+}
+
+func TestClientOptionsApply(t *testing.T) {
+	cfg := &rest.Config{}
+	ClientOptions{}.Apply(cfg)
+	if cfg.QPS != 0 || cfg.Burst != 0 || cfg.Timeout != 0 || cfg.WrapTransport != nil {
+		t.Fatalf("expected the configuration to be left unchanged, got %#v", cfg)
+	}
+
+	ClientOptions{QPS: 50, Burst: 100, Timeout: time.Minute, Retry: NewRetryPolicy()}.Apply(cfg)
+	if cfg.QPS != 50 || cfg.Burst != 100 {
+		t.Fatalf("unexpected configuration %#v", cfg)
+	}
+	// client-go applies the Timeout of the configuration to watch requests too
+	if cfg.Timeout != 0 {
+		t.Fatalf("expected the timeout to be set on each request, got %s", cfg.Timeout)
+	}
+	rrt, ok := cfg.WrapTransport(http.DefaultTransport).(*retryRoundTripper)
+	if !ok {
+		t.Fatal("expected the transport to be wrapped by the retry transport")
+	}
+	if _, ok := rrt.rt.(*timeoutRoundTripper); !ok {
+		t.Fatal("expected each attempt to be wrapped by the timeout transport")
+	}
+}
+
+func TestTimeoutRoundTripper(t *testing.T) {
+	var deadline time.Time
+	var hasDeadline bool
+	rt := &timeoutRoundTripper{
+		rt: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			deadline, hasDeadline = req.Context().Deadline()
+			return newTestResponse(200, nil, nil), nil
+		}),
+		timeout: time.Minute,
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com/api/v1/namespaces/default/configmaps", nil)
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if !hasDeadline || time.Until(deadline) > time.Minute {
+		t.Fatalf("expected the request to have a deadline within the timeout, got %v", deadline)
+	}
+
+	for _, q := range []string{"watch=true", "watch=1"} {
+		req, _ = http.NewRequest(http.MethodGet, "https://example.com/api/v1/namespaces/default/configmaps?"+q, nil)
+		resp, err = rt.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if hasDeadline {
+			t.Fatalf("expected the watch request %q to have no deadline, got %v", q, deadline)
+		}
+	}
+}
+
+func TestRetryRoundTripperRetryAfter(t *testing.T) {
+	// responses returned by the retry transport must not be retried again by client-go
+	attempts := 0
+	rt := &retryRoundTripper{
+		rt: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			attempts++
+			return newTestResponse(429, http.Header{"Retry-After": []string{"1"}}, nil), nil
+		}),
+		policy: RetryPolicy{MaxAttempts: 2, RetryableStatusCodes: DefaultRetryableStatusCodes},
+		sleep:  func(*http.Request, time.Duration) error { return nil },
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com/api/v1/namespaces", nil)
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 2 {
+		t.Fatalf("expected 2 attempts, got %d", attempts)
+	}
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		t.Fatalf("expected the Retry-After header to be removed, got %q", v)
+	}
+
+	// statuses which are not retried by the policy are left to client-go
+	attempts = 0
+	rt.policy.RetryableStatusCodes = []int{500}
+	resp, err = rt.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 1 {
+		t.Fatalf("expected 1 attempt, got %d", attempts)
+	}
+	if v := resp.Header.Get("Retry-After"); v != "1" {
+		t.Fatalf("expected the Retry-After header to be kept, got %q", v)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package util
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// typedResourceKinds maps the typed resources of the main provider which can be moved to and from
+// kubernetes_manifest to the kind of the object they manage. The IDs of these resources are
+// "<namespace>/<name>", or "<name>" for cluster-wide objects.
+var typedResourceKinds = map[string]schema.GroupVersionKind{
+	// core
+	"kubernetes_namespace":                  {Version: "v1", Kind: "Namespace"},
+	"kubernetes_namespace_v1":               {Version: "v1", Kind: "Namespace"},
+	"kubernetes_service":                    {Version: "v1", Kind: "Service"},
+	"kubernetes_service_v1":                 {Version: "v1", Kind: "Service"},
+	"kubernetes_service_account":            {Version: "v1", Kind: "ServiceAccount"},
+	"kubernetes_service_account_v1":         {Version: "v1", Kind: "ServiceAccount"},
+	"kubernetes_config_map":                 {Version: "v1", Kind: "ConfigMap"},
+	"kubernetes_config_map_v1":              {Version: "v1", Kind: "ConfigMap"},
+	"kubernetes_secret":                     {Version: "v1", Kind: "Secret"},
+	"kubernetes_secret_v1":                  {Version: "v1", Kind: "Secret"},
+	"kubernetes_pod":                        {Version: "v1", Kind: "Pod"},
+	"kubernetes_pod_v1":                     {Version: "v1", Kind: "Pod"},
+	"kubernetes_endpoints":                  {Version: "v1", Kind: "Endpoints"},
+	"kubernetes_endpoints_v1":               {Version: "v1", Kind: "Endpoints"},
+	"kubernetes_limit_range":                {Version: "v1", Kind: "LimitRange"},
+	"kubernetes_limit_range_v1":             {Version: "v1", Kind: "LimitRange"},
+	"kubernetes_persistent_volume":          {Version: "v1", Kind: "PersistentVolume"},
+	"kubernetes_persistent_volume_v1":       {Version: "v1", Kind: "PersistentVolume"},
+	"kubernetes_persistent_volume_claim":    {Version: "v1", Kind: "PersistentVolumeClaim"},
+	"kubernetes_persistent_volume_claim_v1": {Version: "v1", Kind: "PersistentVolumeClaim"},
+	"kubernetes_replication_controller":     {Version: "v1", Kind: "ReplicationController"},
+	"kubernetes_replication_controller_v1":  {Version: "v1", Kind: "ReplicationController"},
+	"kubernetes_resource_quota":             {Version: "v1", Kind: "ResourceQuota"},
+	"kubernetes_resource_quota_v1":          {Version: "v1", Kind: "ResourceQuota"},
+
+	// apps
+	"kubernetes_deployment":      {Group: "apps", Version: "v1", Kind: "Deployment"},
+	"kubernetes_deployment_v1":   {Group: "apps", Version: "v1", Kind: "Deployment"},
+	"kubernetes_daemonset":       {Group: "apps", Version: "v1", Kind: "DaemonSet"},
+	"kubernetes_daemon_set_v1":   {Group: "apps", Version: "v1", Kind: "DaemonSet"},
+	"kubernetes_stateful_set":    {Group: "apps", Version: "v1", Kind: "StatefulSet"},
+	"kubernetes_stateful_set_v1": {Group: "apps", Version: "v1", Kind: "StatefulSet"},
+
+	// batch
+	"kubernetes_job":         {Group: "batch", Version: "v1", Kind: "Job"},
+	"kubernetes_job_v1":      {Group: "batch", Version: "v1", Kind: "Job"},
+	"kubernetes_cron_job_v1": {Group: "batch", Version: "v1", Kind: "CronJob"},
+
+	// autoscaling
+	"kubernetes_horizontal_pod_autoscaler_v2": {Group: "autoscaling", Version: "v2", Kind: "HorizontalPodAutoscaler"},
+
+	// rbac
+	"kubernetes_role":                    {Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "Role"},
+	"kubernetes_role_v1":                 {Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "Role"},
+	"kubernetes_role_binding":            {Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "RoleBinding"},
+	"kubernetes_role_binding_v1":         {Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "RoleBinding"},
+	"kubernetes_cluster_role":            {Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"},
+	"kubernetes_cluster_role_v1":         {Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"},
+	"kubernetes_cluster_role_binding":    {Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRoleBinding"},
+	"kubernetes_cluster_role_binding_v1": {Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRoleBinding"},
+
+	// networking
+	"kubernetes_ingress_v1":        {Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"},
+	"kubernetes_ingress_class":     {Group: "networking.k8s.io", Version: "v1", Kind: "IngressClass"},
+	"kubernetes_ingress_class_v1":  {Group: "networking.k8s.io", Version: "v1", Kind: "IngressClass"},
+	"kubernetes_network_policy":    {Group: "networking.k8s.io", Version: "v1", Kind: "NetworkPolicy"},
+	"kubernetes_network_policy_v1": {Group: "networking.k8s.io", Version: "v1", Kind: "NetworkPolicy"},
+
+	// policy
+	"kubernetes_pod_disruption_budget_v1": {Group: "policy", Version: "v1", Kind: "PodDisruptionBudget"},
+
+	// scheduling
+	"kubernetes_priority_class":    {Group: "scheduling.k8s.io", Version: "v1", Kind: "PriorityClass"},
+	"kubernetes_priority_class_v1": {Group: "scheduling.k8s.io", Version: "v1", Kind: "PriorityClass"},
+
+	// admission control
+	"kubernetes_validating_webhook_configuration_v1": {Group: "admissionregistration.k8s.io", Version: "v1", Kind: "ValidatingWebhookConfiguration"},
+	"kubernetes_mutating_webhook_configuration":      {Group: "admissionregistration.k8s.io", Version: "v1", Kind: "MutatingWebhookConfiguration"},
+	"kubernetes_mutating_webhook_configuration_v1":   {Group: "admissionregistration.k8s.io", Version: "v1", Kind: "MutatingWebhookConfiguration"},
+
+	// storage
+	"kubernetes_storage_class":    {Group: "storage.k8s.io", Version: "v1", Kind: "StorageClass"},
+	"kubernetes_storage_class_v1": {Group: "storage.k8s.io", Version: "v1", Kind: "StorageClass"},
+	"kubernetes_csi_driver_v1":    {Group: "storage.k8s.io", Version: "v1", Kind: "CSIDriver"},
+
+	// node
+	"kubernetes_runtime_class_v1": {Group: "node.k8s.io", Version: "v1", Kind: "RuntimeClass"},
+}
+
+// TypedResourceKind returns the kind of the object managed by a typed resource of the main provider,
+// such as "kubernetes_deployment_v1", when its state can be moved to and from kubernetes_manifest.
+func TypedResourceKind(typeName string) (schema.GroupVersionKind, bool) {
+	gvk, ok := typedResourceKinds[typeName]
+	return gvk, ok
+}
+
+// TypedResourceID returns the ID of a typed resource managing the object name in namespace,
+// which is empty for cluster-wide objects.
+func TypedResourceID(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
+
+// IsKubernetesProviderAddress reports whether a provider source address, such as
+// "registry.terraform.io/hashicorp/kubernetes", is the one of this provider,
+// whatever the host and namespace it was installed from.
+func IsKubernetesProviderAddress(addr string) bool {
+	return addr == "kubernetes" || strings.HasSuffix(addr, "/kubernetes")
+}
@@ -10,30 +10,108 @@ import (
 	"fmt"
 	"strings"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // ParseResourceID processes the resource ID string and extracts
 // the values for GVK, name and (optionally) namespace of the target resource
 //
-// The expected format for the resource ID is:
-// "apiVersion=<value>,kind=<value>,name=<value>[,namespace=<value>"]
+// The following formats are supported for the resource ID:
 //
-// where 'namespace' is only required for resources that expect a namespace.
-// Example: "apiVersion=v1,kind=Secret,namespace=default,name=default-token-qgm6s"
-func ParseResourceID(id string) (schema.GroupVersionKind, string, string, error) {
+//   - "apiVersion=<value>,kind=<value>,name=<value>[,namespace=<value>]"
+//     Example: "apiVersion=v1,kind=Secret,namespace=default,name=default-token-qgm6s"
+//   - "<type>/<name> [-n <namespace>]", as accepted by kubectl, where the type is a kind or a plural,
+//     singular or short resource name, optionally followed by a version and group.
+//     Example: "deployment.apps/web -n prod", "deploy/web --namespace=prod" or "Deployment.v1.apps/web -n prod"
+//   - "[<namespace>/]<apiVersion>/<kind>/<name>"
+//     Example: "prod/apps/v1/Deployment/web" or "v1/Namespace/prod"
+//
+// The resource type is resolved using the supplied RESTMapper, which is also used to
+// check that a namespace is given only for namespaced resources, and always for them.
+func ParseResourceID(id string, mapper meta.RESTMapper) (schema.GroupVersionKind, string, string, error) {
+	mapping, name, namespace, err := parseResourceID(id, mapper)
+	if err != nil {
+		return schema.GroupVersionKind{}, "", "", err
+	}
+	return checkScope(id, mapping, name, namespace)
+}
+
+// ParseStateResourceID processes a resource ID read back from the state, in any of the formats
+// of ParseResourceID, without checking the namespace against the scope of the resource.
+// Former versions of the provider stored the namespace of cluster-scoped resources in their ID,
+// which is ignored. The namespace of namespaced resources is empty when the ID has none.
+func ParseStateResourceID(id string, mapper meta.RESTMapper) (schema.GroupVersionKind, string, string, error) {
+	mapping, name, namespace, err := parseResourceID(id, mapper)
+	if err != nil {
+		return schema.GroupVersionKind{}, "", "", err
+	}
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		namespace = ""
+	}
+	return mapping.GroupVersionKind, name, namespace, nil
+}
+
+// parseResourceID resolves the REST mapping, name and namespace of a resource ID
+func parseResourceID(id string, mapper meta.RESTMapper) (*meta.RESTMapping, string, string, error) {
+	if strings.Contains(id, ",") {
+		return parseKeyValueID(id, mapper)
+	}
+
+	var args []string
+	var namespace string
+	fields := strings.Fields(id)
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		switch {
+		case f == "-n" || f == "--namespace":
+			if i+1 == len(fields) {
+				return nil, "", "",
+					fmt.Errorf("could not parse ID: %q. Flag %s requires a value", id, f)
+			}
+			i++
+			namespace = fields[i]
+		case strings.HasPrefix(f, "--namespace="):
+			namespace = strings.TrimPrefix(f, "--namespace=")
+		case strings.HasPrefix(f, "-n="):
+			namespace = strings.TrimPrefix(f, "-n=")
+		case strings.HasPrefix(f, "-"):
+			return nil, "", "",
+				fmt.Errorf("could not parse ID: %q. Unknown flag %s", id, f)
+		default:
+			args = append(args, f)
+		}
+	}
+
+	switch {
+	case len(args) == 2 && !strings.Contains(args[0], "/") && !strings.Contains(args[1], "/"):
+		return resolveTypeAndName(id, args[0], args[1], namespace, mapper)
+	case len(args) == 1 && strings.Count(args[0], "/") == 1:
+		parts := strings.Split(args[0], "/")
+		return resolveTypeAndName(id, parts[0], parts[1], namespace, mapper)
+	case len(args) == 1 && strings.Count(args[0], "/") > 1:
+		if namespace != "" {
+			return nil, "", "",
+				fmt.Errorf("could not parse ID: %q. The namespace must be part of the path", id)
+		}
+		return resolvePath(id, strings.Split(args[0], "/"), mapper)
+	}
+	return nil, "", "",
+		fmt.Errorf("could not parse ID: %q. ID must contain apiVersion, kind, and name", id)
+}
+
+func parseKeyValueID(id string, mapper meta.RESTMapper) (*meta.RESTMapping, string, string, error) {
 	parts := strings.Split(id, ",")
 	if len(parts) < 3 || len(parts) > 4 {
-		return schema.GroupVersionKind{}, "", "",
+		return nil, "", "",
 			fmt.Errorf("could not parse ID: %q. ID must contain apiVersion, kind, and name", id)
 	}
 
-	namespace := "default"
-	var apiVersion, kind, name string
+	var apiVersion, kind, name, namespace string
 	for _, p := range parts {
 		pp := strings.Split(p, "=")
 		if len(pp) != 2 {
-			return schema.GroupVersionKind{}, "", "",
+			return nil, "", "",
 				fmt.Errorf("could not parse ID: %q. ID must be in key=value format", id)
 		}
 		key := pp[0]
@@ -48,11 +126,118 @@ func ParseResourceID(id string) (schema.GroupVersionKind, string, string, error)
 		case "namespace":
 			namespace = val
 		default:
-			return schema.GroupVersionKind{}, "", "",
+			return nil, "", "",
 				fmt.Errorf("could not parse ID: %q. ID contained unknown key %q", id, key)
 		}
 	}
 
 	gvk := schema.FromAPIVersionAndKind(apiVersion, kind)
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, "", "",
+			fmt.Errorf("could not resolve ID: %q. %s", id, err)
+	}
+	return mapping, name, namespace, nil
+}
+
+// resolveTypeAndName looks up a kubectl style resource type, the same way kubectl does
+func resolveTypeAndName(id, resourceOrKind, name, namespace string, mapper meta.RESTMapper) (*meta.RESTMapping, string, string, error) {
+	if resourceOrKind == "" || name == "" {
+		return nil, "", "",
+			fmt.Errorf("could not parse ID: %q. ID must contain a resource type and name", id)
+	}
+
+	var mapping *meta.RESTMapping
+	var err error
+
+	// first try to interpret the type as a (plural, singular or short) resource name
+	fullySpecifiedGVR, groupResource := schema.ParseResourceArg(strings.ToLower(resourceOrKind))
+	gvk := schema.GroupVersionKind{}
+	if fullySpecifiedGVR != nil {
+		gvk, _ = mapper.KindFor(*fullySpecifiedGVR)
+	}
+	if gvk.Empty() {
+		gvk, _ = mapper.KindFor(groupResource.WithVersion(""))
+	}
+	if !gvk.Empty() {
+		mapping, err = mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	} else {
+		// then as a kind
+		fullySpecifiedGVK, groupKind := schema.ParseKindArg(resourceOrKind)
+		if fullySpecifiedGVK != nil {
+			mapping, err = mapper.RESTMapping(fullySpecifiedGVK.GroupKind(), fullySpecifiedGVK.Version)
+		}
+		if mapping == nil {
+			mapping, err = mapper.RESTMapping(groupKind)
+		}
+	}
+	if err != nil {
+		return nil, "", "",
+			fmt.Errorf("could not resolve ID: %q. Unknown resource type %q: %s", id, resourceOrKind, err)
+	}
+	return mapping, name, namespace, nil
+}
+
+// resolvePath looks up a resource given as "[<namespace>/]<apiVersion>/<kind>/<name>"
+func resolvePath(id string, parts []string, mapper meta.RESTMapper) (*meta.RESTMapping, string, string, error) {
+	for _, p := range parts {
+		if p == "" {
+			return nil, "", "",
+				fmt.Errorf("could not parse ID: %q. ID contains an empty path segment", id)
+		}
+	}
+	n := len(parts)
+	switch n {
+	case 3:
+		// core group, cluster-scoped: v1/Namespace/prod
+		return resolveGVK(id, schema.GroupVersionKind{Version: parts[0], Kind: parts[1]}, parts[2], "", mapper)
+	case 4:
+		// either group/version/kind/name or namespace/version/kind/name for the core group
+		gvk := schema.GroupVersionKind{Group: parts[0], Version: parts[1], Kind: parts[2]}
+		if _, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version); err == nil {
+			return resolveGVK(id, gvk, parts[3], "", mapper)
+		}
+		return resolveGVK(id, schema.GroupVersionKind{Version: parts[1], Kind: parts[2]}, parts[3], parts[0], mapper)
+	case 5:
+		gvk := schema.GroupVersionKind{Group: parts[1], Version: parts[2], Kind: parts[3]}
+		return resolveGVK(id, gvk, parts[4], parts[0], mapper)
+	}
+	return nil, "", "",
+		fmt.Errorf("could not parse ID: %q. ID must contain apiVersion, kind, and name", id)
+}
+
+func resolveGVK(id string, gvk schema.GroupVersionKind, name, namespace string, mapper meta.RESTMapper) (*meta.RESTMapping, string, string, error) {
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, "", "",
+			fmt.Errorf("could not resolve ID: %q. %s", id, err)
+	}
+	return mapping, name, namespace, nil
+}
+
+// checkScope makes sure a namespace is given if and only if the resource is namespaced
+func checkScope(id string, mapping *meta.RESTMapping, name, namespace string) (schema.GroupVersionKind, string, string, error) {
+	gvk := mapping.GroupVersionKind
+	namespaced := mapping.Scope.Name() == meta.RESTScopeNameNamespace
+	if namespaced && namespace == "" {
+		return schema.GroupVersionKind{}, "", "",
+			fmt.Errorf("invalid ID: %q. %s is a namespaced resource, the ID must include a namespace", id, gvk.Kind)
+	}
+	if !namespaced && namespace != "" {
+		return schema.GroupVersionKind{}, "", "",
+			fmt.Errorf("invalid ID: %q. %s is a cluster-scoped resource, the ID must not include a namespace", id, gvk.Kind)
+	}
 	return gvk, name, namespace, nil
 }
+
+// ParseClusterImportID splits an import ID of the form "<context>//<id>" into the name of a context
+// of the kube config, whose cluster the resource is imported from, and the ID of the resource.
+// The context is empty for IDs without one, which import from the cluster of the provider configuration.
+// Object names cannot contain a slash, so "//" is never part of the ID of a resource itself.
+func ParseClusterImportID(id string) (string, string) {
+	i := strings.Index(id, "//")
+	if i <= 0 {
+		return "", id
+	}
+	return id[:i], id[i+2:]
+}
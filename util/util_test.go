@@ -7,9 +7,43 @@ import (
 	"fmt"
 	"testing"
 
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/restmapper"
+	k8stesting "k8s.io/client-go/testing"
 )
 
+func newTestRESTMapper(t *testing.T) meta.RESTMapper {
+	dc := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{Resources: []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "configmaps", SingularName: "configmap", Namespaced: true, Kind: "ConfigMap", ShortNames: []string{"cm"}},
+				{Name: "namespaces", SingularName: "namespace", Namespaced: false, Kind: "Namespace", ShortNames: []string{"ns"}},
+			},
+		},
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "deployments", SingularName: "deployment", Namespaced: true, Kind: "Deployment", ShortNames: []string{"deploy"}},
+			},
+		},
+		{
+			GroupVersion: "rbac.authorization.k8s.io/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "clusterroles", SingularName: "clusterrole", Namespaced: false, Kind: "ClusterRole"},
+			},
+		},
+	}}}
+	agr, err := restmapper.GetAPIGroupResources(dc)
+	if err != nil {
+		t.Fatalf("failed to get API group resources: %v", err)
+	}
+	return restmapper.NewShortcutExpander(restmapper.NewDiscoveryRESTMapper(agr), dc)
+}
+
 func TestParseResourceID(t *testing.T) {
 	cases := []struct {
 		id        string
@@ -19,10 +53,8 @@ func TestParseResourceID(t *testing.T) {
 		err       error
 	}{
 		{
-			id:        "apiVersion=v1,kind=ConfigMap,name=test",
-			namespace: "default",
-			name:      "test",
-			gvk:       schema.FromAPIVersionAndKind("v1", "ConfigMap"),
+			id:  "apiVersion=v1,kind=ConfigMap,name=test",
+			err: fmt.Errorf(`invalid ID: "apiVersion=v1,kind=ConfigMap,name=test". ConfigMap is a namespaced resource, the ID must include a namespace`),
 		},
 		{
 			id:        "apiVersion=v1,kind=ConfigMap,name=test,namespace=kube-system",
@@ -48,13 +80,156 @@ func TestParseResourceID(t *testing.T) {
 			id:  "junk",
 			err: fmt.Errorf(`could not parse ID: "junk". ID must contain apiVersion, kind, and name`),
 		},
+		{
+			id:   "apiVersion=v1,kind=Namespace,name=test",
+			name: "test",
+			gvk:  schema.FromAPIVersionAndKind("v1", "Namespace"),
+		},
+		{
+			id:  "apiVersion=v1,kind=Namespace,name=test,namespace=default",
+			err: fmt.Errorf(`invalid ID: "apiVersion=v1,kind=Namespace,name=test,namespace=default". Namespace is a cluster-scoped resource, the ID must not include a namespace`),
+		},
+		{
+			id:        "deployment.apps/web -n prod",
+			namespace: "prod",
+			name:      "web",
+			gvk:       schema.FromAPIVersionAndKind("apps/v1", "Deployment"),
+		},
+		{
+			id:        "deploy/web --namespace=prod",
+			namespace: "prod",
+			name:      "web",
+			gvk:       schema.FromAPIVersionAndKind("apps/v1", "Deployment"),
+		},
+		{
+			id:        "deployments web --namespace prod",
+			namespace: "prod",
+			name:      "web",
+			gvk:       schema.FromAPIVersionAndKind("apps/v1", "Deployment"),
+		},
+		{
+			id:        "Deployment.v1.apps/web -n prod",
+			namespace: "prod",
+			name:      "web",
+			gvk:       schema.FromAPIVersionAndKind("apps/v1", "Deployment"),
+		},
+		{
+			id:        "cm/test -n kube-system",
+			namespace: "kube-system",
+			name:      "test",
+			gvk:       schema.FromAPIVersionAndKind("v1", "ConfigMap"),
+		},
+		{
+			id:   "clusterrole.rbac.authorization.k8s.io/admin",
+			name: "admin",
+			gvk:  schema.FromAPIVersionAndKind("rbac.authorization.k8s.io/v1", "ClusterRole"),
+		},
+		{
+			id:        "prod/apps/v1/Deployment/web",
+			namespace: "prod",
+			name:      "web",
+			gvk:       schema.FromAPIVersionAndKind("apps/v1", "Deployment"),
+		},
+		{
+			id:        "prod/v1/ConfigMap/test",
+			namespace: "prod",
+			name:      "test",
+			gvk:       schema.FromAPIVersionAndKind("v1", "ConfigMap"),
+		},
+		{
+			id:   "rbac.authorization.k8s.io/v1/ClusterRole/admin",
+			name: "admin",
+			gvk:  schema.FromAPIVersionAndKind("rbac.authorization.k8s.io/v1", "ClusterRole"),
+		},
+		{
+			id:   "v1/Namespace/prod",
+			name: "prod",
+			gvk:  schema.FromAPIVersionAndKind("v1", "Namespace"),
+		},
+		{
+			id:  "deploy/web",
+			err: fmt.Errorf(`invalid ID: "deploy/web". Deployment is a namespaced resource, the ID must include a namespace`),
+		},
+		{
+			id:  "ns/prod -n prod",
+			err: fmt.Errorf(`invalid ID: "ns/prod -n prod". Namespace is a cluster-scoped resource, the ID must not include a namespace`),
+		},
+		{
+			id:  "prod/v1/Namespace/test",
+			err: fmt.Errorf(`invalid ID: "prod/v1/Namespace/test". Namespace is a cluster-scoped resource, the ID must not include a namespace`),
+		},
+		{
+			id:  "widgets/test -n prod",
+			err: fmt.Errorf(`could not resolve ID: "widgets/test -n prod". Unknown resource type "widgets": no matches for kind "widgets" in group ""`),
+		},
+		{
+			id:  "deploy/web -n",
+			err: fmt.Errorf(`could not parse ID: "deploy/web -n". Flag -n requires a value`),
+		},
+	}
+
+	mapper := newTestRESTMapper(t)
+
+	for _, tc := range cases {
+		t.Run(tc.id, func(t *testing.T) {
+			gvk, n, ns, err := ParseResourceID(tc.id, mapper)
+			if err != nil && (tc.err == nil || tc.err.Error() != err.Error()) {
+				t.Errorf("expected error %v got %q", tc.err, err)
+			}
+			if err == nil && tc.err != nil {
+				t.Errorf("expected error %q", tc.err)
+			}
+			if tc.namespace != ns {
+				t.Errorf("expected namespace %q got %q", tc.namespace, ns)
+			}
+			if tc.name != n {
+				t.Errorf("expected name %q got %q", tc.name, n)
+			}
+			if tc.gvk != gvk {
+				t.Errorf("expected GroupVersionKind %#v got %#v", tc.gvk, gvk)
+			}
+		})
+	}
+}
+
+func TestParseStateResourceID(t *testing.T) {
+	cases := []struct {
+		id        string
+		namespace string
+		name      string
+		gvk       schema.GroupVersionKind
+	}{
+		{
+			// IDs of cluster-scoped resources stored by former versions of the provider
+			id:   "apiVersion=v1,kind=Namespace,name=test,namespace=default",
+			name: "test",
+			gvk:  schema.FromAPIVersionAndKind("v1", "Namespace"),
+		},
+		{
+			id:   "apiVersion=rbac.authorization.k8s.io/v1,kind=ClusterRole,name=admin,namespace=prod",
+			name: "admin",
+			gvk:  schema.FromAPIVersionAndKind("rbac.authorization.k8s.io/v1", "ClusterRole"),
+		},
+		{
+			id:   "apiVersion=apps/v1,kind=Deployment,name=app",
+			name: "app",
+			gvk:  schema.FromAPIVersionAndKind("apps/v1", "Deployment"),
+		},
+		{
+			id:        "apiVersion=apps/v1,kind=Deployment,name=app,namespace=test",
+			namespace: "test",
+			name:      "app",
+			gvk:       schema.FromAPIVersionAndKind("apps/v1", "Deployment"),
+		},
 	}
 
+	mapper := newTestRESTMapper(t)
+
 	for _, tc := range cases {
 		t.Run(tc.id, func(t *testing.T) {
-			gvk, n, ns, err := ParseResourceID(tc.id)
-			if err != nil && tc.err.Error() != err.Error() {
-				t.Errorf("expected error %q got %q", tc.err, err)
+			gvk, n, ns, err := ParseStateResourceID(tc.id, mapper)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
 			}
 			if tc.namespace != ns {
 				t.Errorf("expected namespace %q got %q", tc.namespace, ns)
@@ -68,3 +243,24 @@ func TestParseResourceID(t *testing.T) {
 		})
 	}
 }
+
+func TestParseClusterImportID(t *testing.T) {
+	cases := map[string]struct {
+		context string
+		id      string
+	}{
+		"default/test":                          {id: "default/test"},
+		"staging//default/test":                 {context: "staging", id: "default/test"},
+		"arn:aws:eks:eu-west-1:1:cluster/a//ns": {context: "arn:aws:eks:eu-west-1:1:cluster/a", id: "ns"},
+		"staging//apiVersion=v1,kind=ConfigMap,namespace=default,name=test": {context: "staging", id: "apiVersion=v1,kind=ConfigMap,namespace=default,name=test"},
+		"//default/test": {id: "//default/test"},
+	}
+	for in, tc := range cases {
+		t.Run(in, func(t *testing.T) {
+			context, id := ParseClusterImportID(in)
+			if context != tc.context || id != tc.id {
+				t.Fatalf("expected context %q and ID %q, got %q and %q", tc.context, tc.id, context, id)
+			}
+		})
+	}
+}